@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -22,11 +23,34 @@ func main() {
 	// Parse command line flags
 	configPath := flag.String("config", "config.json", "Path to configuration file")
 	headless := flag.Bool("headless", false, "Run in headless mode (no UI)")
-	exportStats := flag.Bool("exportStats", false, "Export statistics to CSV and JSON")
+	exportStats := flag.Bool("exportStats", false, "Export statistics to CSV and JSON, and reproduction and death events and trait correlations to CSV")
 	duration := flag.Float64("duration", 60.0, "Simulation duration in seconds (headless mode only)")
+	maxSteps := flag.Int64("maxSteps", 0, "Stop after this many simulation steps, overriding -duration (headless mode only)")
 	cpuprofile := flag.String("cpuprofile", "", "Write CPU profile to file")
+	scenarioPath := flag.String("scenario", "", "Load organisms and chemical sources from a scenario file")
+	transplantPath := flag.String("transplant", "", "Add the organisms from a lineage file to the starting population")
+	verifyDeterminism := flag.Bool("verifyDeterminism", false, "Run the seeded config twice for -duration and exit nonzero if the final states differ")
+	quiet := flag.Bool("quiet", false, "Suppress the progress bar in headless mode")
+	replMode := flag.Bool("repl", false, "Pause a headless run for interactive inspection (implies -headless)")
+	replInterval := flag.Int("replInterval", 600, "Number of steps between REPL prompts")
+	timelinePath := flag.String("timeline", "", "Apply scripted chemical source changes from a timeline file")
+	heatmapOut := flag.String("heatmapOut", "", "Write where organisms spent their time to a PNG or CSV file at the end (headless mode only)")
+	timelapseDir := flag.String("timelapse", "", "Save annotated SVG frames of the run to this directory (implies -headless)")
+	timelapseFrames := flag.Int("timelapseFrames", 100, "Number of frames to save with -timelapse, evenly spaced over the run")
+	until := flag.String("until", "", "Stop a headless run early once this condition holds, e.g. \"generation >= 50\" or \"lineageShare > 0.8 or preferenceChange < 0.1\"")
+	invasionSpec := flag.String("invasion", "", "Run a headless invasion experiment for -duration, seeding a resident population with a few mutants, e.g. \"MetabolicRate,0.5,0.05,0.1\" (trait,resident,mutant[,fraction]); starts from -scenario if given")
+	statsBucketSeconds := flag.Float64("statsBucketSeconds", 0, "With -exportStats, write the mean, min and max of each statistic over buckets of this many simulation seconds instead of every sample")
+	loadStatePath := flag.String("loadState", "", "Resume a run saved with -saveState, using the config it was saved with; -duration and -maxSteps count from the start of the original run")
+	saveStatePath := flag.String("saveState", "", "Save the run's full state to this file at the end, to resume with -loadState (headless mode only)")
+	exportLineagePath := flag.String("exportLineage", "", "Write the largest surviving lineage to this file at the end, to seed another run with -transplant (headless mode only)")
+	tournamentSpec := flag.String("tournament", "", "Run a seeded headless tournament between two foraging strategies for -duration, e.g. \"lockOn,closestPreference\", and report which dominates")
 	flag.Parse()
 
+	// The REPL reads from stdin, and timelapses are drawn offscreen, so neither needs a window
+	if *replMode || *timelapseDir != "" {
+		*headless = true
+	}
+
 	// Start CPU profiling if requested
 	if *cpuprofile != "" {
 		f, err := os.Create(*cpuprofile)
@@ -56,85 +80,407 @@ func main() {
 		}
 	}
 
-	// Initialize the world
-	world := world.NewWorld(cfg)
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	for _, warning := range cfg.Warnings() {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+
+	// Check that two seeded runs end in the same state, then exit
+	if *verifyDeterminism {
+		if cfg.RandomSeed == 0 {
+			cfg.RandomSeed = 1
+		}
+		steps := int(*duration / (1.0 / 60.0))
+		fmt.Printf("Verifying determinism: 2 runs of %d steps with seed %d\n", steps, cfg.RandomSeed)
+		if err := simulation.VerifyDeterminism(cfg, steps); err != nil {
+			fmt.Printf("Determinism check FAILED: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Determinism check passed")
+		return
+	}
+
+	// Pit two foraging strategies against each other, then exit
+	if *tournamentSpec != "" {
+		strategyA, strategyB, err := simulation.ParseTournament(*tournamentSpec)
+		if err != nil {
+			log.Fatalf("Invalid -tournament: %v", err)
+		}
+		if cfg.RandomSeed == 0 {
+			cfg.RandomSeed = 1
+		}
+		steps := int64(*duration / (1.0 / 60.0))
+		fmt.Printf("Tournament: %s vs %s, %d steps with seed %d\n", strategyA, strategyB, steps, cfg.RandomSeed)
+		tournament := simulation.RunTournament(cfg, strategyA, strategyB, steps, 600)
+		for _, sample := range tournament.Samples {
+			fmt.Printf("  t=%7.1fs  population %4d  %s %5.1f%%  %s %5.1f%%\n", sample.Time, sample.Population,
+				strategyA, sample.Shares[0]*100, strategyB, sample.Shares[1]*100)
+		}
+		if leader := tournament.Leader(); leader >= 0 {
+			fmt.Printf("Dominant strategy: %s\n", tournament.Groups[leader].Strategy)
+		} else {
+			fmt.Println("No dominant strategy: the shares are tied")
+		}
+		return
+	}
+
+	// Test whether a mutant trait value can invade a resident population, then exit
+	if *invasionSpec != "" {
+		setup, err := simulation.ParseInvasion(*invasionSpec)
+		if err != nil {
+			log.Fatalf("Invalid -invasion: %v", err)
+		}
+		if *scenarioPath != "" {
+			scenario, err := world.LoadScenarioFromFile(*scenarioPath)
+			if err != nil {
+				log.Fatalf("Failed to load scenario: %v", err)
+			}
+			setup.Start = &scenario
+		}
+		steps := int64(*duration / (1.0 / 60.0))
+		fmt.Printf("Invasion: %s %g into residents at %g, %d steps\n", setup.Trait, setup.MutantValue, setup.ResidentValue, steps)
+		invasion, err := simulation.RunInvasion(cfg, setup, steps, 600)
+		if err != nil {
+			log.Fatalf("Invasion failed: %v", err)
+		}
+		for _, sample := range invasion.Samples {
+			fmt.Printf("  t=%7.1fs  population %4d  mutants %5.1f%%\n", sample.Time, sample.Population, sample.MutantShare*100)
+		}
+		if invasion.Invades() {
+			fmt.Printf("Mutant invades: share grew by %.1f points\n", invasion.ShareChange()*100)
+		} else {
+			fmt.Printf("Mutant does not invade: share changed by %.1f points\n", invasion.ShareChange()*100)
+		}
+		return
+	}
+
+	// Resume a saved run, or initialize a new world and simulator
+	var simulator *simulation.Simulator
+	if *loadStatePath != "" {
+		if *scenarioPath != "" || *transplantPath != "" {
+			log.Fatal("-loadState can't be combined with -scenario or -transplant")
+		}
+		simulator, err = simulation.LoadState(*loadStatePath)
+		if err != nil {
+			log.Fatalf("Failed to load state: %v", err)
+		}
+		cfg = simulator.Config
+		fmt.Printf("Resumed from %s at %.1fs (step %d)\n", *loadStatePath, simulator.Time, simulator.StepCount)
+	} else {
+		world := world.NewWorld(cfg)
+
+		// Replace the random population with a saved scenario if requested
+		if *scenarioPath != "" {
+			if err := world.LoadScenario(*scenarioPath); err != nil {
+				log.Fatalf("Failed to load scenario: %v", err)
+			}
+			fmt.Printf("Loaded scenario from: %s\n", *scenarioPath)
+		}
+
+		// Seed the world with a lineage from another run alongside the residents
+		if *transplantPath != "" {
+			added, err := world.LoadLineage(*transplantPath)
+			if err != nil {
+				log.Fatalf("Failed to load lineage: %v", err)
+			}
+			fmt.Printf("Transplanted %d organisms from: %s\n", added, *transplantPath)
+		}
+
+		simulator = simulation.NewSimulator(world, cfg)
+	}
+	world := simulator.World
+
+	// Schedule scripted environmental changes if requested
+	if *timelinePath != "" {
+		timeline, err := simulation.LoadEventTimeline(*timelinePath)
+		if err != nil {
+			log.Fatalf("Failed to load timeline: %v", err)
+		}
+		simulator.Timeline = timeline
+		fmt.Printf("Loaded %d timeline events from: %s\n", len(timeline.Events), *timelinePath)
+	}
 
-	// Initialize the simulator
-	simulator := simulation.NewSimulator(world, cfg)
+	// Stop early on a custom condition if requested
+	if *until != "" {
+		simulator.EndCondition, err = simulation.ParseEndCondition(*until)
+		if err != nil {
+			log.Fatalf("Invalid -until condition: %v", err)
+		}
+	}
 
 	// Initialize the renderer if not in headless mode
 	if !*headless {
-		gameRenderer := renderer.NewRenderer(world, simulator, cfg)
+		err := runWindowed(world, simulator, cfg)
+		if !windowedRunFailed(err) {
+			return
+		}
 
-		// Set up Ebiten game
-		ebiten.SetWindowSize(cfg.Render.WindowWidth, cfg.Render.WindowHeight)
-		ebiten.SetWindowTitle("Evolution Simulator")
-		ebiten.SetMaxTPS(cfg.Render.FrameRate)
+		// No usable display (e.g. a CI machine): fall back to a headless run
+		fmt.Printf("Could not start the window (%v); falling back to headless mode\n", err)
+		*headless = true
+	}
+
+	// Headless mode for batch processing or testing
+	fmt.Println("Running in headless mode")
+	var repl *simulation.REPL
+	if *replMode {
+		repl = simulation.NewREPL(simulator, os.Stdin, os.Stdout)
+		fmt.Println("REPL enabled; type \"help\" at the prompt for commands")
+	}
 
-		// Start the game
-		if err := ebiten.RunGame(gameRenderer); err != nil {
-			log.Fatalf("Failed to run game: %v", err)
+	// Stream statistics to files as they're sampled, and log events for export at the end
+	var statsSink simulation.StatsSink = simulation.NopStatsSink{}
+	eventsPath, correlationsPath := "", ""
+	if *statsBucketSeconds < 0 {
+		log.Fatalf("Invalid -statsBucketSeconds: %g; must be positive, or 0 to export every sample", *statsBucketSeconds)
+	}
+	if *exportStats {
+		timestamp := time.Now().Format("20060102-150405")
+		statsSink, err = openStatsFiles(timestamp, *statsBucketSeconds)
+		if err != nil {
+			log.Fatalf("Failed to open statistics files: %v", err)
 		}
-	} else {
-		// Headless mode for batch processing or testing
-		fmt.Println("Running in headless mode")
-		runHeadless(simulator, *duration, *exportStats)
+		eventsPath = fmt.Sprintf("events_%s.csv", timestamp)
+		correlationsPath = fmt.Sprintf("correlations_%s.csv", timestamp)
+		simulator.RecordEvents = true
+	}
+
+	// Record habitat usage over the run if requested
+	if *heatmapOut != "" {
+		simulator.Occupancy = simulation.NewOccupancyGrid(world.GetBounds(), simulation.DefaultOccupancyCellSize)
+	}
+
+	// Save frames of the run for a timelapse if requested
+	var timelapse *simulation.Timelapse
+	if *timelapseDir != "" {
+		steps := *maxSteps
+		if steps <= 0 {
+			steps = int64(*duration / simulator.TimeStep)
+		}
+		timelapse, err = simulation.NewTimelapse(*timelapseDir, steps, *timelapseFrames)
+		if err != nil {
+			log.Fatalf("Failed to set up timelapse: %v", err)
+		}
+	}
+
+	runHeadless(simulator, *duration, *maxSteps, statsSink, eventsPath, *quiet, repl, *replInterval, timelapse)
+
+	if *saveStatePath != "" {
+		if err := simulation.SaveState(simulator, *saveStatePath); err != nil {
+			fmt.Printf("Failed to save state: %v\n", err)
+		} else {
+			fmt.Printf("Saved state at %.1fs (step %d) to %s\n", simulator.Time, simulator.StepCount, *saveStatePath)
+		}
+	}
+
+	// Save the most successful lineage for a founder transplant into another run
+	if *exportLineagePath != "" {
+		if rootID, count := simulator.World.LargestLineage(); count == 0 {
+			fmt.Println("No surviving lineage to export")
+		} else if err := simulator.World.ExportLineage(rootID, *exportLineagePath); err != nil {
+			fmt.Printf("Failed to export lineage: %v\n", err)
+		} else {
+			fmt.Printf("Exported lineage %d (%d organisms) to %s\n", rootID, count, *exportLineagePath)
+		}
+	}
+
+	if timelapse != nil {
+		fmt.Printf("Saved %d timelapse frames to %s\n", timelapse.Captured(), *timelapseDir)
+	}
+
+	// Export how the final population's traits vary together
+	if correlationsPath != "" {
+		correlations := simulation.TraitCorrelationMatrix(simulator.World.GetOrganisms())
+		if err := correlations.ExportCSV(correlationsPath); err != nil {
+			fmt.Printf("Failed to export trait correlations: %v\n", err)
+		} else {
+			fmt.Printf("Exported trait correlations to %s\n", correlationsPath)
+		}
+	}
+
+	if *heatmapOut != "" {
+		if err := simulator.Occupancy.Export(*heatmapOut); err != nil {
+			fmt.Printf("Failed to export occupancy heatmap: %v\n", err)
+		} else {
+			fmt.Printf("Exported occupancy heatmap to %s\n", *heatmapOut)
+		}
+	}
+}
+
+// openStatsFiles opens a CSV and a JSON statistics file named with the timestamp
+func openStatsFiles(timestamp string, bucketSeconds float64) (simulation.StatsSink, error) {
+	csvPath := fmt.Sprintf("stats_%s.csv", timestamp)
+	jsonPath := fmt.Sprintf("stats_%s.json", timestamp)
+	if bucketSeconds > 0 {
+		return openBucketedStatsFiles(csvPath, jsonPath, bucketSeconds)
+	}
+
+	csvSink, err := simulation.NewCSVStatsSink(csvPath)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonSink, err := simulation.NewJSONStatsSink(jsonPath)
+	if err != nil {
+		csvSink.Close()
+		return nil, err
+	}
+
+	fmt.Printf("Writing statistics to %s and %s\n", csvPath, jsonPath)
+	return simulation.NewMultiStatsSink(csvSink, jsonSink), nil
+}
+
+// openBucketedStatsFiles opens CSV and JSON files that receive statistics
+// downsampled into buckets of bucketSeconds of simulation time
+func openBucketedStatsFiles(csvPath, jsonPath string, bucketSeconds float64) (simulation.StatsSink, error) {
+	csvWriter, err := simulation.NewCSVBucketWriter(csvPath)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonWriter, err := simulation.NewJSONBucketWriter(jsonPath)
+	if err != nil {
+		csvWriter.Close()
+		return nil, err
+	}
+
+	fmt.Printf("Writing statistics in %gs buckets to %s and %s\n", bucketSeconds, csvPath, jsonPath)
+	return simulation.NewDownsamplingStatsSink(bucketSeconds, csvWriter, jsonWriter), nil
+}
+
+// runWindowed runs the simulation with the Ebiten renderer until the window is closed
+func runWindowed(world *world.World, simulator *simulation.Simulator, cfg config.SimulationConfig) error {
+	gameRenderer := renderer.NewRenderer(world, simulator, cfg)
+
+	// Set up Ebiten game
+	ebiten.SetWindowSize(cfg.Render.WindowWidth, cfg.Render.WindowHeight)
+	ebiten.SetWindowTitle("Evolution Simulator")
+	if cfg.Render.Resizable {
+		ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	}
+	ebiten.SetMaxTPS(cfg.Render.FrameRate)
+
+	// Start the game
+	return ebiten.RunGame(gameRenderer)
 }
 
-// runHeadless executes the simulation without visualization
-func runHeadless(simulator *simulation.Simulator, duration float64, exportStats bool) {
+// windowedRunFailed reports whether the error returned from the windowed run means
+// the window could not be used, in which case the simulation should run headless.
+// The renderer never stops the game loop with an error of its own, so any error
+// other than a normal termination comes from initializing the window.
+func windowedRunFailed(err error) bool {
+	return err != nil && !errors.Is(err, ebiten.Termination)
+}
+
+// progressRedrawInterval is how often the headless progress bar is redrawn
+const progressRedrawInterval = 200 * time.Millisecond
+
+// runHeadless executes the simulation without visualization for maxSteps steps,
+// or for duration seconds of simulation time if maxSteps is 0.
+// Unless quiet is set, a progress bar is redrawn in place as the run advances.
+// If repl is non-nil, the run pauses for commands every replInterval steps.
+// If timelapse is non-nil, it is given the chance to save a frame after every step.
+// Statistics are written to sink about once a second of simulation time, and the
+// event log is exported to eventsPath at the end unless it is empty.
+func runHeadless(simulator *simulation.Simulator, duration float64, maxSteps int64, sink simulation.StatsSink, eventsPath string, quiet bool, repl *simulation.REPL, replInterval int, timelapse *simulation.Timelapse) {
 	// Calculate the number of steps needed
-	// This assumes timestep is 1/60 (default)
-	steps := int(duration / simulator.TimeStep)
+	steps := maxSteps
+	if steps <= 0 {
+		steps = int64(duration / simulator.TimeStep)
+	}
 
-	// Stats collection
-	var stats []simulation.SimulationStats
 	startTime := time.Now()
 
 	// Progress reporting
-	reportInterval := steps / 10
-	if reportInterval < 1 {
-		reportInterval = 1
+	var lastRedraw time.Time
+	drawProgress := func() {
+		fmt.Printf("\r%s", simulation.FormatProgress(int(simulator.StepCount), int(steps), time.Since(startTime)))
+		lastRedraw = time.Now()
 	}
 
-	// Run the simulation
-	for i := 0; i < steps; i++ {
+	// Run the simulation until the step count is reached, including steps taken from the REPL
+	heatDeath, endConditionMet := false, false
+	for simulator.StepCount < steps {
 		simulator.Step()
 
+		// Stop early once the world's energy has collapsed for good
+		if simulator.World.HeatDeath() {
+			heatDeath = true
+			break
+		}
+
+		// Or once the experiment's own stopping rule holds
+		if simulator.EndConditionMet() {
+			endConditionMet = true
+			break
+		}
+
 		// Collect stats every 60 steps (approximately once per second)
-		if i%60 == 0 {
+		if simulator.StepCount%60 == 1 {
 			stat := simulator.CollectStats()
 			stat.RealTimeElapsed = time.Since(startTime)
-			stats = append(stats, stat)
+			if err := sink.Write(stat); err != nil {
+				// Keep the run going, but stop trying to record it
+				fmt.Printf("\nFailed to write statistics, no more will be recorded: %v\n", err)
+				sink.Close()
+				sink = simulation.NopStatsSink{}
+			}
+		}
+
+		// Save a timelapse frame when one is due
+		if timelapse != nil {
+			if err := timelapse.Capture(simulator); err != nil {
+				fmt.Printf("\nFailed to save timelapse frame, no more will be saved: %v\n", err)
+				timelapse = nil
+			}
 		}
 
 		// Report progress
-		if i%reportInterval == 0 {
-			progress := float64(i) / float64(steps) * 100
-			fmt.Printf("Simulation progress: %.1f%% (time: %.2fs)\n", progress, simulator.Time)
+		if !quiet && time.Since(lastRedraw) >= progressRedrawInterval {
+			drawProgress()
+		}
+
+		// Drop into the REPL periodically
+		if repl != nil && replInterval > 0 && simulator.StepCount%int64(replInterval) == 0 {
+			if !quiet {
+				fmt.Println()
+			}
+			if repl.Run() {
+				break
+			}
 		}
 	}
 
-	fmt.Printf("Simulation completed in %.2f seconds (simulation time: %.2fs)\n",
-		time.Since(startTime).Seconds(), simulator.Time)
+	// Finish the progress bar on its own line
+	if !quiet {
+		drawProgress()
+		fmt.Println()
+	}
 
-	// Export statistics if requested
-	if exportStats && len(stats) > 0 {
-		timestamp := time.Now().Format("20060102-150405")
-		csvPath := fmt.Sprintf("stats_%s.csv", timestamp)
-		jsonPath := fmt.Sprintf("stats_%s.json", timestamp)
+	if endConditionMet {
+		fmt.Printf("End condition %q met at step %d; stopping early\n", simulator.EndCondition.Expression, simulator.StepCount)
+	}
+	if heatDeath {
+		fmt.Printf("Heat death at step %d: system energy collapsed and sources can't regenerate; stopping early\n",
+			simulator.StepCount)
+	}
 
-		if err := simulation.ExportStatsCSV(stats, csvPath); err != nil {
-			fmt.Printf("Failed to export CSV: %v\n", err)
-		} else {
-			fmt.Printf("Exported statistics to %s\n", csvPath)
-		}
+	fmt.Printf("Simulation completed %d steps in %.2f seconds (simulation time: %.2fs, %.0f steps/s)\n",
+		simulator.StepCount, time.Since(startTime).Seconds(), simulator.Time, simulator.StepsPerSecond())
+
+	// Finish the statistics output
+	if err := sink.Close(); err != nil {
+		fmt.Printf("Failed to finish writing statistics: %v\n", err)
+	}
 
-		if err := simulation.ExportStatsJSON(stats, jsonPath); err != nil {
-			fmt.Printf("Failed to export JSON: %v\n", err)
+	// Export the event log if requested
+	if eventsPath != "" {
+		if err := simulator.ExportEventsCSV(eventsPath); err != nil {
+			fmt.Printf("Failed to export events: %v\n", err)
 		} else {
-			fmt.Printf("Exported statistics to %s\n", jsonPath)
+			fmt.Printf("Exported events to %s\n", eventsPath)
 		}
 	}
 }
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestWindowedRunFailed(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"Window closed normally", nil, false},
+		{"Game terminated", ebiten.Termination, false},
+		{"Wrapped termination", fmt.Errorf("run: %w", ebiten.Termination), false},
+		{"No display", errors.New("glfw: The DISPLAY environment variable is missing"), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := windowedRunFailed(tc.err); got != tc.expected {
+				t.Errorf("windowedRunFailed(%v) = %v; want %v", tc.err, got, tc.expected)
+			}
+		})
+	}
+}
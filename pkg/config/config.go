@@ -2,6 +2,8 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 	"os"
 )
@@ -23,27 +25,83 @@ type OrganismConfig struct {
 	TurnSpeed                    float64 `json:"turnSpeed"` // radians per step
 	PreferenceDistributionMean   float64 `json:"preferenceDistributionMean"`
 	PreferenceDistributionStdDev float64 `json:"preferenceDistributionStdDev"`
+	PreferenceDistribution       string  `json:"preferenceDistribution"`    // "normal" (default) or "bimodal"
+	SecondaryPreferenceMean      float64 `json:"secondaryPreferenceMean"`   // Mean of the second peak (bimodal only)
+	SecondaryPreferenceWeight    float64 `json:"secondaryPreferenceWeight"` // Fraction of organisms drawn from the second peak (bimodal only)
+	FeedingMemorySize            int     `json:"feedingMemorySize"`         // Recently fed positions each organism avoids (0 disables)
+	MinSensorSpread              float64 `json:"minSensorSpread"`           // Minimum angle in radians between the front and each side sensor after mutation (0 disables)
+	SensorFieldOfView            float64 `json:"sensorFieldOfView"`         // Largest angle in radians from the heading a sensor reads at; sensors beyond it are blind (0 disables)
+	CircadianStrength            float64 `json:"circadianStrength"`         // Fraction by which activity swings with the seasonal cycle (0 disables)
+	PlasticityRate               float64 `json:"plasticityRate"`            // Fraction per second each organism's preference drifts toward what it feeds on (0 disables)
+	ImprintStrength              float64 `json:"imprintStrength"`           // Fraction of the gap to the parent's local concentration offspring start shifted by, with plasticity on (0 disables)
+	ForagingStrategy             string  `json:"foragingStrategy"`          // "closestPreference" (default) or "lockOn"
+	InitialHeading               string  `json:"initialHeading"`            // "random" (default), "fixed" or "toward-center"
+	InitialHeadingAngle          float64 `json:"initialHeadingAngle"`       // Heading in radians for every organism (fixed only)
+	InitialSize                  float64 `json:"initialSize"`               // Body size of founders, scaling feeding radius, movement cost and drawn size (0 uses 1)
+	CrowdingThreshold            int     `json:"crowdingThreshold"`         // Neighbors within crowdingRadius that make an organism scatter (0 disables)
+	CrowdingRadius               float64 `json:"crowdingRadius"`            // How close other organisms must be to count as neighbors
+	DispersalDuration            float64 `json:"dispersalDuration"`         // Seconds a crowded organism keeps scattering
+	MaxAge                       float64 `json:"maxAge"`                    // Seconds an organism lives before dying of old age (0 disables)
+	UpdateFraction               float64 `json:"updateFraction"`            // Fraction of organisms updated each step, in turn, with a longer time step (0 updates all)
+	CollisionRadius              float64 `json:"collisionRadius"`           // Radius of an organism of size 1 that others are pushed out of, scaling with body size (0 lets organisms overlap)
+	PredatorSizeRatio            float64 `json:"predatorSizeRatio"`         // How many times larger than another an organism must be for that one to flee it (0 disables)
 }
 
+// Preference distribution names
+const (
+	PreferenceDistributionNormal  = "normal"
+	PreferenceDistributionBimodal = "bimodal"
+)
+
+// Initial heading distribution names
+const (
+	InitialHeadingRandom       = "random"
+	InitialHeadingFixed        = "fixed"
+	InitialHeadingTowardCenter = "toward-center"
+)
+
+// Foraging strategy names
+const (
+	ForagingStrategyClosestPreference = "closestPreference" // Turn toward whichever sensor best matches the preference
+	ForagingStrategyLockOn            = "lockOn"            // Commit to following a strong gradient until the match stops improving
+)
+
 // EnergyConfig holds settings for the energy system
 type EnergyConfig struct {
-	InitialEnergy         float64    `json:"initialEnergy"`         // Starting energy for new organisms
-	MaximumEnergy         float64    `json:"maximumEnergy"`         // Maximum energy capacity
-	BaseMetabolicRate     float64    `json:"baseMetabolicRate"`     // Energy consumed per second just existing
-	MovementCostFactor    float64    `json:"movementCostFactor"`    // Energy cost per unit of movement
-	SensingCostBase       float64    `json:"sensingCostBase"`       // Energy cost for sensor operations
-	OptimalEnergyGainRate float64    `json:"optimalEnergyGainRate"` // Maximum energy gain per second
-	EnergyEfficiencyRange [2]float64 `json:"energyEfficiencyRange"` // Min/max for random initialization
+	InitialEnergy          float64    `json:"initialEnergy"`          // Starting energy as a fraction of capacity (0.0-1.0)
+	MaximumEnergy          float64    `json:"maximumEnergy"`          // Maximum energy capacity
+	BaseMetabolicRate      float64    `json:"baseMetabolicRate"`      // Energy consumed per second just existing
+	MovementCostFactor     float64    `json:"movementCostFactor"`     // Energy cost per unit of movement
+	SensingCostBase        float64    `json:"sensingCostBase"`        // Energy cost for sensor operations
+	OptimalEnergyGainRate  float64    `json:"optimalEnergyGainRate"`  // Maximum energy gain per second
+	EnergyEfficiencyRange  [2]float64 `json:"energyEfficiencyRange"`  // Min/max for random initialization
+	MaxEnergyChangePerStep float64    `json:"maxEnergyChangePerStep"` // Largest energy change in one step, as a fraction of capacity (0 disables)
+	HungerSpeedBoost       float64    `json:"hungerSpeedBoost"`       // Extra speed fraction for hungry organisms (0 disables)
+	ReserveCapacityRatio   float64    `json:"reserveCapacityRatio"`   // Energy reserve size as a fraction of capacity (0 disables)
+	ReserveTransferRate    float64    `json:"reserveTransferRate"`    // Maximum energy moved to or from the reserve per second
+	KinShareRate           float64    `json:"kinShareRate"`           // Energy per second an organism may give to needy kin (0 disables)
+	KinShareRadius         float64    `json:"kinShareRadius"`         // How close kin must be to share energy
+	GainEfficiencyBudget   float64    `json:"gainEfficiencyBudget"`   // Fixed ratio of optimal gain to efficiency multiplier, trading one for the other (0 disables)
+	BoundaryCollisionCost  float64    `json:"boundaryCollisionCost"`  // Extra energy lost each time an organism bounces off a wall (0 disables)
+	DormancyThreshold      float64    `json:"dormancyThreshold"`      // Energy fraction of capacity below which starving organisms go dormant (0 disables)
+	DormantMetabolicFactor float64    `json:"dormantMetabolicFactor"` // Fraction of the normal energy drain paid while dormant
+	FlowThroughFeeding     bool       `json:"flowThroughFeeding"`     // Whether organisms only gain energy while moving, in proportion to the distance covered
+	FeedingDepletion       bool       `json:"feedingDepletion"`       // Whether feeding drains the chemical sources within each organism's feeding radius
 }
 
 // ReproductionConfig holds settings for the reproduction system
 type ReproductionConfig struct {
-	ReproductionThreshold float64 `json:"reproductionThreshold"` // Energy required to reproduce
-	EnergyTransferRatio   float64 `json:"energyTransferRatio"`   // Portion of energy given to offspring
-	OffspringDistance     float64 `json:"offspringDistance"`     // How far offspring spawns from parent
-	MutationRate          float64 `json:"mutationRate"`          // Probability of trait mutation
-	MutationMagnitude     float64 `json:"mutationMagnitude"`     // Maximum percent change when mutation occurs
-	MaxPopulation         int     `json:"maxPopulation"`         // Optional cap on total population
+	ReproductionThreshold   float64 `json:"reproductionThreshold"`   // Energy required to reproduce
+	EnergyTransferRatio     float64 `json:"energyTransferRatio"`     // Portion of energy given to offspring
+	OffspringDistance       float64 `json:"offspringDistance"`       // How far offspring spawns from parent
+	MutationRate            float64 `json:"mutationRate"`            // Probability of trait mutation
+	MutationMagnitude       float64 `json:"mutationMagnitude"`       // Maximum percent change when mutation occurs
+	MaxPopulation           int     `json:"maxPopulation"`           // Optional cap on total population
+	MaxReproductionsPerStep int     `json:"maxReproductionsPerStep"` // Optional cap on births in a single step (0 = unlimited)
+	EnvironmentCost         float64 `json:"environmentCost"`         // Energy each birth also draws from nearby sources (0 disables)
+	ScaleCooldown           bool    `json:"scaleCooldown"`           // Whether investing more energy per offspring lengthens the cooldown between births
+	RequireMate             bool    `json:"requireMate"`             // Whether an organism needs another one ready to reproduce nearby to reproduce
+	MateRadius              float64 `json:"mateRadius"`              // How close a mate must be when RequireMate is set
 }
 
 // ChemicalConfig holds settings for chemical sources
@@ -55,18 +113,61 @@ type ChemicalConfig struct {
 	MaxDecayFactor float64 `json:"maxDecayFactor"`
 	// New fields for energy balance
 	DepletionRate           float64 `json:"depletionRate"`
+	RegenerationEnabled     bool    `json:"regenerationEnabled"` // False stops depleted sources reactivating and new sources appearing
 	RegenerationProbability float64 `json:"regenerationProbability"`
 	TargetSystemEnergy      float64 `json:"targetSystemEnergy"`
+	SeasonPeriod            float64 `json:"seasonPeriod"`     // Seconds per seasonal food cycle (0 disables seasons)
+	SeasonAmplitude         float64 `json:"seasonAmplitude"`  // Fraction by which food availability swings with the seasons (0-1)
+	MaxConcentration        float64 `json:"maxConcentration"` // Level at which overlapping sources' summed concentration saturates (0 disables)
+	FineGridFactor          int     `json:"fineGridFactor"`   // Resolution of the grids around sources, as a multiple of the base grid's (below 2 disables)
+	FineGridRadius          float64 `json:"fineGridRadius"`   // Distance from each source its fine grid covers
 }
 
 // RenderConfig holds settings for visualization
 type RenderConfig struct {
-	WindowWidth  int  `json:"windowWidth"`
-	WindowHeight int  `json:"windowHeight"`
-	FrameRate    int  `json:"frameRate"`
-	ShowGrid     bool `json:"showGrid"`
-	ShowSensors  bool `json:"showSensors"`
-	ShowLegend   bool `json:"showLegend"`
+	WindowWidth  int                 `json:"windowWidth"`
+	WindowHeight int                 `json:"windowHeight"`
+	FrameRate    int                 `json:"frameRate"`
+	ShowGrid     bool                `json:"showGrid"`
+	ShowSensors  bool                `json:"showSensors"`
+	ShowLegend   bool                `json:"showLegend"`
+	Resizable    bool                `json:"resizable"`              // Whether the window can be resized, with the view following its size
+	ColorSchemes []ColorSchemeConfig `json:"colorSchemes,omitempty"` // Custom gradients added after the built-in schemes
+
+	ConcentrationUnit  string `json:"concentrationUnit"`  // Label shown after concentrations and preferences ("" shows bare numbers)
+	EnergyUnit         string `json:"energyUnit"`         // Label shown after energy amounts ("" shows bare numbers)
+	SignificantFigures int    `json:"significantFigures"` // Precision of displayed concentrations and energies (0 uses the default)
+
+	InitialZoom         float64      `json:"initialZoom"`                   // Camera zoom at launch and on Home, relative to fitting the whole world (0 fits it)
+	InitialCameraCenter *PointConfig `json:"initialCameraCenter,omitempty"` // World point the camera centers on at launch and on Home (omit for the world's center)
+}
+
+// PointConfig is a position in world coordinates
+type PointConfig struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// ColorSchemeConfig describes a custom color gradient for concentration visualization
+type ColorSchemeConfig struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Stops       []ColorStopConfig `json:"stops"` // Ordered by position, spanning 0 to 1
+}
+
+// ColorStopConfig is a single color in a custom gradient
+type ColorStopConfig struct {
+	Position float64 `json:"position"` // 0.0 to 1.0
+	R        uint8   `json:"r"`
+	G        uint8   `json:"g"`
+	B        uint8   `json:"b"`
+}
+
+// SimulationControl holds switches that change how the simulation is computed
+type SimulationControl struct {
+	ExactSensing  bool `json:"exactSensing"`  // Sense by summing sources directly instead of using the interpolated grid (slower)
+	EnergyEnabled bool `json:"energyEnabled"` // False turns off energy drain and gain, death and reproduction (pure chemotaxis)
+	UpdateWorkers int  `json:"updateWorkers"` // Goroutines organisms are updated across each step (0 or 1 updates them in turn)
 }
 
 // SimulationConfig holds all configuration for the simulation
@@ -78,6 +179,7 @@ type SimulationConfig struct {
 	Render          RenderConfig       `json:"render"`
 	Energy          EnergyConfig       `json:"energy"`       // New energy configuration
 	Reproduction    ReproductionConfig `json:"reproduction"` // New reproduction configuration
+	Control         SimulationControl  `json:"control"`
 	RandomSeed      int64              `json:"randomSeed"`
 	SimulationSpeed float64            `json:"simulationSpeed"`
 }
@@ -97,9 +199,12 @@ func DefaultConfig() SimulationConfig {
 			TurnSpeed:                    math.Pi / 10, // 18 degrees per step
 			PreferenceDistributionMean:   50.0,
 			PreferenceDistributionStdDev: 10.0,
+			PreferenceDistribution:       PreferenceDistributionNormal,
+			SecondaryPreferenceMean:      150.0,
+			SecondaryPreferenceWeight:    0.5,
 		},
 		Energy: EnergyConfig{
-			InitialEnergy:         80.0,                 // Start with 80% of maximum
+			InitialEnergy:         0.8,                  // Start with 80% of maximum
 			MaximumEnergy:         100.0,                // Base energy capacity
 			BaseMetabolicRate:     0.1,                  // Energy consumed per second just existing
 			MovementCostFactor:    0.02,                 // Energy cost per unit of movement
@@ -114,6 +219,7 @@ func DefaultConfig() SimulationConfig {
 			MutationRate:          0.2,  // 20% chance of mutation per trait
 			MutationMagnitude:     0.1,  // 10% maximum change when mutation occurs
 			MaxPopulation:         500,  // Maximum allowed population
+			ScaleCooldown:         true, // Bigger offspring take longer to recover from
 		},
 		Chemical: ChemicalConfig{
 			Count:          5,
@@ -123,6 +229,7 @@ func DefaultConfig() SimulationConfig {
 			MaxDecayFactor: 0.01,
 			// Default values for energy balance
 			DepletionRate:           0.2,
+			RegenerationEnabled:     true,
 			RegenerationProbability: 0.2,
 			TargetSystemEnergy:      10000.0,
 		},
@@ -133,12 +240,130 @@ func DefaultConfig() SimulationConfig {
 			ShowGrid:     true,
 			ShowSensors:  true,
 			ShowLegend:   true,
+			Resizable:    true,
+		},
+		Control: SimulationControl{
+			EnergyEnabled: true,
 		},
 		RandomSeed:      0, // 0 means use current time as seed
 		SimulationSpeed: 10.0,
 	}
 }
 
+// Validate checks the configuration for values that can't be used as intended
+func (c SimulationConfig) Validate() error {
+	var problems []error
+
+	if c.Energy.InitialEnergy < 0 || c.Energy.InitialEnergy > 1 {
+		problems = append(problems, fmt.Errorf(
+			"energy.initialEnergy must be a fraction of capacity between 0 and 1, got %v", c.Energy.InitialEnergy))
+	}
+
+	// Negative decay makes concentration grow with distance
+	if c.Chemical.MinDecayFactor < 0 {
+		problems = append(problems, fmt.Errorf(
+			"chemical.minDecayFactor must not be negative, got %v", c.Chemical.MinDecayFactor))
+	}
+	if c.Chemical.MaxDecayFactor < c.Chemical.MinDecayFactor {
+		problems = append(problems, fmt.Errorf(
+			"chemical.maxDecayFactor (%v) must not be less than chemical.minDecayFactor (%v)",
+			c.Chemical.MaxDecayFactor, c.Chemical.MinDecayFactor))
+	}
+	switch c.Organism.ForagingStrategy {
+	case "", ForagingStrategyClosestPreference, ForagingStrategyLockOn:
+	default:
+		problems = append(problems, fmt.Errorf(
+			"organism.foragingStrategy must be %q or %q, got %q",
+			ForagingStrategyClosestPreference, ForagingStrategyLockOn, c.Organism.ForagingStrategy))
+	}
+	if c.Organism.ImprintStrength < 0 || c.Organism.ImprintStrength > 1 {
+		problems = append(problems, fmt.Errorf(
+			"organism.imprintStrength must be between 0 and 1, got %v", c.Organism.ImprintStrength))
+	}
+	if c.Organism.InitialSize < 0 {
+		problems = append(problems, fmt.Errorf(
+			"organism.initialSize must not be negative (use 0 for the default size), got %v", c.Organism.InitialSize))
+	}
+	if c.Organism.SensorFieldOfView < 0 {
+		problems = append(problems, fmt.Errorf(
+			"organism.sensorFieldOfView must not be negative (use 0 for no blind spot), got %v", c.Organism.SensorFieldOfView))
+	}
+	if c.Control.UpdateWorkers < 0 {
+		problems = append(problems, fmt.Errorf(
+			"control.updateWorkers must not be negative (use 0 to update organisms in turn), got %v", c.Control.UpdateWorkers))
+	}
+	if c.Organism.UpdateFraction < 0 || c.Organism.UpdateFraction > 1 {
+		problems = append(problems, fmt.Errorf(
+			"organism.updateFraction must be between 0 and 1 (use 0 to update every organism every step), got %v", c.Organism.UpdateFraction))
+	}
+	if c.Organism.CollisionRadius < 0 {
+		problems = append(problems, fmt.Errorf(
+			"organism.collisionRadius must not be negative (use 0 to let organisms overlap), got %v", c.Organism.CollisionRadius))
+	}
+	if c.Organism.PredatorSizeRatio < 0 || (c.Organism.PredatorSizeRatio > 0 && c.Organism.PredatorSizeRatio <= 1) {
+		problems = append(problems, fmt.Errorf(
+			"organism.predatorSizeRatio must be greater than 1 (use 0 to disable fleeing), got %v", c.Organism.PredatorSizeRatio))
+	}
+	if c.Organism.MaxAge < 0 {
+		problems = append(problems, fmt.Errorf(
+			"organism.maxAge must not be negative (use 0 to disable aging), got %v", c.Organism.MaxAge))
+	}
+	if c.Organism.CrowdingThreshold > 0 && (c.Organism.CrowdingRadius <= 0 || c.Organism.DispersalDuration <= 0) {
+		problems = append(problems, fmt.Errorf(
+			"organism.crowdingRadius and organism.dispersalDuration must be positive when organism.crowdingThreshold is set, got %v and %v",
+			c.Organism.CrowdingRadius, c.Organism.DispersalDuration))
+	}
+	if c.Energy.DormantMetabolicFactor < 0 || c.Energy.DormantMetabolicFactor > 1 {
+		problems = append(problems, fmt.Errorf(
+			"energy.dormantMetabolicFactor must be between 0 and 1, got %v", c.Energy.DormantMetabolicFactor))
+	}
+	switch c.Organism.InitialHeading {
+	case "", InitialHeadingRandom, InitialHeadingFixed, InitialHeadingTowardCenter:
+	default:
+		problems = append(problems, fmt.Errorf(
+			"organism.initialHeading must be %q, %q or %q, got %q",
+			InitialHeadingRandom, InitialHeadingFixed, InitialHeadingTowardCenter, c.Organism.InitialHeading))
+	}
+	if c.Chemical.MaxConcentration < 0 {
+		problems = append(problems, fmt.Errorf(
+			"chemical.maxConcentration must not be negative (use 0 to disable the cap), got %v", c.Chemical.MaxConcentration))
+	}
+	if c.Chemical.FineGridFactor < 0 {
+		problems = append(problems, fmt.Errorf(
+			"chemical.fineGridFactor must not be negative (use 0 to disable fine grids), got %v", c.Chemical.FineGridFactor))
+	}
+	if c.Chemical.FineGridFactor >= 2 && c.Chemical.FineGridRadius <= 0 {
+		problems = append(problems, fmt.Errorf(
+			"chemical.fineGridRadius must be positive when fine grids are enabled, got %v", c.Chemical.FineGridRadius))
+	}
+	if c.Render.InitialZoom < 0 {
+		problems = append(problems, fmt.Errorf(
+			"render.initialZoom must not be negative (use 0 to fit the whole world), got %v", c.Render.InitialZoom))
+	}
+	if c.Render.SignificantFigures < 0 {
+		problems = append(problems, fmt.Errorf(
+			"render.significantFigures must not be negative (use 0 for the default), got %v", c.Render.SignificantFigures))
+	}
+	if c.Reproduction.RequireMate && c.Reproduction.MateRadius <= 0 {
+		problems = append(problems, fmt.Errorf(
+			"reproduction.mateRadius must be positive when reproduction.requireMate is set, got %v", c.Reproduction.MateRadius))
+	}
+
+	return errors.Join(problems...)
+}
+
+// Warnings lists settings that are valid but probably not intended
+func (c SimulationConfig) Warnings() []string {
+	var warnings []string
+
+	if c.Chemical.MinDecayFactor == 0 && c.Chemical.Count > 0 {
+		warnings = append(warnings, "chemical.minDecayFactor is 0: sources with no decay "+
+			"fill the whole world with a uniform field at full strength")
+	}
+
+	return warnings
+}
+
 // LoadFromFile loads configuration from a JSON file
 func LoadFromFile(filename string) (SimulationConfig, error) {
 	// Start with default config
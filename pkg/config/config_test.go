@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -165,3 +166,65 @@ func TestPartialConfig(t *testing.T) {
 		t.Errorf("Organism count should remain at default 100, got %v", config.Organism.Count)
 	}
 }
+
+func TestValidateInitialEnergy(t *testing.T) {
+	tests := []struct {
+		name          string
+		initialEnergy float64
+		wantErr       bool
+	}{
+		{"default", DefaultConfig().Energy.InitialEnergy, false},
+		{"empty", 0.0, false},
+		{"full", 1.0, false},
+		{"percentage instead of fraction", 80.0, true},
+		{"negative", -0.1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Energy.InitialEnergy = tt.initialEnergy
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() with initialEnergy %v = %v; want error: %v", tt.initialEnergy, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDecayFactor(t *testing.T) {
+	tests := []struct {
+		name        string
+		minDecay    float64
+		maxDecay    float64
+		wantErr     bool
+		wantWarning bool
+	}{
+		{"default", DefaultConfig().Chemical.MinDecayFactor, DefaultConfig().Chemical.MaxDecayFactor, false, false},
+		{"zero minimum", 0, 0.01, false, true},
+		{"negative minimum", -0.001, 0.01, true, false},
+		{"maximum below minimum", 0.01, 0.001, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Chemical.MinDecayFactor = tt.minDecay
+			cfg.Chemical.MaxDecayFactor = tt.maxDecay
+
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() with decay [%v, %v] = %v; want error: %v", tt.minDecay, tt.maxDecay, err, tt.wantErr)
+			}
+
+			warned := false
+			for _, warning := range cfg.Warnings() {
+				if strings.Contains(warning, "minDecayFactor") {
+					warned = true
+				}
+			}
+			if warned != tt.wantWarning {
+				t.Errorf("Warnings() with decay [%v, %v] = %v; want decay warning: %v",
+					tt.minDecay, tt.maxDecay, cfg.Warnings(), tt.wantWarning)
+			}
+		})
+	}
+}
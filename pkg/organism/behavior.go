@@ -2,6 +2,7 @@ package organism
 
 import (
 	"math"
+	"math/rand"
 
 	"github.com/zachbeta/evolve_sim/pkg/types"
 )
@@ -13,6 +14,34 @@ const (
 	MAX_CONCENTRATION     = 1000 // Maximum expected concentration for normalization
 )
 
+// AmbiguityTolerance is the spread between sensor differences, relative to the
+// preference, below which the readings are considered a near-tie
+const AmbiguityTolerance = 0.02
+
+// The sensor setup the base SensingCost is calibrated for
+const (
+	ReferenceSensorCount    = 3
+	ReferenceSensorDistance = 10.0
+)
+
+// Dispersal constants, for organisms scattering away from a crowd
+const (
+	DispersalTurnJitter = math.Pi / 2 // Largest random turn per second, in radians
+	DispersalSpeedBoost = 0.5         // Extra speed fraction while scattering
+)
+
+// Feeding memory constants
+const (
+	FeedingMemoryRadius  = 15.0 // Sensors this close to a remembered feeding position are penalized
+	FeedingMemoryPenalty = 0.5  // Penalty added to a penalized sensor's difference, relative to the preference
+)
+
+// Predator avoidance constants
+const (
+	PredatorDetectionRadius = 25.0 // Predators farther away than this are ignored
+	PredatorAvoidanceWeight = 2.0  // Penalty for a sensor pointing at an adjacent predator, relative to the preference
+)
+
 // Direction represents the three possible directions an organism can turn
 type Direction int
 
@@ -28,11 +57,76 @@ const (
 // DecideDirection determines the best direction for the organism to move
 // based on its sensor readings and chemical preference
 func DecideDirection(readings SensorReadings, preference float64) Direction {
+	return decidePenalized(readings, preference, [3]float64{})
+}
+
+// DecideDirectionAvoiding works like DecideDirection, but sensors that fall within
+// FeedingMemoryRadius of a recently fed position count as a worse match, steering
+// the organism away from patches it has just grazed
+func DecideDirectionAvoiding(
+	readings SensorReadings,
+	preference float64,
+	sensorPositions [3]types.Point,
+	fedPositions []types.Point,
+) Direction {
+	var penalties [3]float64
+	for i, sensor := range sensorPositions {
+		for _, fed := range fedPositions {
+			if sensor.DistanceTo(fed) <= FeedingMemoryRadius {
+				penalties[i] = FeedingMemoryPenalty * math.Abs(preference)
+				break
+			}
+		}
+	}
+
+	return decidePenalized(readings, preference, penalties)
+}
+
+// DecideDirectionFleeing works like DecideDirection, but sensors pointing toward the
+// nearest predator within detectionRadius count as a worse match, by up to
+// PredatorAvoidanceWeight times the preference for a sensor aimed straight at an
+// adjacent predator. A close predator outweighs the chemical match, steering the
+// organism away from it.
+func DecideDirectionFleeing(
+	readings SensorReadings,
+	preference float64,
+	position types.Point,
+	sensorPositions [3]types.Point,
+	predators []types.Point,
+	detectionRadius float64,
+) Direction {
+	// Only the nearest predator in range is fled from
+	nearest, found := types.Point{}, false
+	nearestDistance := detectionRadius
+	for _, predator := range predators {
+		if distance := position.DistanceTo(predator); distance <= nearestDistance {
+			nearest, nearestDistance, found = predator, distance, true
+		}
+	}
+
+	var penalties [3]float64
+	if found && nearestDistance > 0 {
+		urgency := 1 - nearestDistance/detectionRadius
+		bearing := math.Atan2(nearest.Y-position.Y, nearest.X-position.X)
+		for i, sensor := range sensorPositions {
+			// Sensors pointing away from the predator aren't penalized
+			facing := math.Cos(math.Atan2(sensor.Y-position.Y, sensor.X-position.X) - bearing)
+			penalties[i] = PredatorAvoidanceWeight * math.Abs(preference) * urgency * math.Max(facing, 0)
+		}
+	}
+
+	return decidePenalized(readings, preference, penalties)
+}
+
+// decidePenalized returns the direction whose reading is closest to the preference
+// once each sensor's penalty (front, left, right) is added to its difference.
+// Ties go to front, then left, then right.
+func decidePenalized(readings SensorReadings, preference float64, penalties [3]float64) Direction {
 	// Calculate the difference between each reading and the preference
 	// We want to find the reading closest to the preference
-	frontDiff := math.Abs(readings.Front - preference)
-	leftDiff := math.Abs(readings.Left - preference)
-	rightDiff := math.Abs(readings.Right - preference)
+	frontDiff := math.Abs(readings.Front-preference) + penalties[0]
+	leftDiff := math.Abs(readings.Left-preference) + penalties[1]
+	rightDiff := math.Abs(readings.Right-preference) + penalties[2]
 
 	// Find the minimum difference
 	minDiff := math.Min(frontDiff, math.Min(leftDiff, rightDiff))
@@ -42,9 +136,42 @@ func DecideDirection(readings SensorReadings, preference float64) Direction {
 		return Continue
 	} else if minDiff == leftDiff {
 		return Left
-	} else {
-		return Right
 	}
+	return Right
+}
+
+// SensorsAmbiguous reports whether all three sensor readings are nearly equally
+// close to the preference, so the readings give no useful direction
+func SensorsAmbiguous(readings SensorReadings, preference float64) bool {
+	frontDiff := math.Abs(readings.Front - preference)
+	leftDiff := math.Abs(readings.Left - preference)
+	rightDiff := math.Abs(readings.Right - preference)
+
+	maxDiff := math.Max(frontDiff, math.Max(leftDiff, rightDiff))
+	minDiff := math.Min(frontDiff, math.Min(leftDiff, rightDiff))
+
+	return maxDiff-minDiff <= AmbiguityTolerance*math.Abs(preference)
+}
+
+// FoodInSensorRange reports whether any sensor reads a concentration close enough
+// to the preference to gain energy from
+func FoodInSensorRange(readings SensorReadings, preference float64) bool {
+	for _, reading := range []float64{readings.Front, readings.Left, readings.Right} {
+		if 1.0-math.Min(math.Abs(reading-preference)/preference, 1.0) > ENERGY_GAIN_THRESHOLD {
+			return true
+		}
+	}
+	return false
+}
+
+// SensingEnergyCost returns the energy an organism spends sensing over deltaTime.
+// The base SensingCost covers ReferenceSensorCount sensors reaching
+// ReferenceSensorDistance; the cost scales linearly with both the number of
+// sensors and how far they reach, so sensing more or farther costs more.
+func SensingEnergyCost(org *types.Organism, sensorCount int, sensorDistance, deltaTime float64) float64 {
+	countFactor := float64(sensorCount) / ReferenceSensorCount
+	distanceFactor := math.Abs(sensorDistance) / ReferenceSensorDistance
+	return org.SensingCost * org.EnergyEfficiency * countFactor * distanceFactor * deltaTime
 }
 
 // Update performs a complete update cycle for an organism:
@@ -52,26 +179,81 @@ func DecideDirection(readings SensorReadings, preference float64) Direction {
 // 2. Decides direction
 // 3. Turns if necessary
 // 4. Moves forward
-// 5. Updates energy based on environment
+// 5. Updates energy based on environment, depleting the sources it feeds from
 func Update(
 	org *types.Organism,
 	world interface {
 		GetConcentrationAt(types.Point) float64
-		DepleteEnergyFromSourcesAt(types.Point, float64)
+		DepleteEnergyFromSourcesWithin(types.Point, float64, float64)
+	},
+	bounds types.Rect,
+	sensorDistance float64,
+	turnSpeed float64,
+	deltaTime float64,
+) {
+	UpdateWithRand(org, world, bounds, sensorDistance, turnSpeed, deltaTime, types.GlobalRand, true)
+}
+
+// UpdateWithRand performs an update cycle like Update, drawing the random turns of
+// dispersing organisms from rng so seeded runs move identically. Organisms only grow
+// older while aging is set, so they never die of old age when it isn't.
+func UpdateWithRand(
+	org *types.Organism,
+	world interface {
+		GetConcentrationAt(types.Point) float64
+		DepleteEnergyFromSourcesWithin(types.Point, float64, float64)
 	},
 	bounds types.Rect,
 	sensorDistance float64,
 	turnSpeed float64,
 	deltaTime float64,
+	rng *rand.Rand,
+	aging bool,
 ) {
-	// Apply sensing cost before reading sensors
-	org.Energy -= org.SensingCost * org.EnergyEfficiency * deltaTime
+	// Organisms past their lifespan die of old age and do nothing more
+	if aging {
+		org.Age += deltaTime
+		if org.MaxAge > 0 && org.Age >= org.MaxAge {
+			org.MarkDead(types.DeathCauseOldAge)
+			return
+		}
+	}
+
+	// Apply sensing cost before reading sensors; dormant organisms sense sluggishly
+	sensingCost := SensingEnergyCost(org, len(org.SensorAngles), sensorDistance, deltaTime)
+	if org.Dormant {
+		sensingCost *= org.DormantMetabolicFactor
+	}
+	org.Energy -= sensingCost
 
 	// Read sensors
 	readings := ReadSensors(org, world, sensorDistance)
 
-	// Decide direction
-	direction := DecideDirection(readings, org.ChemPreference)
+	// Starving organisms with nothing to eat in reach wait, still, for conditions to improve
+	if org.DormancyThreshold > 0 {
+		org.Dormant = org.Energy < org.DormancyThreshold*org.EnergyCapacity &&
+			!FoodInSensorRange(readings, org.EffectivePreference())
+	}
+	if org.Dormant {
+		org.DistanceMoved = 0
+		feed(org, world, deltaTime)
+		if org.Energy <= 0 {
+			org.MarkDead(types.DeathCauseStarvation)
+		}
+		org.TimeSinceReproduction += deltaTime
+		return
+	}
+
+	// Flee a predator the world reports close by, dropping any locked heading;
+	// otherwise decide direction using the organism's foraging strategy
+	var direction Direction
+	if predators := sensePredators(org, world); len(predators) > 0 {
+		org.LockedOn = false
+		direction = DecideDirectionFleeing(readings, org.EffectivePreference(), org.Position,
+			org.GetSensorPositions(sensorDistance), predators, PredatorDetectionRadius)
+	} else {
+		direction = StrategyFor(org.ForagingStrategy).Decide(org, readings, sensorDistance, turnSpeed*deltaTime)
+	}
 
 	// Turn if necessary
 	switch direction {
@@ -80,20 +262,45 @@ func Update(
 	case Right:
 		org.Turn(turnSpeed * deltaTime)
 	case Continue:
-		// Continue straight, no turning needed
+		// On a near-tie, drift toward the organism's preferred turning side,
+		// unless it has committed to a heading
+		if org.TurnBias != 0 && !org.LockedOn && SensorsAmbiguous(readings, org.EffectivePreference()) {
+			org.Turn(org.TurnBias * turnSpeed * deltaTime)
+		}
+	}
+
+	// Crowded organisms scatter in random directions until their dispersal runs out
+	if org.DispersalTime > 0 {
+		org.Turn((2*rng.Float64() - 1) * DispersalTurnJitter * deltaTime)
 	}
 
 	// Move forward (this includes energy consumption for movement)
 	Move(org, bounds, deltaTime)
 
 	// Update energy status - gain from optimal environment, lose from metabolism
-	org.UpdateEnergy(world, deltaTime)
+	feed(org, world, deltaTime)
 
 	// If energy is depleted, mark for removal
 	if org.Energy <= 0 {
-		org.MarkForRemoval = true
+		org.MarkDead(types.DeathCauseStarvation)
 	}
 
 	// Update reproduction timer
 	org.TimeSinceReproduction += deltaTime
+	org.DispersalTime = math.Max(org.DispersalTime-deltaTime, 0)
+}
+
+// feed updates the organism's energy and takes what it consumed out of the chemical
+// sources within its feeding radius
+func feed(
+	org *types.Organism,
+	world interface {
+		GetConcentrationAt(types.Point) float64
+		DepleteEnergyFromSourcesWithin(types.Point, float64, float64)
+	},
+	deltaTime float64,
+) {
+	if consumed := org.UpdateEnergy(world, deltaTime); consumed > 0 {
+		world.DepleteEnergyFromSourcesWithin(org.Position, org.FeedingRadius(), consumed)
+	}
 }
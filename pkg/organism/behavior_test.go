@@ -81,7 +81,7 @@ func (mw *behaviorMockWorld) GetConcentrationAt(p types.Point) float64 {
 	return mw.concentrationFn(p)
 }
 
-func (mw *behaviorMockWorld) DepleteEnergyFromSourcesAt(p types.Point, amount float64) {
+func (mw *behaviorMockWorld) DepleteEnergyFromSourcesWithin(p types.Point, radius, amount float64) {
 	mw.depletedEnergy += amount
 	mw.depletedPosition = p
 }
@@ -203,3 +203,299 @@ func TestUpdate(t *testing.T) {
 		}
 	})
 }
+
+func TestTurnBias(t *testing.T) {
+	bounds := types.Rect{
+		Min: types.Point{X: 0, Y: 0},
+		Max: types.Point{X: 100, Y: 100},
+	}
+
+	// A uniform field gives identical readings on every sensor
+	uniformWorld := &behaviorMockWorld{
+		concentrationFn: func(p types.Point) float64 {
+			return 20.0
+		},
+	}
+
+	newBiasedOrganism := func(bias float64) types.Organism {
+		org := types.NewOrganism(
+			types.Point{X: 50, Y: 50},
+			math.Pi/2, // Heading south
+			10.0,
+			1.0,
+			types.DefaultSensorAngles(),
+		)
+		org.TurnBias = bias
+		return org
+	}
+
+	leftHanded := newBiasedOrganism(-1.0)
+	rightHanded := newBiasedOrganism(1.0)
+	unbiased := newBiasedOrganism(0.0)
+
+	Update(&leftHanded, uniformWorld, bounds, 5.0, 0.1, 1.0)
+	Update(&rightHanded, uniformWorld, bounds, 5.0, 0.1, 1.0)
+	Update(&unbiased, uniformWorld, bounds, 5.0, 0.1, 1.0)
+
+	if leftHanded.Heading >= math.Pi/2 {
+		t.Errorf("Expected left-biased organism to turn left, heading = %v", leftHanded.Heading)
+	}
+
+	if rightHanded.Heading <= math.Pi/2 {
+		t.Errorf("Expected right-biased organism to turn right, heading = %v", rightHanded.Heading)
+	}
+
+	if unbiased.Heading != math.Pi/2 {
+		t.Errorf("Expected unbiased organism to keep its heading, heading = %v", unbiased.Heading)
+	}
+}
+
+func TestSensingEnergyCost(t *testing.T) {
+	org := types.NewOrganism(types.Point{X: 50, Y: 50}, 0, 10, 1.0, types.DefaultSensorAngles())
+	org.SensingCost = 0.3
+	org.EnergyEfficiency = 1.0
+
+	base := SensingEnergyCost(&org, ReferenceSensorCount, ReferenceSensorDistance, 1.0)
+	if math.Abs(base-0.3) > 1e-9 {
+		t.Errorf("Reference sensing cost = %v; want the base SensingCost 0.3", base)
+	}
+
+	tests := []struct {
+		name     string
+		count    int
+		distance float64
+		want     float64 // Multiple of the reference cost
+	}{
+		{"twice as far", ReferenceSensorCount, 2 * ReferenceSensorDistance, 2},
+		{"twice as many", 2 * ReferenceSensorCount, ReferenceSensorDistance, 2},
+		{"more and farther", 2 * ReferenceSensorCount, 3 * ReferenceSensorDistance, 6},
+		{"shorter reach", ReferenceSensorCount, ReferenceSensorDistance / 2, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SensingEnergyCost(&org, tt.count, tt.distance, 1.0)
+			if math.Abs(got-base*tt.want) > 1e-9 {
+				t.Errorf("SensingEnergyCost(%d, %v) = %v; want %v", tt.count, tt.distance, got, base*tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedingMemoryAvoidsDrainedPatch(t *testing.T) {
+	bounds := types.Rect{Min: types.Point{X: 0, Y: 0}, Max: types.Point{X: 200, Y: 200}}
+	patch := types.Point{X: 50, Y: 50}
+
+	// A single patch whose center matches the organisms' preference
+	patchWorld := &behaviorMockWorld{
+		concentrationFn: func(p types.Point) float64 {
+			d := p.DistanceTo(patch)
+			return 100.0 * math.Exp(-d*d/800.0)
+		},
+	}
+
+	closestApproach := func(remembers bool) float64 {
+		org := types.NewOrganism(types.Point{X: 80, Y: 50}, math.Pi, 100.0, 1.0, types.DefaultSensorAngles())
+		if remembers {
+			// Just fed at the patch
+			org.FeedingMemorySize = 5
+			org.RememberFeeding(patch)
+		}
+
+		closest := org.Position.DistanceTo(patch)
+		for step := 0; step < 40; step++ {
+			Update(&org, patchWorld, bounds, 10.0, 0.5, 1.0)
+			closest = math.Min(closest, org.Position.DistanceTo(patch))
+		}
+		return closest
+	}
+
+	naive := closestApproach(false)
+	remembering := closestApproach(true)
+
+	if remembering <= naive {
+		t.Errorf("Expected the organism that just fed to stay farther from the patch: closest %v vs naive %v",
+			remembering, naive)
+	}
+	if remembering < FeedingMemoryRadius/2 {
+		t.Errorf("Expected the organism to avoid returning to the patch center, got within %v", remembering)
+	}
+}
+
+func TestDormancyInBarrenField(t *testing.T) {
+	barren := &behaviorMockWorld{
+		concentrationFn: func(p types.Point) float64 { return 0 },
+	}
+	bounds := types.Rect{Min: types.Point{X: 0, Y: 0}, Max: types.Point{X: 100, Y: 100}}
+
+	newStarving := func(dormancyThreshold float64) types.Organism {
+		org := types.NewOrganism(types.Point{X: 50, Y: 50}, 0, 50.0, 1.0, types.DefaultSensorAngles())
+		org.Energy = org.EnergyCapacity * 0.05
+		org.DormancyThreshold = dormancyThreshold
+		org.DormantMetabolicFactor = 0.1
+		return org
+	}
+
+	dormant := newStarving(0.1)
+	active := newStarving(0) // Dormancy disabled
+	startEnergy, startPosition := dormant.Energy, dormant.Position
+
+	for i := 0; i < 10; i++ {
+		Update(&dormant, barren, bounds, 5.0, 0.1, 0.1)
+		Update(&active, barren, bounds, 5.0, 0.1, 0.1)
+	}
+
+	if !dormant.Dormant {
+		t.Fatal("Expected the starving organism to go dormant")
+	}
+	if dormant.Position != startPosition {
+		t.Errorf("Dormant organism moved from %v to %v", startPosition, dormant.Position)
+	}
+
+	dormantDrain := startEnergy - dormant.Energy
+	activeDrain := startEnergy - active.Energy
+	if dormantDrain <= 0 || dormantDrain > activeDrain/5 {
+		t.Errorf("Dormant drain = %v, active drain = %v; want dormant much slower", dormantDrain, activeDrain)
+	}
+
+	// Food coming into reach wakes the organism
+	fed := &behaviorMockWorld{
+		concentrationFn: func(p types.Point) float64 { return 50.0 },
+	}
+	Update(&dormant, fed, bounds, 5.0, 0.1, 0.1)
+	if dormant.Dormant {
+		t.Error("Expected the organism to wake once food is in sensor range")
+	}
+}
+
+func TestDecidePenalized(t *testing.T) {
+	readings := SensorReadings{Front: 10.0, Left: 8.0, Right: 7.0}
+
+	tests := []struct {
+		name      string
+		penalties [3]float64
+		want      Direction
+	}{
+		{"No penalties follows the chemical", [3]float64{}, Continue},
+		{"Penalized front turns to the next best", [3]float64{5, 0, 0}, Left},
+		{"Penalties can outweigh the chemical", [3]float64{5, 5, 0}, Right},
+		{"Tie goes to front", [3]float64{2, 0, 0}, Continue},
+		{"Tie between sides goes left", [3]float64{3, 0, 1}, Left},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decidePenalized(readings, 10.0, tt.penalties); got != tt.want {
+				t.Errorf("decidePenalized(%v) = %v; want %v", tt.penalties, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlowThroughFeedingNeedsMovement(t *testing.T) {
+	perfect := &behaviorMockWorld{
+		concentrationFn: func(p types.Point) float64 { return 50.0 },
+	}
+	bounds := types.Rect{Min: types.Point{X: 0, Y: 0}, Max: types.Point{X: 100, Y: 100}}
+
+	gain := func(speed float64) float64 {
+		org := types.NewOrganism(types.Point{X: 50, Y: 50}, 0, 50.0, speed, types.DefaultSensorAngles())
+		org.FlowThroughFeeding = true
+		org.Energy = org.EnergyCapacity / 2
+		org.MetabolicRate, org.MovementCost, org.SensingCost = 0, 0, 0
+		start := org.Energy
+
+		Update(&org, perfect, bounds, 5.0, 0.1, 1.0)
+		return org.Energy - start
+	}
+
+	if stationary := gain(0); stationary != 0 {
+		t.Errorf("Stationary flow-through feeder gained %v energy in a perfect field; want 0", stationary)
+	}
+	if moving := gain(1); moving <= 0 {
+		t.Errorf("Moving flow-through feeder gained %v energy in a perfect field; want a gain", moving)
+	}
+}
+
+func TestDecideDirectionFleeing(t *testing.T) {
+	// Heading right, with the best chemical match straight ahead
+	org := types.NewOrganism(types.Point{X: 50, Y: 50}, 0, 10.0, 1.0, types.DefaultSensorAngles())
+	sensors := org.GetSensorPositions(10.0)
+	readings := SensorReadings{Front: 10.0, Left: 8.0, Right: 7.0}
+
+	tests := []struct {
+		name      string
+		predators []types.Point
+		want      []Direction
+	}{
+		{"No predators follows the chemical", nil, []Direction{Continue}},
+		{"Distant predator is ignored", []types.Point{{X: 90, Y: 50}}, []Direction{Continue}},
+		{"Predator ahead turns away", []types.Point{{X: 55, Y: 50}}, []Direction{Left, Right}},
+		{"Predator behind keeps going", []types.Point{{X: 45, Y: 50}}, []Direction{Continue}},
+		{"Predator ahead and left turns right", []types.Point{{X: 55, Y: 45}, {X: 90, Y: 90}}, []Direction{Right}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DecideDirectionFleeing(readings, org.ChemPreference, org.Position, sensors, tt.predators, PredatorDetectionRadius)
+			for _, want := range tt.want {
+				if got == want {
+					return
+				}
+			}
+			t.Errorf("DecideDirectionFleeing() = %v; want one of %v", got, tt.want)
+		})
+	}
+}
+
+// predatorMockWorld is a behaviorMockWorld that reports the same predators to every organism
+type predatorMockWorld struct {
+	behaviorMockWorld
+	predators []types.Point
+}
+
+func (mw *predatorMockWorld) PredatorsNear(org *types.Organism) []types.Point {
+	return mw.predators
+}
+
+func TestUpdateFleesPredator(t *testing.T) {
+	bounds := types.Rect{Min: types.Point{X: 0, Y: 0}, Max: types.Point{X: 200, Y: 200}}
+	predator := types.Point{X: 58, Y: 50}
+
+	// The best match to the preference lies straight ahead, along y = 50, right
+	// where the predator sits
+	ridge := behaviorMockWorld{
+		concentrationFn: func(p types.Point) float64 { return 100.0 - math.Abs(p.Y-50) },
+	}
+
+	closestApproach := func(world interface {
+		GetConcentrationAt(types.Point) float64
+		DepleteEnergyFromSourcesWithin(types.Point, float64, float64)
+	}) (float64, float64) {
+		org := types.NewOrganism(types.Point{X: 50, Y: 50}, 0, 100.0, 1.0, types.DefaultSensorAngles())
+
+		closest := org.Position.DistanceTo(predator)
+		var firstHeading float64
+		for step := 0; step < 20; step++ {
+			Update(&org, world, bounds, 10.0, 0.5, 1.0)
+			if step == 0 {
+				firstHeading = org.Heading
+			}
+			closest = math.Min(closest, org.Position.DistanceTo(predator))
+		}
+		return closest, firstHeading
+	}
+
+	naive, naiveHeading := closestApproach(&ridge)
+	fleeing, fleeingHeading := closestApproach(&predatorMockWorld{ridge, []types.Point{predator}})
+
+	if naiveHeading != 0 {
+		t.Errorf("Expected the organism to follow the ridge without a predator, turned to %v", naiveHeading)
+	}
+	if fleeingHeading == 0 {
+		t.Error("Expected the organism to turn off the ridge away from the predator")
+	}
+	if fleeing <= naive {
+		t.Errorf("Expected the fleeing organism to keep farther from the predator: closest %v vs naive %v", fleeing, naive)
+	}
+}
@@ -6,6 +6,40 @@ import (
 	"github.com/zachbeta/evolve_sim/pkg/types"
 )
 
+// Wall-stuck detection constants
+const (
+	WallStuckWindow = 1.0 // Most seconds between collisions for them to count as one streak
+	WallStuckHits   = 3   // Collisions in a streak after which an organism counts as stuck
+)
+
+// WallStuck reports whether the organism keeps bouncing off the walls, an artifact
+// of reflecting headings in corners
+func WallStuck(org types.Organism) bool {
+	return org.WallHitStreak >= WallStuckHits && org.TimeSinceWallHit <= WallStuckWindow
+}
+
+// CriticalEnergyRatio is the fraction of capacity below which organisms slow down
+const CriticalEnergyRatio = 0.1
+
+// SpeedMultiplier returns the fraction of its base speed an organism moves at for the
+// given energy ratio (energy / capacity). Below CriticalEnergyRatio organisms slow down
+// in proportion to their remaining energy. Above it they move at base speed or, with a
+// positive hungerBoost, up to 1+hungerBoost times faster the closer they get to the threshold.
+func SpeedMultiplier(energyRatio, hungerBoost float64) float64 {
+	if energyRatio <= 0 {
+		return 0
+	}
+	if energyRatio < CriticalEnergyRatio {
+		return energyRatio / CriticalEnergyRatio
+	}
+	if hungerBoost <= 0 {
+		return 1
+	}
+
+	hunger := (1 - math.Min(energyRatio, 1)) / (1 - CriticalEnergyRatio)
+	return 1 + hungerBoost*hunger
+}
+
 // Move updates the organism's position based on its heading and speed
 // It handles boundary collisions and adjusts the position and heading accordingly
 func Move(org *types.Organism, bounds types.Rect, deltaTime float64) {
@@ -15,6 +49,18 @@ func Move(org *types.Organism, bounds types.Rect, deltaTime float64) {
 	// Calculate the distance to move based on speed and time delta
 	distance := org.Speed * deltaTime
 
+	// Hungry organisms search faster if hunger-driven speed is enabled
+	if org.HungerSpeedBoost > 0 && org.EnergyCapacity > 0 {
+		if energyRatio := org.Energy / org.EnergyCapacity; energyRatio >= CriticalEnergyRatio {
+			distance *= SpeedMultiplier(energyRatio, org.HungerSpeedBoost)
+		}
+	}
+
+	// Scattering organisms flee the crowd faster
+	if org.DispersalTime > 0 {
+		distance *= 1 + DispersalSpeedBoost
+	}
+
 	// Store the original position to restore if needed
 	originalPos := org.Position
 
@@ -26,10 +72,10 @@ func Move(org *types.Organism, bounds types.Rect, deltaTime float64) {
 	// Calculate actual distance moved for energy consumption
 	distanceMoved := math.Sqrt(dx*dx + dy*dy)
 
-	// Consume energy based on distance moved and speed
-	// Faster organisms use more energy per unit distance
+	// Consume energy based on distance moved, speed and size
+	// Faster and bigger organisms use more energy per unit distance
 	// Use the organism's MovementCost parameter modified by EnergyEfficiency
-	energyCost := distanceMoved * org.MovementCost * org.EnergyEfficiency * (1.0 + org.Speed*0.05)
+	energyCost := distanceMoved * org.MovementCost * org.EnergyEfficiency * (1.0 + org.Speed*0.05) * org.BodySize()
 	org.Energy -= energyCost
 
 	// If energy is depleted, reduce speed proportionally
@@ -37,10 +83,9 @@ func Move(org *types.Organism, bounds types.Rect, deltaTime float64) {
 		org.Energy = 0
 		distance = 0 // Stop movement when out of energy
 		newPos = originalPos
-	} else if org.Energy < org.EnergyCapacity*0.1 {
+	} else if org.Energy < org.EnergyCapacity*CriticalEnergyRatio {
 		// Reduce speed when low on energy (less than 10% of capacity)
-		energyRatio := org.Energy / (org.EnergyCapacity * 0.1)
-		distance *= energyRatio
+		distance *= SpeedMultiplier(org.Energy/org.EnergyCapacity, 0)
 		dx = math.Cos(org.Heading) * distance
 		dy = math.Sin(org.Heading) * distance
 		newPos = types.Point{X: originalPos.X + dx, Y: originalPos.Y + dy}
@@ -70,6 +115,17 @@ func Move(org *types.Organism, bounds types.Rect, deltaTime float64) {
 		// Update the heading
 		org.Heading = newHeading
 
+		// Bouncing off a wall costs extra, so hugging the edges doesn't pay
+		org.Energy = math.Max(org.Energy-org.BoundaryCost, 0)
+
+		// Count collisions that come in quick succession
+		if org.WallHitStreak > 0 && org.TimeSinceWallHit <= WallStuckWindow {
+			org.WallHitStreak++
+		} else {
+			org.WallHitStreak = 1
+		}
+		org.TimeSinceWallHit = 0
+
 		// Keep organism within bounds
 		boundedX := math.Max(bounds.Min.X, math.Min(newPos.X, bounds.Max.X-0.001))
 		boundedY := math.Max(bounds.Min.Y, math.Min(newPos.Y, bounds.Max.Y-0.001))
@@ -77,7 +133,9 @@ func Move(org *types.Organism, bounds types.Rect, deltaTime float64) {
 	} else {
 		// No collision, update position normally
 		org.Position = newPos
+		org.TimeSinceWallHit += deltaTime
 	}
+	org.DistanceMoved = originalPos.DistanceTo(org.Position)
 
 	// Update the organism's trail
 	org.UpdateTrail()
@@ -94,3 +94,120 @@ func TestMove(t *testing.T) {
 		}
 	})
 }
+
+func TestSpeedMultiplier(t *testing.T) {
+	const boost = 1.0
+
+	tests := []struct {
+		name        string
+		energyRatio float64
+		hungerBoost float64
+		want        float64
+	}{
+		{"empty", 0.0, boost, 0.0},
+		{"below threshold drops sharply", 0.05, boost, 0.5},
+		{"just above threshold is fastest", CriticalEnergyRatio, boost, 1.0 + boost},
+		{"half full", 0.55, boost, 1.5},
+		{"full energy moves at base speed", 1.0, boost, 1.0},
+		{"disabled above threshold", 0.3, 0.0, 1.0},
+		{"disabled below threshold", 0.05, 0.0, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SpeedMultiplier(tt.energyRatio, tt.hungerBoost)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("SpeedMultiplier(%v, %v) = %v; want %v", tt.energyRatio, tt.hungerBoost, got, tt.want)
+			}
+		})
+	}
+
+	// The boosted curve should rise steadily as energy falls toward the threshold
+	previous := SpeedMultiplier(1.0, boost)
+	for ratio := 0.9; ratio >= CriticalEnergyRatio; ratio -= 0.1 {
+		current := SpeedMultiplier(ratio, boost)
+		if current <= previous {
+			t.Errorf("Expected speed to increase as energy falls to %v: %v <= %v", ratio, current, previous)
+		}
+		previous = current
+	}
+}
+
+func TestMoveHungerSpeedBoost(t *testing.T) {
+	bounds := types.Rect{Min: types.Point{X: 0, Y: 0}, Max: types.Point{X: 1000, Y: 1000}}
+
+	newOrg := func(boost float64) types.Organism {
+		org := types.NewOrganism(types.Point{X: 100, Y: 100}, 0, 10, 1.0, types.DefaultSensorAngles())
+		org.Energy = org.EnergyCapacity * 0.3
+		org.HungerSpeedBoost = boost
+		return org
+	}
+
+	plain := newOrg(0)
+	hungry := newOrg(1.0)
+	Move(&plain, bounds, 10.0)
+	Move(&hungry, bounds, 10.0)
+
+	if hungry.Position.X-100 <= plain.Position.X-100 {
+		t.Errorf("Expected hungry organism to travel farther: %v vs %v", hungry.Position.X-100, plain.Position.X-100)
+	}
+}
+
+func TestMoveBoundaryCollisionCost(t *testing.T) {
+	bounds := types.Rect{
+		Min: types.Point{X: 0, Y: 0},
+		Max: types.Point{X: 100, Y: 100},
+	}
+
+	newOrg := func(x, cost float64) types.Organism {
+		org := types.NewOrganism(types.Point{X: x, Y: 50}, 0, 10, 1.0, types.DefaultSensorAngles())
+		org.BoundaryCost = cost
+		org.EnergyEfficiency = 1.0 // Randomized by default; fix it so energies compare
+		return org
+	}
+
+	// Identical organisms hit the right wall; only one pays for it
+	free := newOrg(99.5, 0)
+	costly := newOrg(99.5, 2.0)
+	Move(&free, bounds, 1.0)
+	Move(&costly, bounds, 1.0)
+
+	if got := free.Energy - costly.Energy; math.Abs(got-2.0) > 1e-9 {
+		t.Errorf("Wall collision cost %v extra energy; want 2", got)
+	}
+
+	// Away from the walls the cost never applies
+	open := newOrg(50, 2.0)
+	plain := newOrg(50, 0)
+	Move(&open, bounds, 1.0)
+	Move(&plain, bounds, 1.0)
+	if open.Energy != plain.Energy {
+		t.Errorf("Energy without a collision = %v; want %v", open.Energy, plain.Energy)
+	}
+}
+
+func TestMoveCostScalesWithSize(t *testing.T) {
+	bounds := types.Rect{
+		Min: types.Point{X: 0, Y: 0},
+		Max: types.Point{X: 100, Y: 100},
+	}
+
+	newOrg := func(size float64) types.Organism {
+		org := types.NewOrganism(types.Point{X: 50, Y: 50}, 0, 10, 1.0, types.DefaultSensorAngles())
+		org.Size = size
+		org.EnergyEfficiency = 1.0
+		return org
+	}
+
+	small, big := newOrg(1), newOrg(2)
+	start := small.Energy
+	Move(&small, bounds, 1.0)
+	Move(&big, bounds, 1.0)
+
+	if small.Position != big.Position {
+		t.Errorf("Big organism moved to %v; want the same distance as the small one, to %v", big.Position, small.Position)
+	}
+	if got, want := start-big.Energy, 2*(start-small.Energy); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Size 2 movement cost = %v; want twice the size 1 cost, %v", got, want)
+	}
+}
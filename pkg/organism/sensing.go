@@ -1,6 +1,8 @@
 package organism
 
 import (
+	"math"
+
 	"github.com/zachbeta/evolve_sim/pkg/types"
 )
 
@@ -9,6 +11,48 @@ type SensorReadings struct {
 	Front float64
 	Left  float64
 	Right float64
+
+	// GradientMagnitude is the local steepness of the concentration field at the
+	// organism's position. It is zero if the world can't report gradients.
+	GradientMagnitude float64
+
+	// GradientDirection is the angle in radians of the uphill direction at the
+	// organism's position, valid when GradientMagnitude is nonzero
+	GradientDirection float64
+}
+
+// batchSampler is implemented by worlds that can look up several concentrations at once
+type batchSampler interface {
+	GetConcentrationsAt([]types.Point) []float64
+}
+
+// gradientSource is implemented by worlds that can report the unnormalized
+// concentration gradient at a point
+type gradientSource interface {
+	GetConcentrationGradientVectorAt(types.Point) types.Point
+}
+
+// predatorSource is implemented by worlds that can report the positions of the
+// predators near an organism
+type predatorSource interface {
+	PredatorsNear(org *types.Organism) []types.Point
+}
+
+// sensePredators returns the positions of the predators within PredatorDetectionRadius
+// of the organism, or nil if the world doesn't report predators
+func sensePredators(org *types.Organism, world interface{ GetConcentrationAt(types.Point) float64 }) []types.Point {
+	pw, ok := world.(predatorSource)
+	if !ok {
+		return nil
+	}
+
+	var near []types.Point
+	for _, predator := range pw.PredatorsNear(org) {
+		if org.Position.DistanceTo(predator) <= PredatorDetectionRadius {
+			near = append(near, predator)
+		}
+	}
+	return near
 }
 
 // ReadSensors reads the chemical concentration at each sensor position
@@ -21,11 +65,24 @@ func ReadSensors(
 	// Get sensor positions
 	sensorPositions := org.GetSensorPositions(sensorDistance)
 
-	// Read concentrations at each sensor position
-	readings := SensorReadings{
-		Front: world.GetConcentrationAt(sensorPositions[0]),
-		Left:  world.GetConcentrationAt(sensorPositions[1]),
-		Right: world.GetConcentrationAt(sensorPositions[2]),
+	// Read concentrations at each sensor position, in one batch if the world supports it
+	var readings SensorReadings
+	if bw, ok := world.(batchSampler); ok {
+		values := bw.GetConcentrationsAt(sensorPositions[:])
+		readings = SensorReadings{Front: values[0], Left: values[1], Right: values[2]}
+	} else {
+		readings = SensorReadings{
+			Front: world.GetConcentrationAt(sensorPositions[0]),
+			Left:  world.GetConcentrationAt(sensorPositions[1]),
+			Right: world.GetConcentrationAt(sensorPositions[2]),
+		}
+	}
+
+	// Sense how steep the landscape is, if the world supports it
+	if gw, ok := world.(gradientSource); ok {
+		gradient := gw.GetConcentrationGradientVectorAt(org.Position)
+		readings.GradientMagnitude = math.Sqrt(gradient.X*gradient.X + gradient.Y*gradient.Y)
+		readings.GradientDirection = math.Atan2(gradient.Y, gradient.X)
 	}
 
 	return readings
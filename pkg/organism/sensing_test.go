@@ -1,6 +1,7 @@
 package organism
 
 import (
+	"math"
 	"testing"
 
 	"github.com/zachbeta/evolve_sim/pkg/types"
@@ -77,3 +78,90 @@ func TestReadSensors(t *testing.T) {
 		}
 	})
 }
+
+// linearGradientWorld is a world whose concentration rises linearly along X
+type linearGradientWorld struct {
+	slope float64
+}
+
+func (w *linearGradientWorld) GetConcentrationAt(p types.Point) float64 {
+	return w.slope * p.X
+}
+
+func (w *linearGradientWorld) GetConcentrationGradientVectorAt(p types.Point) types.Point {
+	return types.Point{X: w.slope, Y: 0}
+}
+
+func TestReadSensorsGradientMagnitude(t *testing.T) {
+	org := types.NewOrganism(
+		types.Point{X: 50, Y: 50},
+		0, // Heading east
+		10,
+		1.0,
+		types.DefaultSensorAngles(),
+	)
+
+	steep := ReadSensors(&org, &linearGradientWorld{slope: 5.0}, 5.0)
+	shallow := ReadSensors(&org, &linearGradientWorld{slope: 0.1}, 5.0)
+
+	if steep.GradientMagnitude <= shallow.GradientMagnitude {
+		t.Errorf("Expected steep gradient magnitude (%f) to exceed shallow (%f)",
+			steep.GradientMagnitude, shallow.GradientMagnitude)
+	}
+
+	// Worlds that can't report gradients leave the reading at zero
+	plain := ReadSensors(&org, &mockWorld{concentrationFn: func(p types.Point) float64 { return p.X }}, 5.0)
+	if plain.GradientMagnitude != 0 {
+		t.Errorf("Expected zero gradient magnitude without gradient support, got %f", plain.GradientMagnitude)
+	}
+}
+
+func TestReadSensorsFieldOfView(t *testing.T) {
+	// Rich behind the organism, so a rear sensor would pull it around
+	world := &mockWorld{
+		concentrationFn: func(p types.Point) float64 {
+			if p.X < 50 {
+				return 100
+			}
+			return p.X
+		},
+	}
+
+	// newOrganism returns an organism heading east with the given sensor angles
+	newOrganism := func(angles [3]float64, fieldOfView float64) types.Organism {
+		org := types.NewOrganism(types.Point{X: 50, Y: 50}, 0, 10, 1.0, angles)
+		org.SensorFieldOfView = fieldOfView
+		return org
+	}
+
+	rearLeft := [3]float64{0, -3 * math.Pi / 4, math.Pi / 4}
+
+	t.Run("Sensor outside the field of view adds no reading", func(t *testing.T) {
+		org := newOrganism(rearLeft, math.Pi/2)
+		readings := ReadSensors(&org, world, 10.0)
+
+		if readings.Left != readings.Front {
+			t.Errorf("Blind left sensor read %v; want the front reading %v", readings.Left, readings.Front)
+		}
+		if want := 50 + 10*math.Cos(math.Pi/4); math.Abs(readings.Right-want) > 1e-9 {
+			t.Errorf("Right sensor read %v; want %v", readings.Right, want)
+		}
+		if direction := DecideDirection(readings, 100); direction == Left {
+			t.Error("Organism turned toward its blind spot")
+		}
+	})
+
+	t.Run("Without a field of view the rear sensor reads", func(t *testing.T) {
+		org := newOrganism(rearLeft, 0)
+		if readings := ReadSensors(&org, world, 10.0); readings.Left != 100 {
+			t.Errorf("Left sensor read %v; want 100", readings.Left)
+		}
+	})
+
+	t.Run("Blind front sensor reads at the organism", func(t *testing.T) {
+		org := newOrganism([3]float64{math.Pi, -math.Pi / 4, math.Pi / 4}, math.Pi/2)
+		if readings := ReadSensors(&org, world, 10.0); readings.Front != 50 {
+			t.Errorf("Blind front sensor read %v; want the concentration at the organism, 50", readings.Front)
+		}
+	})
+}
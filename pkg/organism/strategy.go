@@ -0,0 +1,117 @@
+package organism
+
+import (
+	"math"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// Lock-on constants
+const (
+	LockOnGradientThreshold = 0.05 // Change across the sensor reach, relative to the local concentration, needed to lock on
+	LockOnTolerance         = 0.01 // Worsening of the match, relative to the preference, that ends a lock
+)
+
+// Strategy decides which way an organism turns based on its sensor readings.
+// Strategies keep any state they need on the organism, since organisms are
+// copied between steps. A strategy that steers the organism itself turns it by
+// at most maxTurn radians per decision, the limit every other turn obeys.
+type Strategy interface {
+	Decide(org *types.Organism, readings SensorReadings, sensorDistance, maxTurn float64) Direction
+}
+
+// StrategyFor returns the strategy with the given config name. Unknown names,
+// including the empty name, select ClosestPreference.
+func StrategyFor(name string) Strategy {
+	if name == config.ForagingStrategyLockOn {
+		return LockOn{}
+	}
+	return ClosestPreference{}
+}
+
+// StrategyName returns the config name of the strategy StrategyFor selects for name
+func StrategyName(name string) string {
+	if name == config.ForagingStrategyLockOn {
+		return config.ForagingStrategyLockOn
+	}
+	return config.ForagingStrategyClosestPreference
+}
+
+// ClosestPreference greedily turns toward the sensor whose reading is closest to
+// the organism's preference, steering away from recently grazed patches if the
+// organism remembers any
+type ClosestPreference struct{}
+
+// Decide picks the sensor that best matches the preference
+func (ClosestPreference) Decide(org *types.Organism, readings SensorReadings, sensorDistance, maxTurn float64) Direction {
+	preference := org.EffectivePreference()
+	if len(org.FeedingMemory) > 0 {
+		return DecideDirectionAvoiding(readings, preference, org.GetSensorPositions(sensorDistance), org.FeedingMemory)
+	}
+	return DecideDirection(readings, preference)
+}
+
+// LockOn searches like ClosestPreference until it senses a strong enough gradient,
+// then turns along it (uphill or downhill, toward the preference) as fast as the
+// turn limit allows and holds that heading, ignoring minor fluctuations in the
+// sensors. The lock ends
+// once the front sensor's match to the preference stops improving, meaning the
+// organism has reached a local best, and the search resumes.
+type LockOn struct{}
+
+// Decide follows a locked gradient or searches for one
+func (LockOn) Decide(org *types.Organism, readings SensorReadings, sensorDistance, maxTurn float64) Direction {
+	preference := org.EffectivePreference()
+	match := math.Abs(readings.Front - preference)
+	tolerance := LockOnTolerance * math.Abs(preference)
+
+	if org.LockedOn {
+		// Finish turning onto the locked heading before judging progress along it
+		if turnToward(org, org.LockHeading, maxTurn) {
+			return Continue
+		}
+
+		if match > org.LockBestMatch+tolerance || match <= tolerance {
+			// Past the best point along the gradient, or already there
+			org.LockedOn = false
+		} else {
+			org.LockBestMatch = math.Min(org.LockBestMatch, match)
+			return Continue
+		}
+	}
+
+	// Lock on once the field changes enough across the sensors' reach, relative to
+	// the concentration here, so faint but clear trails far from a source still count
+	if readings.Front > 0 && match > tolerance &&
+		readings.GradientMagnitude*sensorDistance >= LockOnGradientThreshold*readings.Front {
+		heading := readings.GradientDirection
+		if readings.Front > preference {
+			heading += math.Pi // Head downhill toward a lower concentration
+		}
+		org.LockedOn = true
+		org.LockHeading = heading
+		org.LockBestMatch = math.MaxFloat64 // Nothing sensed along the new heading yet
+		turnToward(org, heading, maxTurn)
+		return Continue
+	}
+
+	return ClosestPreference{}.Decide(org, readings, sensorDistance, maxTurn)
+}
+
+// turnToward turns the organism toward heading the short way round, by at most
+// maxTurn radians, and reports whether it was still off that heading
+func turnToward(org *types.Organism, heading, maxTurn float64) bool {
+	// Difference wrapped into [-π, π)
+	difference := math.Mod(heading-org.Heading+math.Pi, 2*math.Pi)
+	if difference < 0 {
+		difference += 2 * math.Pi
+	}
+	difference -= math.Pi
+
+	if math.Abs(difference) < 1e-9 {
+		return false
+	}
+	org.Turn(math.Max(-maxTurn, math.Min(difference, maxTurn)))
+	return true
+}
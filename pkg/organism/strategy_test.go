@@ -0,0 +1,97 @@
+package organism
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// singleSourceWorld is a world with one chemical source that reports gradients
+type singleSourceWorld struct {
+	source types.ChemicalSource
+}
+
+func (w singleSourceWorld) GetConcentrationAt(p types.Point) float64 {
+	return w.source.GetConcentrationAt(p)
+}
+
+func (w singleSourceWorld) DepleteEnergyFromSourcesWithin(types.Point, float64, float64) {}
+
+func (w singleSourceWorld) GetConcentrationGradientVectorAt(p types.Point) types.Point {
+	const delta = 0.5
+	center := w.GetConcentrationAt(p)
+	return types.Point{
+		X: (w.GetConcentrationAt(types.Point{X: p.X + delta, Y: p.Y}) - center) / delta,
+		Y: (w.GetConcentrationAt(types.Point{X: p.X, Y: p.Y + delta}) - center) / delta,
+	}
+}
+
+func TestLockOnReachesSourceFaster(t *testing.T) {
+	bounds := types.Rect{Min: types.Point{X: 0, Y: 0}, Max: types.Point{X: 400, Y: 400}}
+	world := singleSourceWorld{source: types.NewChemicalSource(types.Point{X: 300, Y: 200}, 100.0, 0.001)}
+
+	// Steps until the organism gets within reach of the source, or -1 if it never does
+	stepsToReach := func(strategy string) int {
+		// Start off to the side, heading away from the source
+		org := types.NewOrganism(types.Point{X: 150, Y: 120}, math.Pi, 100.0, 1.0, types.DefaultSensorAngles())
+		org.ForagingStrategy = strategy
+		org.MetabolicRate = 0
+		org.MovementCost = 0
+		org.SensingCost = 0
+
+		for step := 1; step <= 20000; step++ {
+			Update(&org, world, bounds, 10.0, math.Pi/10, 1.0/60.0)
+			if org.Position.DistanceTo(world.source.Position) < 10 {
+				return step
+			}
+		}
+		return -1
+	}
+
+	greedy := stepsToReach(config.ForagingStrategyClosestPreference)
+	lockOn := stepsToReach(config.ForagingStrategyLockOn)
+
+	if lockOn < 0 {
+		t.Fatal("Expected the locked-on organism to reach the source")
+	}
+	if greedy >= 0 && lockOn >= greedy {
+		t.Errorf("Lock-on reached the source in %d steps; want fewer than greedy's %d", lockOn, greedy)
+	}
+}
+
+func TestLockOnReleasesAtPeak(t *testing.T) {
+	world := singleSourceWorld{source: types.NewChemicalSource(types.Point{X: 100, Y: 100}, 100.0, 0.001)}
+	bounds := types.Rect{Min: types.Point{X: 0, Y: 0}, Max: types.Point{X: 200, Y: 200}}
+
+	org := types.NewOrganism(types.Point{X: 40, Y: 100}, math.Pi/2, 100.0, 1.0, types.DefaultSensorAngles())
+	org.ForagingStrategy = config.ForagingStrategyLockOn
+	org.MetabolicRate = 0
+
+	// Lock on to the heading straight for the source, turning no faster than allowed
+	const turnSpeed, deltaTime = math.Pi / 10, 1.0 / 60.0
+	Update(&org, world, bounds, 10.0, turnSpeed, deltaTime)
+	if !org.LockedOn {
+		t.Fatal("Expected the organism to lock on to the strong gradient")
+	}
+	if math.Abs(math.Cos(org.LockHeading)-1) > 0.01 {
+		t.Errorf("Locked heading = %v; want to head straight for the source (0)", org.LockHeading)
+	}
+	if turned := math.Pi/2 - org.Heading; math.Abs(turned-turnSpeed*deltaTime) > 1e-9 {
+		t.Errorf("Turned %v on locking on; want the per-step limit %v", turned, turnSpeed*deltaTime)
+	}
+
+	// The lock is released once the front sensor passes the peak
+	released := false
+	for step := 0; step < 10000 && !released; step++ {
+		Update(&org, world, bounds, 10.0, turnSpeed, deltaTime)
+		released = !org.LockedOn
+	}
+	if !released {
+		t.Fatal("Expected the lock to end at the source")
+	}
+	if distance := org.Position.DistanceTo(world.source.Position); distance > 15 {
+		t.Errorf("Lock ended %v from the source; want it to end near the peak", distance)
+	}
+}
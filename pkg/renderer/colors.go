@@ -1,8 +1,11 @@
 package renderer
 
 import (
+	"fmt"
 	"image/color"
 	"math"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
 )
 
 // ColorScheme defines a color gradient to use for visualizations
@@ -78,6 +81,33 @@ var (
 	}
 )
 
+// ColorSchemeFromConfig builds a color scheme from a custom gradient definition.
+// The stops must be in strictly increasing order, starting at 0 and ending at 1.
+func ColorSchemeFromConfig(cfg config.ColorSchemeConfig) (ColorScheme, error) {
+	stops := cfg.Stops
+	if len(stops) < 2 {
+		return ColorScheme{}, fmt.Errorf("color scheme %q needs at least 2 stops, got %d", cfg.Name, len(stops))
+	}
+	if stops[0].Position != 0 || stops[len(stops)-1].Position != 1 {
+		return ColorScheme{}, fmt.Errorf("color scheme %q must span positions 0 to 1, got %v to %v",
+			cfg.Name, stops[0].Position, stops[len(stops)-1].Position)
+	}
+
+	scheme := ColorScheme{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		ColorStops:  make([]ColorStop, len(stops)),
+	}
+	for i, stop := range stops {
+		if i > 0 && stop.Position <= stops[i-1].Position {
+			return ColorScheme{}, fmt.Errorf("color scheme %q has stops out of order at position %v", cfg.Name, stop.Position)
+		}
+		scheme.ColorStops[i] = ColorStop{stop.Position, color.RGBA{stop.R, stop.G, stop.B, 255}}
+	}
+
+	return scheme, nil
+}
+
 // GetColorFromScheme returns an interpolated color from the scheme at the given position (0-1)
 func GetColorFromScheme(scheme ColorScheme, position float64) color.RGBA {
 	// Clamp position to 0-1 range
@@ -0,0 +1,49 @@
+package renderer
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+)
+
+func TestColorSchemeFromConfig(t *testing.T) {
+	t.Run("Custom scheme interpolates at midpoint", func(t *testing.T) {
+		scheme, err := ColorSchemeFromConfig(config.ColorSchemeConfig{
+			Name: "Grayscale",
+			Stops: []config.ColorStopConfig{
+				{Position: 0, R: 0, G: 0, B: 0},
+				{Position: 1, R: 255, G: 255, B: 255},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		want := color.RGBA{128, 128, 128, 255}
+		if got := GetColorFromScheme(scheme, 0.5); got != want {
+			t.Errorf("GetColorFromScheme(0.5) = %v; want %v", got, want)
+		}
+		if got := GetColorFromScheme(scheme, 1.0); got != (color.RGBA{255, 255, 255, 255}) {
+			t.Errorf("GetColorFromScheme(1.0) = %v; want white", got)
+		}
+	})
+
+	invalid := []struct {
+		name  string
+		stops []config.ColorStopConfig
+	}{
+		{"too few stops", []config.ColorStopConfig{{Position: 0}}},
+		{"does not start at 0", []config.ColorStopConfig{{Position: 0.2}, {Position: 1}}},
+		{"does not end at 1", []config.ColorStopConfig{{Position: 0}, {Position: 0.8}}},
+		{"out of order", []config.ColorStopConfig{{Position: 0}, {Position: 0.7}, {Position: 0.3}, {Position: 1}}},
+		{"duplicate position", []config.ColorStopConfig{{Position: 0}, {Position: 0.5}, {Position: 0.5}, {Position: 1}}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ColorSchemeFromConfig(config.ColorSchemeConfig{Name: "Bad", Stops: tt.stops}); err == nil {
+				t.Error("Expected an error for an invalid scheme")
+			}
+		})
+	}
+}
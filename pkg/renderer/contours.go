@@ -0,0 +1,181 @@
+package renderer
+
+import (
+	"math"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// Contour overlay constants
+const (
+	ContourResolution      = 60   // Samples along each axis when tracing contours
+	ContourRefreshInterval = 1.0  // Simulation seconds between contour refreshes
+	ContourLabelSpacing    = 40.0 // Minimum screen distance between contour labels
+)
+
+// ContourLevelFractions are the contour levels as fractions of the peak sampled concentration
+var ContourLevelFractions = []float64{0.2, 0.4, 0.6, 0.8}
+
+// ContourSegment is a straight piece of a contour line in world coordinates
+type ContourSegment struct {
+	Start types.Point
+	End   types.Point
+}
+
+// ContourLine is the set of segments where the concentration crosses a level
+type ContourLine struct {
+	Level    float64
+	Segments []ContourSegment
+}
+
+// contourLabel is a contour level label positioned in screen coordinates
+type contourLabel struct {
+	X, Y float64
+	Text string
+}
+
+// contourField is a scalar field sampled at the corners of a square grid
+type contourField struct {
+	corners  [][]types.Point
+	values   [][]float64
+	maxValue float64
+}
+
+// sampleContourField samples the field on a resolution x resolution grid of cells over bounds
+func sampleContourField(sample func(types.Point) float64, bounds types.Rect, resolution int) contourField {
+	if resolution < 1 {
+		return contourField{}
+	}
+
+	field := contourField{
+		corners: make([][]types.Point, resolution+1),
+		values:  make([][]float64, resolution+1),
+	}
+
+	stepX := bounds.Width / float64(resolution)
+	stepY := bounds.Height / float64(resolution)
+	for i := 0; i <= resolution; i++ {
+		field.corners[i] = make([]types.Point, resolution+1)
+		field.values[i] = make([]float64, resolution+1)
+		for j := 0; j <= resolution; j++ {
+			field.corners[i][j] = types.Point{X: bounds.X + float64(i)*stepX, Y: bounds.Y + float64(j)*stepY}
+			field.values[i][j] = sample(field.corners[i][j])
+			field.maxValue = math.Max(field.maxValue, field.values[i][j])
+		}
+	}
+
+	return field
+}
+
+// trace finds each level's contour using marching squares
+func (f contourField) trace(levels []float64) []ContourLine {
+	resolution := len(f.values) - 1
+	lines := make([]ContourLine, 0, len(levels))
+	for _, level := range levels {
+		line := ContourLine{Level: level}
+		for i := 0; i < resolution; i++ {
+			for j := 0; j < resolution; j++ {
+				// Corners in order around the cell, so consecutive pairs form its edges
+				xs := [4]int{i, i + 1, i + 1, i}
+				ys := [4]int{j, j, j + 1, j + 1}
+
+				var crossings []types.Point
+				for k := 0; k < 4; k++ {
+					a, b := k, (k+1)%4
+					va, vb := f.values[xs[a]][ys[a]], f.values[xs[b]][ys[b]]
+					if (va < level) == (vb < level) {
+						continue
+					}
+					t := (level - va) / (vb - va)
+					pa, pb := f.corners[xs[a]][ys[a]], f.corners[xs[b]][ys[b]]
+					crossings = append(crossings, types.Point{
+						X: pa.X + t*(pb.X-pa.X),
+						Y: pa.Y + t*(pb.Y-pa.Y),
+					})
+				}
+
+				// Pair crossings in edge order; saddle cells produce two segments
+				for k := 0; k+1 < len(crossings); k += 2 {
+					line.Segments = append(line.Segments, ContourSegment{Start: crossings[k], End: crossings[k+1]})
+				}
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// contourLevels returns the contour levels for a field whose peak is maxValue
+func contourLevels(maxValue float64) []float64 {
+	if maxValue <= 0 {
+		return nil
+	}
+
+	levels := make([]float64, len(ContourLevelFractions))
+	for i, fraction := range ContourLevelFractions {
+		levels[i] = maxValue * fraction
+	}
+	return levels
+}
+
+// representativePoint returns a point on the contour to label it, the midpoint of
+// the segment at the given index counted outward from the middle of the line
+func (c ContourLine) representativePoint(candidate int) (types.Point, bool) {
+	if candidate < 0 || candidate >= len(c.Segments) {
+		return types.Point{}, false
+	}
+
+	// Alternate either side of the middle segment: 0, +1, -1, +2, -2, ...
+	middle := len(c.Segments) / 2
+	offset := (candidate + 1) / 2
+	if candidate%2 == 0 {
+		offset = -offset
+	}
+	index := middle + offset
+	if index < 0 || index >= len(c.Segments) {
+		return types.Point{}, false
+	}
+
+	segment := c.Segments[index]
+	return types.Point{
+		X: (segment.Start.X + segment.End.X) / 2,
+		Y: (segment.Start.Y + segment.End.Y) / 2,
+	}, true
+}
+
+// placeContourLabels picks a label position for each contour, trying points
+// further along the line when the preferred one is too close to an earlier label.
+// Contours without room for a label are left unlabeled. Levels are written with format.
+func placeContourLabels(lines []ContourLine, toScreen func(types.Point) (float64, float64), format func(float64) string, minSpacing float64) []contourLabel {
+	labels := make([]contourLabel, 0, len(lines))
+
+	for _, line := range lines {
+		for candidate := 0; candidate < len(line.Segments); candidate++ {
+			point, ok := line.representativePoint(candidate)
+			if !ok {
+				continue
+			}
+
+			x, y := toScreen(point)
+			if labelCrowded(labels, x, y, minSpacing) {
+				continue
+			}
+
+			labels = append(labels, contourLabel{X: x, Y: y, Text: format(line.Level)})
+			break
+		}
+	}
+
+	return labels
+}
+
+// labelCrowded reports whether a label at (x, y) would be within minSpacing of an existing label
+func labelCrowded(labels []contourLabel, x, y, minSpacing float64) bool {
+	for _, label := range labels {
+		if math.Hypot(label.X-x, label.Y-y) < minSpacing {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// distanceToSegment returns the distance from p to the closest point on segment s
+func distanceToSegment(p types.Point, s ContourSegment) float64 {
+	dx, dy := s.End.X-s.Start.X, s.End.Y-s.Start.Y
+	lengthSquared := dx*dx + dy*dy
+	if lengthSquared == 0 {
+		return math.Hypot(p.X-s.Start.X, p.Y-s.Start.Y)
+	}
+	t := ((p.X-s.Start.X)*dx + (p.Y-s.Start.Y)*dy) / lengthSquared
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(p.X-(s.Start.X+t*dx), p.Y-(s.Start.Y+t*dy))
+}
+
+func TestContourRepresentativePoint(t *testing.T) {
+	// A radial field peaking at the center of the world
+	center := types.Point{X: 50, Y: 50}
+	radial := func(p types.Point) float64 {
+		return 100 - math.Hypot(p.X-center.X, p.Y-center.Y)
+	}
+	bounds := types.NewRect(0, 0, 100, 100)
+
+	field := sampleContourField(radial, bounds, 40)
+	lines := field.trace([]float64{70, 80, 90})
+
+	for _, line := range lines {
+		if len(line.Segments) == 0 {
+			t.Fatalf("Contour at level %v has no segments", line.Level)
+		}
+
+		for candidate := 0; candidate < len(line.Segments); candidate++ {
+			point, ok := line.representativePoint(candidate)
+			if !ok {
+				continue
+			}
+
+			onLine := false
+			for _, segment := range line.Segments {
+				if distanceToSegment(point, segment) < 1e-9 {
+					onLine = true
+					break
+				}
+			}
+			if !onLine {
+				t.Errorf("Representative point %v of level %v is not on the contour", point, line.Level)
+			}
+
+			// Each contour is a circle of radius 100 - level around the peak
+			radius := math.Hypot(point.X-center.X, point.Y-center.Y)
+			if math.Abs(radius-(100-line.Level)) > 0.5 {
+				t.Errorf("Representative point %v of level %v is %.2f from the peak; want about %v",
+					point, line.Level, radius, 100-line.Level)
+			}
+		}
+	}
+
+	if _, ok := (ContourLine{Level: 1}).representativePoint(0); ok {
+		t.Error("Expected no representative point for an empty contour")
+	}
+}
+
+func TestPlaceContourLabelsSpacing(t *testing.T) {
+	// Vertical contours one unit apart, each spanning the height of the world
+	var lines []ContourLine
+	for i := 0; i < 5; i++ {
+		x := 50 + float64(i)
+		line := ContourLine{Level: float64(i)}
+		for y := 0.0; y < 100; y += 10 {
+			line.Segments = append(line.Segments, ContourSegment{
+				Start: types.Point{X: x, Y: y},
+				End:   types.Point{X: x, Y: y + 10},
+			})
+		}
+		lines = append(lines, line)
+	}
+
+	identity := func(p types.Point) (float64, float64) { return p.X, p.Y }
+	minSpacing := 15.0
+	labels := placeContourLabels(lines, identity, func(level float64) string { return FormatQuantity(level, 0, "") }, minSpacing)
+
+	if len(labels) != len(lines) {
+		t.Fatalf("Placed %d labels; want %d", len(labels), len(lines))
+	}
+	for i := range labels {
+		for j := i + 1; j < len(labels); j++ {
+			distance := math.Hypot(labels[i].X-labels[j].X, labels[i].Y-labels[j].Y)
+			if distance < minSpacing {
+				t.Errorf("Labels %q and %q are %.2f apart; want at least %v",
+					labels[i].Text, labels[j].Text, distance, minSpacing)
+			}
+		}
+	}
+}
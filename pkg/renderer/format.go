@@ -0,0 +1,76 @@
+package renderer
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultSignificantFigures is the precision of displayed concentrations and
+// energies when the config doesn't set one
+const DefaultSignificantFigures = 3
+
+// quantitySuffixes abbreviate large values, largest first
+var quantitySuffixes = []struct {
+	scale  float64
+	suffix string
+}{
+	{1e9, "G"},
+	{1e6, "M"},
+	{1e3, "k"},
+}
+
+// FormatQuantity formats value to sigFigs significant figures, abbreviating
+// thousands and up with k, M and G, followed by unit if it isn't empty, so that
+// displays read sensibly whether sources have strength 100 or 5000
+func FormatQuantity(value float64, sigFigs int, unit string) string {
+	if sigFigs <= 0 {
+		sigFigs = DefaultSignificantFigures
+	}
+
+	text := formatSignificant(value, sigFigs)
+	if unit != "" {
+		text += " " + unit
+	}
+	return text
+}
+
+// formatConcentration formats a concentration or preference with the configured units and precision
+func (r *Renderer) formatConcentration(value float64) string {
+	return FormatQuantity(value, r.Config.Render.SignificantFigures, r.Config.Render.ConcentrationUnit)
+}
+
+// formatEnergy formats an energy amount with the configured units and precision
+func (r *Renderer) formatEnergy(value float64) string {
+	return FormatQuantity(value, r.Config.Render.SignificantFigures, r.Config.Render.EnergyUnit)
+}
+
+// formatLevel formats a concentration with the configured precision but no unit,
+// for labels drawn over the world where space is tight
+func (r *Renderer) formatLevel(value float64) string {
+	return FormatQuantity(value, r.Config.Render.SignificantFigures, "")
+}
+
+// formatSignificant formats value to sigFigs significant figures with an abbreviating suffix
+func formatSignificant(value float64, sigFigs int) string {
+	if value == 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Sprintf("%.*f", sigFigs-1, value)
+	}
+
+	// Round first, so that a value like 999.7 moves up to the next suffix as 1.00k
+	exponent := int(math.Floor(math.Log10(math.Abs(value))))
+	step := math.Pow(10, float64(exponent-sigFigs+1))
+	rounded := math.Round(value/step) * step
+
+	suffix := ""
+	for _, s := range quantitySuffixes {
+		if math.Abs(rounded) >= s.scale {
+			rounded /= s.scale
+			suffix = s.suffix
+			break
+		}
+	}
+
+	exponent = int(math.Floor(math.Log10(math.Abs(rounded))))
+	decimals := max(sigFigs-1-exponent, 0)
+	return fmt.Sprintf("%.*f%s", decimals, rounded, suffix)
+}
@@ -0,0 +1,32 @@
+package renderer
+
+import "testing"
+
+func TestFormatQuantity(t *testing.T) {
+	tests := []struct {
+		value   float64
+		sigFigs int
+		unit    string
+		want    string
+	}{
+		{0, 3, "", "0.00"},
+		{0.0123456, 3, "", "0.0123"},
+		{5, 3, "", "5.00"},
+		{42.678, 3, "", "42.7"},
+		{-42.678, 3, "", "-42.7"},
+		{100, 3, "mM", "100 mM"},
+		{999.7, 3, "", "1.00k"},
+		{5000, 3, "", "5.00k"},
+		{123456, 3, "J", "123k J"},
+		{2500000, 2, "", "2.5M"},
+		{7.25e9, 3, "", "7.25G"},
+		{5000, 0, "", "5.00k"},
+		{123.456, 5, "", "123.46"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatQuantity(tt.value, tt.sigFigs, tt.unit); got != tt.want {
+			t.Errorf("FormatQuantity(%v, %d, %q) = %q; want %q", tt.value, tt.sigFigs, tt.unit, got, tt.want)
+		}
+	}
+}
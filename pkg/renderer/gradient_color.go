@@ -0,0 +1,73 @@
+package renderer
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/zachbeta/evolve_sim/pkg/organism"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// OrganismColorMode selects what organisms are colored by
+type OrganismColorMode int
+
+// Organism coloring modes, cycled with the O key
+const (
+	OrganismColorPreference OrganismColorMode = iota // Chemical preference, blue to red
+	OrganismColorGradient                            // Steepness of the concentration field underfoot
+)
+
+// String returns the display name of the organism coloring mode
+func (m OrganismColorMode) String() string {
+	switch m {
+	case OrganismColorGradient:
+		return "gradient"
+	default:
+		return "preference"
+	}
+}
+
+// Colors for organisms on flat and steep ground in the gradient coloring mode
+var (
+	FlatGradientColor  = color.RGBA{70, 80, 120, 255}
+	SteepGradientColor = color.RGBA{255, 220, 60, 255}
+)
+
+// gradientSteepness measures how informative the field around an organism is: the
+// concentration change its sensors can see across sensorDistance, relative to the
+// smallest difference it can act on. Below 1 its readings are a near-tie and it
+// wanders; well above 1 it can follow the slope.
+func gradientSteepness(gradient types.Point, sensorDistance, preference float64) float64 {
+	threshold := organism.AmbiguityTolerance * math.Abs(preference)
+	if threshold <= 0 {
+		return 0
+	}
+
+	change := math.Hypot(gradient.X, gradient.Y) * math.Abs(sensorDistance)
+	return change / threshold
+}
+
+// gradientColor maps a steepness to a color from FlatGradientColor to
+// SteepGradientColor, halfway between the two where the sensors can just tell
+// directions apart
+func gradientColor(steepness float64) color.RGBA {
+	if !(steepness > 0) {
+		return FlatGradientColor
+	}
+
+	// Saturating curve, so a wide range of steepness stays distinguishable
+	t := steepness / (1 + steepness)
+	if math.IsInf(steepness, 1) {
+		t = 1
+	}
+
+	lerp := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a) + (float64(b)-float64(a))*t))
+	}
+	return color.RGBA{
+		lerp(FlatGradientColor.R, SteepGradientColor.R),
+		lerp(FlatGradientColor.G, SteepGradientColor.G),
+		lerp(FlatGradientColor.B, SteepGradientColor.B),
+		255,
+	}
+}
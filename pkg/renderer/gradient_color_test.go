@@ -0,0 +1,58 @@
+package renderer
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+func TestGradientSteepness(t *testing.T) {
+	// A change of AmbiguityTolerance*preference across the sensors is exactly 1
+	gradient := types.Point{X: 0.06, Y: 0.08} // Length 0.1
+	if got := gradientSteepness(gradient, 10, 50); math.Abs(got-1) > 1e-9 {
+		t.Errorf("gradientSteepness() = %v; want 1", got)
+	}
+	if got := gradientSteepness(gradient, 10, 0); got != 0 {
+		t.Errorf("gradientSteepness() with no preference = %v; want 0", got)
+	}
+}
+
+func TestGradientColor(t *testing.T) {
+	midpoint := color.RGBA{163, 150, 90, 255}
+
+	tests := []struct {
+		name      string
+		steepness float64
+		want      color.RGBA
+	}{
+		{"Flat", 0, FlatGradientColor},
+		{"Negative", -1, FlatGradientColor},
+		{"NaN", math.NaN(), FlatGradientColor},
+		{"Just informative", 1, midpoint},
+		{"Infinite", math.Inf(1), SteepGradientColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gradientColor(tt.steepness); got != tt.want {
+				t.Errorf("gradientColor(%v) = %v; want %v", tt.steepness, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Steeper is closer to the steep color", func(t *testing.T) {
+		previous := math.Inf(1)
+		for _, steepness := range []float64{0.01, 0.1, 1, 10, 100, 1000} {
+			c := gradientColor(steepness)
+			distance := math.Abs(float64(c.R)-float64(SteepGradientColor.R)) +
+				math.Abs(float64(c.G)-float64(SteepGradientColor.G)) +
+				math.Abs(float64(c.B)-float64(SteepGradientColor.B))
+			if distance > previous {
+				t.Errorf("gradientColor(%v) = %v is further from the steep color than a flatter slope", steepness, c)
+			}
+			previous = distance
+		}
+	})
+}
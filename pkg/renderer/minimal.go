@@ -0,0 +1,49 @@
+package renderer
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+// Dot sizes in the minimal organism render mode, in screen pixels
+const (
+	MinimalDotMinRadius = 1.5 // Radius of an organism with no energy left
+	MinimalDotMaxRadius = 4.0 // Radius of an organism at full capacity
+)
+
+// organismDrawPath selects how drawOrganisms renders each organism
+type organismDrawPath int
+
+const (
+	organismDrawDetailed organismDrawPath = iota // Triangle, border, energy bar, glow, trail, sensors and label
+	organismDrawMinimal                          // A single dot sized by energy
+)
+
+// organismDrawPath returns the draw path for the current render mode
+func (r *Renderer) organismDrawPath() organismDrawPath {
+	if r.MinimalOrganisms {
+		return organismDrawMinimal
+	}
+	return organismDrawDetailed
+}
+
+// minimalDotRadius returns the dot radius for an organism with the given energy
+// ratio, clamped to the 0-1 range
+func minimalDotRadius(energyRatio float64) float64 {
+	energyRatio = math.Max(0, math.Min(1, energyRatio))
+	return MinimalDotMinRadius + (MinimalDotMaxRadius-MinimalDotMinRadius)*energyRatio
+}
+
+// drawOrganismDot draws an organism as a single dot in its base color, sized by
+// its energy
+func (r *Renderer) drawOrganismDot(screen *ebiten.Image, snapshot world.Snapshot, org types.Organism, screenX, screenY float64) {
+	red, green, blue := r.organismBaseColor(snapshot, org)
+	radius := minimalDotRadius(org.Energy / org.EnergyCapacity)
+	vector.DrawFilledCircle(screen, float32(screenX), float32(screenY), float32(radius),
+		color.RGBA{red, green, blue, 255}, false)
+}
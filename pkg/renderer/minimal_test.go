@@ -0,0 +1,65 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+func TestMinimalOrganismsDrawPath(t *testing.T) {
+	snapshot := world.Snapshot{Organisms: []types.Organism{
+		{ID: 1, Position: types.Point{X: 100, Y: 100}, Energy: 50, EnergyCapacity: 100},
+		{ID: 2, Position: types.Point{X: 900, Y: 900}, Energy: 100, EnergyCapacity: 100},
+		{ID: 3, Position: types.Point{X: 5000, Y: 5000}, Energy: 100, EnergyCapacity: 100}, // Off screen
+	}}
+
+	for _, tt := range []struct {
+		name    string
+		minimal bool
+		want    organismDrawPath
+	}{
+		{"Detailed by default", false, organismDrawDetailed},
+		{"Minimal when toggled", true, organismDrawMinimal},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Renderer{
+				World:            world.NewWorld(config.SimulationConfig{World: config.WorldConfig{Width: 1000, Height: 1000}}),
+				WindowWidth:      800,
+				WindowHeight:     800,
+				MinimalOrganisms: tt.minimal,
+			}
+
+			var drawn []int64
+			r.drawOrganismHook = func(path organismDrawPath, org types.Organism) {
+				if path != tt.want {
+					t.Errorf("Organism %d drawn with path %d; want %d", org.ID, path, tt.want)
+				}
+				drawn = append(drawn, org.ID)
+			}
+			r.drawOrganisms(nil, snapshot)
+
+			if len(drawn) != 2 {
+				t.Errorf("Drew organisms %v; want the two on screen", drawn)
+			}
+		})
+	}
+}
+
+func TestMinimalDotRadius(t *testing.T) {
+	for _, tt := range []struct {
+		energyRatio float64
+		want        float64
+	}{
+		{0, MinimalDotMinRadius},
+		{1, MinimalDotMaxRadius},
+		{0.5, (MinimalDotMinRadius + MinimalDotMaxRadius) / 2},
+		{-1, MinimalDotMinRadius},
+		{2, MinimalDotMaxRadius},
+	} {
+		if got := minimalDotRadius(tt.energyRatio); got != tt.want {
+			t.Errorf("minimalDotRadius(%v) = %v; want %v", tt.energyRatio, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package renderer
+
+import "math"
+
+// DefaultPreferenceColorRange is the preference drawn fully red when the starting
+// preference distribution has no positive mean to scale colors by. It matches
+// the range the default config gives.
+const DefaultPreferenceColorRange = 150.0
+
+// preferenceColorRange returns the preference drawn fully red: three times the
+// mean of the starting preference distribution, or DefaultPreferenceColorRange
+// when that isn't positive, as for a population that prefers zero concentration
+func preferenceColorRange(mean float64) float64 {
+	if prefRange := mean * 3; prefRange > 0 && !math.IsInf(prefRange, 1) {
+		return prefRange
+	}
+	return DefaultPreferenceColorRange
+}
+
+// preferenceColor maps a chemical preference to a blue-to-red gradient, blue at
+// zero and below and red at prefRange and above
+func preferenceColor(preference, prefRange float64) (uint8, uint8, uint8) {
+	normalizedPref := preference / prefRange
+	if !(normalizedPref > 0) {
+		normalizedPref = 0
+	}
+	normalizedPref = math.Min(normalizedPref, 1)
+
+	red := uint8(normalizedPref * 255)
+	blue := uint8((1 - normalizedPref) * 255)
+	green := uint8(128 - math.Abs(normalizedPref*255-128))
+	return red, green, blue
+}
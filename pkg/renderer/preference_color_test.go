@@ -0,0 +1,56 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPreferenceColorRange(t *testing.T) {
+	tests := []struct {
+		name string
+		mean float64
+		want float64
+	}{
+		{"Positive mean", 50, 150},
+		{"Zero mean", 0, DefaultPreferenceColorRange},
+		{"Negative mean", -10, DefaultPreferenceColorRange},
+		{"NaN mean", math.NaN(), DefaultPreferenceColorRange},
+		{"Infinite mean", math.Inf(1), DefaultPreferenceColorRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := preferenceColorRange(tt.mean); got != tt.want {
+				t.Errorf("preferenceColorRange(%v) = %v; want %v", tt.mean, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreferenceColor(t *testing.T) {
+	type rgb struct{ r, g, b uint8 }
+	tests := []struct {
+		name       string
+		preference float64
+		mean       float64
+		want       rgb
+	}{
+		{"Zero preference", 0, 50, rgb{0, 0, 255}},
+		{"Middle of the range", 75, 50, rgb{127, 127, 127}},
+		{"Top of the range", 150, 50, rgb{255, 1, 0}},
+		{"Beyond the range", 400, 50, rgb{255, 1, 0}},
+		{"Negative preference", -5, 50, rgb{0, 0, 255}},
+		{"Zero mean, zero preference", 0, 0, rgb{0, 0, 255}},
+		{"Zero mean, some preference", 15, 0, rgb{25, 25, 229}},
+		{"NaN preference", math.NaN(), 0, rgb{0, 0, 255}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, g, b := preferenceColor(tt.preference, preferenceColorRange(tt.mean))
+			if got := (rgb{r, g, b}); got != tt.want {
+				t.Errorf("preferenceColor(%v) with mean %v = %v; want %v", tt.preference, tt.mean, got, tt.want)
+			}
+		})
+	}
+}
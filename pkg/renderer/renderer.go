@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image/color"
 	"math"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
@@ -19,6 +20,52 @@ type ReproductionEvent struct {
 	TimeLeft float64     // Time left for this effect (in seconds)
 }
 
+// DeathEvent tracks the fading ring drawn where an organism died
+type DeathEvent struct {
+	Position types.Point // Position of the death
+	TimeLeft float64     // Time left for this effect (in seconds)
+}
+
+// Depletion indicator constants
+const (
+	DepletionIndicatorFullScale = 0.01 // Fraction of max energy lost per second that draws a full-length arrow
+	DepletionIndicatorSmoothing = 0.2  // Weight of the newest sample in the smoothed depletion rate
+)
+
+// TrailColorMode selects what trail segments are colored by
+type TrailColorMode int
+
+// Trail coloring modes, cycled with the H key
+const (
+	TrailColorOrganism TrailColorMode = iota // The organism's current color
+	TrailColorEnergy                         // Energy recorded at each trail point
+	TrailColorSpeed                          // Distance covered between trail points
+)
+
+// String returns the display name of the trail coloring mode
+func (m TrailColorMode) String() string {
+	switch m {
+	case TrailColorEnergy:
+		return "energy"
+	case TrailColorSpeed:
+		return "speed"
+	default:
+		return "organism"
+	}
+}
+
+// MaxProbes is the number of preference probes kept; placing more drops the oldest
+const MaxProbes = 10
+
+// DormantBrightness scales the brightness of dormant organisms
+const DormantBrightness = 0.3
+
+// Lineage panel constants
+const (
+	LineagePanelSize            = 5   // Number of lineages listed
+	LineagePanelRefreshInterval = 1.0 // Simulation seconds between refreshes
+)
+
 // Renderer is responsible for visualizing the simulation
 type Renderer struct {
 	World               *world.World
@@ -30,6 +77,14 @@ type Renderer struct {
 	ShowSensors         bool
 	ShowLegend          bool
 	ShowTrails          bool
+	TrailColorMode      TrailColorMode
+	OrganismColorMode   OrganismColorMode
+	MinimalOrganisms    bool // Draw organisms as plain dots sized by energy, for dense populations
+	camera              camera
+	panDragging         bool // Whether a middle-button drag was under way last frame
+	panDragX, panDragY  int  // Cursor position when the drag was last applied
+	ShowLineages        bool
+	ShowContours        bool
 	Stats               simulation.SimulationStats
 	FPS                 float64
 	keyStates           map[ebiten.Key]bool
@@ -39,9 +94,32 @@ type Renderer struct {
 	interpolationFactor float64 // For smooth animations between frames
 	triangleImage       *ebiten.Image
 	triangleOpts        ebiten.DrawImageOptions
-	selectedOrganism    *types.Organism     // For future organism selection feature
+	selectedOrganism    *types.Organism     // Organism picked by clicking, kept up to date each frame
 	reproductionEvents  []ReproductionEvent // Track reproduction visual effects
-	previousOrgCount    int                 // To detect reproduction events
+	deathEvents         []DeathEvent        // Track death visual effects
+
+	// Per-source depletion tracking, keyed by source position
+	previousSourceEnergy map[types.Point]float64 // Source energy seen last frame
+	sourceDepletion      map[types.Point]float64 // Smoothed depletion indicator magnitude (0-1)
+
+	// Dominant lineages, refreshed periodically for the lineage panel
+	topLineages         []world.LineageShare
+	lineageRefreshTimer float64
+
+	heatDeath bool // Whether the world's energy has collapsed for good
+
+	// Preference probes placed with Shift+click, showing the optimal preference there
+	probes []types.Point
+
+	clickHeld bool // Whether the left mouse button was down last frame
+
+	// Concentration contours, refreshed periodically for the contour overlay
+	contours            []ContourLine
+	contourRefreshTimer float64
+
+	// Called instead of drawing each visible organism when set; lets tests see
+	// which draw path was chosen without a screen
+	drawOrganismHook func(path organismDrawPath, org types.Organism)
 }
 
 // NewRenderer creates a new renderer with the specified world and config
@@ -54,8 +132,15 @@ func NewRenderer(world *world.World, simulator *simulation.Simulator, config con
 		ClassicScheme,
 	}
 
-	// Get initial organism count
-	initialCount, _ := world.GetPopulationInfo()
+	// Add any custom schemes from the config, skipping invalid ones
+	for _, schemeConfig := range config.Render.ColorSchemes {
+		scheme, err := ColorSchemeFromConfig(schemeConfig)
+		if err != nil {
+			fmt.Printf("Ignoring custom color scheme: %v\n", err)
+			continue
+		}
+		colorSchemes = append(colorSchemes, scheme)
+	}
 
 	// Create renderer
 	renderer := &Renderer{
@@ -75,8 +160,11 @@ func NewRenderer(world *world.World, simulator *simulation.Simulator, config con
 		CurrentSchemeIndex:  0,
 		interpolationFactor: 0.5, // Default interpolation for animations
 		reproductionEvents:  make([]ReproductionEvent, 0),
-		previousOrgCount:    initialCount,
+
+		previousSourceEnergy: make(map[types.Point]float64),
+		sourceDepletion:      make(map[types.Point]float64),
 	}
+	renderer.camera = newCamera(world.GetBounds(), config.Render)
 
 	// Create triangle image for optimized drawing
 	renderer.triangleImage = ebiten.NewImage(16, 16)
@@ -84,6 +172,7 @@ func NewRenderer(world *world.World, simulator *simulation.Simulator, config con
 
 	// Register with the simulator to receive reproduction events
 	simulator.SetReproductionHandler(renderer.AddReproductionEvent)
+	simulator.SetDeathHandler(renderer.AddDeathEvent)
 
 	return renderer
 }
@@ -124,6 +213,21 @@ func (r *Renderer) Update() error {
 		r.ShowTrails = !r.ShowTrails
 	}
 
+	// H: Cycle trail coloring (organism color, energy, speed)
+	if r.isKeyJustPressed(ebiten.KeyH) {
+		r.TrailColorMode = (r.TrailColorMode + 1) % 3
+	}
+
+	// O: Cycle organism coloring (preference, gradient steepness)
+	if r.isKeyJustPressed(ebiten.KeyO) {
+		r.OrganismColorMode = (r.OrganismColorMode + 1) % 2
+	}
+
+	// D: Toggle the minimal dot rendering of organisms
+	if r.isKeyJustPressed(ebiten.KeyD) {
+		r.MinimalOrganisms = !r.MinimalOrganisms
+	}
+
 	// M: Cycle color schemes
 	if r.isKeyJustPressed(ebiten.KeyM) {
 		r.CurrentSchemeIndex = (r.CurrentSchemeIndex + 1) % len(r.ColorSchemes)
@@ -132,9 +236,38 @@ func (r *Renderer) Update() error {
 
 	// R: Reset simulation
 	if r.isKeyJustPressed(ebiten.KeyR) {
-		r.Simulator.Reset()
+		r.resetSimulation()
 	}
 
+	// N: Toggle the lineage panel
+	if r.isKeyJustPressed(ebiten.KeyN) {
+		r.ShowLineages = !r.ShowLineages
+		r.lineageRefreshTimer = 0 // Refresh right away
+	}
+
+	// C: Toggle concentration contours
+	if r.isKeyJustPressed(ebiten.KeyC) {
+		r.ShowContours = !r.ShowContours
+		r.contourRefreshTimer = 0 // Refresh right away
+	}
+
+	// Arrow keys: Pan; mouse wheel: Zoom; Home: Return to the home view
+	r.updateCamera()
+
+	// Click: Select an organism; Shift+click: Place a preference probe; X: Clear probes
+	r.updateMouse()
+	if r.isKeyJustPressed(ebiten.KeyX) {
+		r.probes = nil
+	}
+
+	// E: Export the current world as a scenario
+	if r.isKeyJustPressed(ebiten.KeyE) {
+		r.exportScenario()
+	}
+
+	// Hold B: Bullet-time while an organism is selected
+	r.Simulator.SetBulletTime(r.selectedOrganism != nil && ebiten.IsKeyPressed(ebiten.KeyB))
+
 	// +: Increase simulation speed
 	if r.isKeyJustPressed(ebiten.KeyEqual) {
 		r.Simulator.SetSimulationSpeed(r.Simulator.SimulationSpeed * 1.5)
@@ -148,15 +281,43 @@ func (r *Renderer) Update() error {
 	// Step the simulation
 	r.Simulator.Step()
 
+	// Follow the selected organism, dropping it once it dies
+	r.refreshSelection()
+
 	// Update FPS counter
 	r.FPS = ebiten.CurrentFPS()
 
-	// Update reproduction events
+	// Update reproduction and death events
 	r.updateReproductionEvents(r.Simulator.TimeStep * r.Simulator.SimulationSpeed)
+	r.updateDeathEvents(r.Simulator.TimeStep * r.Simulator.SimulationSpeed)
+
+	// Track how quickly each source is being drained
+	if !r.Simulator.IsPaused {
+		r.updateSourceDepletion(r.Simulator.TimeStep * r.Simulator.SimulationSpeed)
+	}
 
 	// Update statistics
 	stats := simulation.CalculateStatistics(r.World, r.Simulator.Time)
 	r.Stats = stats
+	r.heatDeath = r.World.HeatDeath()
+
+	// Refresh the lineage rankings periodically while the panel is shown
+	if r.ShowLineages {
+		r.lineageRefreshTimer -= r.Simulator.TimeStep * r.Simulator.SimulationSpeed
+		if r.lineageRefreshTimer <= 0 {
+			r.topLineages = world.TopLineages(r.World.GetOrganisms(), LineagePanelSize)
+			r.lineageRefreshTimer = LineagePanelRefreshInterval
+		}
+	}
+
+	// Retrace the contours periodically while the overlay is shown
+	if r.ShowContours {
+		r.contourRefreshTimer -= r.Simulator.TimeStep * r.Simulator.SimulationSpeed
+		if r.contourRefreshTimer <= 0 {
+			r.contours = r.traceWorldContours()
+			r.contourRefreshTimer = ContourRefreshInterval
+		}
+	}
 
 	return nil
 }
@@ -166,6 +327,9 @@ func (r *Renderer) Draw(screen *ebiten.Image) {
 	// Clear the screen with a dark background
 	screen.Fill(color.RGBA{20, 20, 25, 255})
 
+	// Draw the whole frame from one consistent copy of the world
+	snapshot := r.World.Snapshot()
+
 	// Draw concentration grid if available
 	r.drawChemicalConcentration(screen)
 
@@ -174,44 +338,126 @@ func (r *Renderer) Draw(screen *ebiten.Image) {
 		r.drawGrid(screen)
 	}
 
+	// Draw concentration contours if enabled
+	if r.ShowContours {
+		r.drawContours(screen)
+	}
+
 	// Draw chemical sources
-	r.drawChemicalSources(screen)
+	r.drawChemicalSources(screen, snapshot.ChemicalSources)
 
 	// Draw organisms
-	r.drawOrganisms(screen)
+	r.drawOrganisms(screen, snapshot)
 
-	// Draw reproduction events
+	// Draw reproduction and death events
 	r.drawReproductionEvents(screen)
+	r.drawDeathEvents(screen)
+
+	// Draw preference probes
+	r.drawProbes(screen, snapshot)
+
+	// Draw the selected organism's details
+	r.drawSelection(screen)
 
 	// Draw legend if enabled
 	if r.ShowLegend {
 		r.drawLegend(screen)
 	}
 
+	// Draw lineage panel if enabled
+	if r.ShowLineages {
+		r.drawLineagePanel(screen)
+	}
+
 	// Draw statistics
 	r.drawStats(screen)
+
+	// Draw the statistics sidebar when there's room beside the world view
+	if r.showSidebar() {
+		r.drawSidebar(screen, snapshot.ChemicalSources)
+	}
+
+	// Warn that the run is effectively over
+	if r.heatDeath {
+		r.drawHeatDeathBanner(screen)
+	}
 }
 
-// Layout returns the logical screen dimensions
+// Layout adopts the window's current size as the logical screen size, so the view
+// and everything laid out from WindowWidth and WindowHeight follow window resizes
 func (r *Renderer) Layout(outsideWidth, outsideHeight int) (int, int) {
+	if outsideWidth > 0 && outsideHeight > 0 {
+		r.WindowWidth, r.WindowHeight = outsideWidth, outsideHeight
+	}
 	return r.WindowWidth, r.WindowHeight
 }
 
-// Helper method to convert world coordinates to screen coordinates
-func (r *Renderer) worldToScreen(point types.Point) (float64, float64) {
-	bounds := r.World.GetBounds()
-	width := bounds.Max.X - bounds.Min.X
-	height := bounds.Max.Y - bounds.Min.Y
+// screenToWorld converts screen coordinates back to world coordinates
+func (r *Renderer) screenToWorld(screenX, screenY float64) types.Point {
+	return r.camera.viewport(r.World.GetBounds(), r.mainViewWidth(), r.WindowHeight).toWorld(screenX, screenY)
+}
+
+// updateCamera pans with the arrow keys or a middle-button drag, zooms toward the
+// cursor with the mouse wheel and returns to the home view on Home
+func (r *Renderer) updateCamera() {
+	if r.isKeyJustPressed(ebiten.KeyHome) {
+		r.camera.goHome()
+	}
+
+	// Zoom toward the cursor, so what's under it stays put
+	cursorX, cursorY := ebiten.CursorPosition()
+	if _, wheel := ebiten.Wheel(); wheel != 0 {
+		r.camera.zoomAt(math.Pow(CameraZoomStep, wheel), r.screenToWorld(float64(cursorX), float64(cursorY)))
+	}
+
+	// Middle-drag to pan, moving the world along with the cursor
+	view := r.camera.viewport(r.World.GetBounds(), r.mainViewWidth(), r.WindowHeight)
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonMiddle) {
+		if r.panDragging {
+			r.camera.pan(float64(r.panDragX-cursorX)/view.scale, float64(r.panDragY-cursorY)/view.scale)
+		}
+		r.panDragging, r.panDragX, r.panDragY = true, cursorX, cursorY
+	} else {
+		r.panDragging = false
+	}
+
+	// Pan a fixed fraction of the view per second, however far in the camera is
+	step := CameraPanSpeed * float64(r.mainViewWidth()) / view.scale / float64(ebiten.TPS())
+	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+		r.camera.pan(-step, 0)
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+		r.camera.pan(step, 0)
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+		r.camera.pan(0, -step)
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+		r.camera.pan(0, step)
+	}
 
-	// Convert world coordinates to normalized coordinates (0-1)
-	normalizedX := (point.X - bounds.Min.X) / width
-	normalizedY := (point.Y - bounds.Min.Y) / height
+	r.camera.clamp(r.World.GetBounds(), r.mainViewWidth(), r.WindowHeight)
+}
 
-	// Convert normalized coordinates to screen coordinates
-	screenX := normalizedX * float64(r.WindowWidth)
-	screenY := normalizedY * float64(r.WindowHeight)
+// drawProbes marks each probe and labels it with the concentration there, which
+// is the preference an organism would need to thrive at that spot
+func (r *Renderer) drawProbes(screen *ebiten.Image, snapshot world.Snapshot) {
+	probeColor := color.RGBA{255, 255, 255, 220}
+	for _, probe := range r.probes {
+		x, y := r.worldToScreen(probe)
+		ebitenutil.DrawLine(screen, x-5, y, x+5, y, probeColor)
+		ebitenutil.DrawLine(screen, x, y-5, x, y+5, probeColor)
+
+		preference := types.OptimalPreferenceAt(snapshot, probe)
+		label := fmt.Sprintf("c=%s  optimal pref=%s", r.formatConcentration(snapshot.GetConcentrationAt(probe)), r.formatConcentration(preference))
+		ebitenutil.DebugPrintAt(screen, label, int(x)+8, int(y)-8)
+	}
+}
 
-	return screenX, screenY
+// Helper method to convert world coordinates to screen coordinates.
+// The world is letterboxed so that its aspect ratio is preserved.
+func (r *Renderer) worldToScreen(point types.Point) (float64, float64) {
+	return r.camera.viewport(r.World.GetBounds(), r.mainViewWidth(), r.WindowHeight).toScreen(point)
 }
 
 // Draw a visualization of chemical concentration - removed for performance
@@ -221,10 +467,7 @@ func (r *Renderer) drawChemicalConcentration(screen *ebiten.Image) {
 }
 
 // Draw chemical sources
-func (r *Renderer) drawChemicalSources(screen *ebiten.Image) {
-	// Get chemical sources
-	sources := r.World.GetChemicalSources()
-
+func (r *Renderer) drawChemicalSources(screen *ebiten.Image, sources []types.ChemicalSource) {
 	// Draw each chemical source
 	for _, source := range sources {
 		// Skip inactive sources
@@ -275,224 +518,347 @@ func (r *Renderer) drawChemicalSources(screen *ebiten.Image) {
 				screen.Set(cx, cy, outlineColor)
 			}
 		}
+
+		// Draw a downward arrow beside sources under pressure, longer when draining faster
+		if magnitude := r.sourceDepletion[source.Position]; magnitude > 0.05 {
+			arrowX := x + radius + 4
+			arrowLength := 4 + 20*magnitude
+			arrowColor := color.RGBA{255, 80, 60, uint8(120 + 135*magnitude)}
+			tipY := y + arrowLength/2
+			ebitenutil.DrawLine(screen, arrowX, y-arrowLength/2, arrowX, tipY, arrowColor)
+			ebitenutil.DrawLine(screen, arrowX-3, tipY-3, arrowX, tipY, arrowColor)
+			ebitenutil.DrawLine(screen, arrowX+3, tipY-3, arrowX, tipY, arrowColor)
+		}
 	}
 }
 
-// Draw organisms
-func (r *Renderer) drawOrganisms(screen *ebiten.Image) {
-	organisms := r.World.GetOrganisms()
-	currentTime := r.Simulator.Time // Get current simulation time for animations
-
-	for _, org := range organisms {
-		// Convert world coordinates to screen coordinates
-		screenX, screenY := r.worldToScreen(org.Position)
-
-		// Determine base color based on chemical preference
-		// Map preference to a blue-to-red gradient
-		prefRange := r.Config.Organism.PreferenceDistributionMean * 3
-		normalizedPref := org.ChemPreference / prefRange
-
-		baseRed := uint8(normalizedPref * 255)
-		baseBlue := uint8((1 - normalizedPref) * 255)
-		baseGreen := uint8(128 - math.Abs(float64(normalizedPref*255-128)))
-
-		// Modify color based on energy level
-		// Low energy organisms appear darker/more transparent
-		energyRatio := org.Energy / org.EnergyCapacity
+// resetSimulation restarts the simulation in a fresh world. Everything the renderer
+// holds about the old world's organisms and sources is dropped, since none of it
+// exists any more; view and display settings are kept.
+func (r *Renderer) resetSimulation() {
+	r.Simulator.Reset()
+
+	r.selectedOrganism = nil
+	r.probes = nil
+	r.reproductionEvents = r.reproductionEvents[:0]
+	r.deathEvents = r.deathEvents[:0]
+	r.previousSourceEnergy = make(map[types.Point]float64)
+	r.sourceDepletion = make(map[types.Point]float64)
+	r.heatDeath = false
+
+	// Rebuild the lineage panel and contours from the new world right away
+	r.topLineages = nil
+	r.lineageRefreshTimer = 0
+	r.contours = nil
+	r.contourRefreshTimer = 0
+}
 
-		// Critical energy effect (pulsing when below 20%)
-		var pulseEffect float64 = 1.0
-		if energyRatio < 0.2 {
-			// Create a pulsing effect based on time
-			pulseFrequency := 5.0                                                 // pulses per second
-			pulseAmount := 0.5 + 0.5*math.Sin(currentTime*pulseFrequency*math.Pi) // 0.5-1.5 range
+// updateSourceDepletion samples the live energy of each source and updates its
+// smoothed depletion indicator. The indicator covers every net loss: organisms
+// grazing within their feeding radius (with feedingDepletion on), decay, and the
+// environment cost of births.
+func (r *Renderer) updateSourceDepletion(deltaTime float64) {
+	sources := r.World.GetChemicalSources()
+	seen := make(map[types.Point]bool, len(sources))
 
-			// Make pulse more intense as energy decreases
-			pulseIntensity := 1.0 - (energyRatio / 0.2) // 0-1 range as energy drops from 20% to 0%
-			pulseEffect = 1.0 + (pulseAmount-1.0)*pulseIntensity
+	for _, source := range sources {
+		seen[source.Position] = true
 
-			// Apply pulse to color intensity
-			energyRatio = math.Min(1.0, energyRatio*pulseEffect)
+		previous, ok := r.previousSourceEnergy[source.Position]
+		r.previousSourceEnergy[source.Position] = source.Energy
+		if !ok {
+			continue
 		}
 
-		red := uint8(float64(baseRed) * math.Sqrt(energyRatio))
-		green := uint8(float64(baseGreen) * math.Sqrt(energyRatio))
-		blue := uint8(float64(baseBlue) * math.Sqrt(energyRatio))
+		sample := depletionMagnitude(previous-source.Energy, source.MaxEnergy, deltaTime)
+		smoothed := r.sourceDepletion[source.Position]
+		r.sourceDepletion[source.Position] = smoothed + (sample-smoothed)*DepletionIndicatorSmoothing
+	}
 
-		// Full alpha for the organism itself
-		alpha := uint8(255)
+	// Forget sources that no longer exist
+	for position := range r.previousSourceEnergy {
+		if !seen[position] {
+			delete(r.previousSourceEnergy, position)
+			delete(r.sourceDepletion, position)
+		}
+	}
+}
 
-		// Draw trail if enabled
-		if r.ShowTrails && len(org.PositionHistory) > 1 {
-			// Draw a line connecting all positions in history
-			trailColor := color.RGBA{red, green, blue, 100} // Semi-transparent
+// depletionMagnitude maps the energy a source lost over deltaTime to an indicator
+// magnitude in [0, 1]. Gains (regeneration) map to zero.
+func depletionMagnitude(energyDelta, maxEnergy, deltaTime float64) float64 {
+	if energyDelta <= 0 || maxEnergy <= 0 || deltaTime <= 0 {
+		return 0
+	}
 
-			// Draw lines between consecutive points
-			for i := 0; i < len(org.PositionHistory)-1; i++ {
-				// Convert world coordinates to screen coordinates for both points
-				x1, y1 := r.worldToScreen(org.PositionHistory[i])
-				x2, y2 := r.worldToScreen(org.PositionHistory[i+1])
+	rate := energyDelta / maxEnergy / deltaTime
+	return math.Min(1.0, rate/DepletionIndicatorFullScale)
+}
 
-				// Fade the trail as it gets older
-				trailAlpha := uint8(40 + (160 * i / len(org.PositionHistory)))
-				fadedColor := color.RGBA{red, green, blue, trailAlpha}
+// Draw organisms
+func (r *Renderer) drawOrganisms(screen *ebiten.Image, snapshot world.Snapshot) {
+	viewWidth := r.mainViewWidth()
+	path := r.organismDrawPath()
 
-				// Draw the line
-				ebitenutil.DrawLine(screen, x1, y1, x2, y2, fadedColor)
-			}
+	for _, org := range snapshot.Organisms {
+		// Convert world coordinates to screen coordinates
+		screenX, screenY := r.worldToScreen(org.Position)
 
-			// Connect the last history point to current position
-			if len(org.PositionHistory) > 0 {
-				lastX, lastY := r.worldToScreen(org.PositionHistory[len(org.PositionHistory)-1])
-				ebitenutil.DrawLine(screen, lastX, lastY, screenX, screenY, trailColor)
-			}
+		// Skip organisms outside the view; drawing them is wasted work
+		if !onScreen(screenX, screenY, viewWidth, r.WindowHeight, CullMargin) {
+			continue
 		}
 
-		// Calculate the visual heading with interpolation for smooth rotation
-		visualHeading := org.PreviousHeading + (org.Heading-org.PreviousHeading)*r.interpolationFactor
+		if r.drawOrganismHook != nil {
+			r.drawOrganismHook(path, org)
+			continue
+		}
+		if path == organismDrawMinimal {
+			r.drawOrganismDot(screen, snapshot, org, screenX, screenY)
+		} else {
+			r.drawOrganismDetailed(screen, snapshot, org, screenX, screenY)
+		}
+	}
+}
 
-		// Define triangle size (can be adjusted based on organism properties)
-		// Scale size slightly with energy level for visual feedback
-		sizeMultiplier := 0.8 + 0.4*energyRatio // Size reduced by up to 20% when low energy
+// organismBaseColor returns the color an organism is drawn in at full energy,
+// following the organism coloring mode
+func (r *Renderer) organismBaseColor(snapshot world.Snapshot, org types.Organism) (uint8, uint8, uint8) {
+	// Show how much the field underfoot tells the organism where to go
+	if r.OrganismColorMode == OrganismColorGradient {
+		gradient := snapshot.GetConcentrationGradientVectorAt(org.Position)
+		steepness := gradientSteepness(gradient, r.Config.Organism.SensorDistance, org.EffectivePreference())
+		c := gradientColor(steepness)
+		return c.R, c.G, c.B
+	}
 
-		// Add pulsing effect for critically low energy
-		if energyRatio < 0.2 && pulseEffect > 1.0 {
-			sizeMultiplier *= pulseEffect * 0.8 // Pulsing size, slightly subdued
-		}
+	// Otherwise map chemical preference to a blue-to-red gradient
+	return preferenceColor(org.ChemPreference, preferenceColorRange(r.Config.Organism.PreferenceDistributionMean))
+}
 
-		size := 4.0 * sizeMultiplier
+// drawOrganismDetailed draws an organism as a heading triangle with its trail,
+// energy bar, feeding glow, sensors and generation label
+func (r *Renderer) drawOrganismDetailed(screen *ebiten.Image, snapshot world.Snapshot, org types.Organism, screenX, screenY float64) {
+	currentTime := r.Simulator.Time // Get current simulation time for animations
+	baseRed, baseGreen, baseBlue := r.organismBaseColor(snapshot, org)
 
-		// Calculate triangle vertices
-		// The triangle should point in the direction of heading
-		// First point: front of the triangle (in heading direction)
-		frontX := screenX + math.Cos(visualHeading)*size*1.5
-		frontY := screenY + math.Sin(visualHeading)*size*1.5
+	// Modify color based on energy level
+	// Low energy organisms appear darker/more transparent
+	energyRatio := org.Energy / org.EnergyCapacity
 
-		// Calculate the back corners (perpendicular to heading)
-		backOffsetX := math.Cos(visualHeading+math.Pi/2) * size
-		backOffsetY := math.Sin(visualHeading+math.Pi/2) * size
+	// Critical energy effect (pulsing when below 20%)
+	var pulseEffect float64 = 1.0
+	if energyRatio < 0.2 {
+		// Create a pulsing effect based on time
+		pulseFrequency := 5.0                                                 // pulses per second
+		pulseAmount := 0.5 + 0.5*math.Sin(currentTime*pulseFrequency*math.Pi) // 0.5-1.5 range
 
-		// Left back corner
-		leftX := screenX - math.Cos(visualHeading)*size/2 - backOffsetX
-		leftY := screenY - math.Sin(visualHeading)*size/2 - backOffsetY
+		// Make pulse more intense as energy decreases
+		pulseIntensity := 1.0 - (energyRatio / 0.2) // 0-1 range as energy drops from 20% to 0%
+		pulseEffect = 1.0 + (pulseAmount-1.0)*pulseIntensity
 
-		// Right back corner
-		rightX := screenX - math.Cos(visualHeading)*size/2 + backOffsetX
-		rightY := screenY - math.Sin(visualHeading)*size/2 + backOffsetY
+		// Apply pulse to color intensity
+		energyRatio = math.Min(1.0, energyRatio*pulseEffect)
+	}
 
-		// Draw the triangle
-		r.drawTriangle(screen, frontX, frontY, leftX, leftY, rightX, rightY,
-			color.RGBA{red, green, blue, alpha})
+	// Dormant organisms are drawn dimmed
+	if org.Dormant {
+		energyRatio *= DormantBrightness
+	}
 
-		// Add a border for better visibility
-		borderAlpha := uint8(150 + 50*energyRatio) // Border fades a bit when low energy
-		ebitenutil.DrawLine(screen, frontX, frontY, leftX, leftY, color.RGBA{255, 255, 255, borderAlpha})
-		ebitenutil.DrawLine(screen, leftX, leftY, rightX, rightY, color.RGBA{255, 255, 255, borderAlpha})
-		ebitenutil.DrawLine(screen, rightX, rightY, frontX, frontY, color.RGBA{255, 255, 255, borderAlpha})
+	red := uint8(float64(baseRed) * math.Sqrt(energyRatio))
+	green := uint8(float64(baseGreen) * math.Sqrt(energyRatio))
+	blue := uint8(float64(baseBlue) * math.Sqrt(energyRatio))
 
-		// Draw energy bar
-		// Always draw the energy bar, enhanced version
-		barWidth := 12.0
-		barHeight := 2.5
-		barX := screenX - barWidth/2
-		barY := screenY - size*2.5 // Position higher above organism
+	// Full alpha for the organism itself
+	alpha := uint8(255)
 
-		// Background (empty) bar with border
-		bgAlpha := uint8(80 + 120*energyRatio) // More visible when energy is higher
-		ebitenutil.DrawRect(screen, barX-0.5, barY-0.5, barWidth+1, barHeight+1, color.RGBA{30, 30, 30, bgAlpha})
-		ebitenutil.DrawRect(screen, barX, barY, barWidth, barHeight, color.RGBA{50, 50, 50, bgAlpha})
+	// Draw trail if enabled
+	if r.ShowTrails && len(org.PositionHistory) > 1 {
+		// Draw a line connecting all positions in history
+		trailColor := color.RGBA{red, green, blue, 100} // Semi-transparent
 
-		// Filled portion based on energy
-		fillWidth := barWidth * energyRatio
+		// In a heat mode, color each segment by the organism's state along the way
+		var heat []float64
+		if r.TrailColorMode != TrailColorOrganism {
+			heat = trailSegmentValues(org.PositionHistory, r.TrailColorMode)
+		}
 
-		// Color changes from red (low) to yellow (medium) to green (high)
-		barRed := uint8(255)
-		barGreen := uint8(0)
+		// Draw lines between consecutive points
+		for i := 0; i < len(org.PositionHistory)-1; i++ {
+			// Convert world coordinates to screen coordinates for both points
+			x1, y1 := r.worldToScreen(org.PositionHistory[i].Position)
+			x2, y2 := r.worldToScreen(org.PositionHistory[i+1].Position)
+
+			// Fade the trail as it gets older
+			trailAlpha := uint8(40 + (160 * i / len(org.PositionHistory)))
+			fadedColor := color.RGBA{red, green, blue, trailAlpha}
+			if heat != nil {
+				heatColor := GetColorFromScheme(r.CurrentColorScheme, heat[i])
+				fadedColor = color.RGBA{heatColor.R, heatColor.G, heatColor.B, trailAlpha}
+			}
 
-		if energyRatio > 0.5 {
-			// Green increases as energy goes from 50% to 100%
-			barGreen = uint8(255 * (energyRatio - 0.5) * 2)
-		} else {
-			// Red stays at max, green increases as energy goes from 0% to 50%
-			barGreen = uint8(255 * energyRatio * 2)
+			// Draw the line
+			ebitenutil.DrawLine(screen, x1, y1, x2, y2, fadedColor)
 		}
 
-		// Make bar pulse for critical energy
-		if energyRatio < 0.2 && pulseEffect > 1.0 {
-			// Make bar flash more intensely when critically low
-			barRed = uint8(math.Min(255, float64(barRed)*pulseEffect))
+		// Connect the last history point to current position
+		if len(org.PositionHistory) > 0 {
+			lastX, lastY := r.worldToScreen(org.PositionHistory[len(org.PositionHistory)-1].Position)
+			ebitenutil.DrawLine(screen, lastX, lastY, screenX, screenY, trailColor)
 		}
+	}
 
-		// Draw the energy bar with anti-aliasing by drawing multiple rects with varying alpha
-		aaOffset := 0.5
-		ebitenutil.DrawRect(screen, barX-aaOffset, barY-aaOffset, fillWidth+aaOffset*2, barHeight+aaOffset*2,
-			color.RGBA{barRed / 2, barGreen / 2, 0, 128})
-		ebitenutil.DrawRect(screen, barX, barY, fillWidth, barHeight,
-			color.RGBA{barRed, barGreen, 0, 230})
+	// Calculate the visual heading with interpolation for smooth rotation
+	visualHeading := org.PreviousHeading + (org.Heading-org.PreviousHeading)*r.interpolationFactor
 
-		// Add glow effect for organisms gaining energy
-		// Detect if organism is in optimal environment and gaining energy
-		concentration := r.World.GetConcentrationAt(org.Position)
-		similarityFactor := 1.0 - math.Min(math.Abs(concentration-org.ChemPreference)/org.ChemPreference, 1.0)
+	// Define triangle size (can be adjusted based on organism properties)
+	// Scale size slightly with energy level for visual feedback
+	sizeMultiplier := 0.8 + 0.4*energyRatio // Size reduced by up to 20% when low energy
 
-		// If in optimal environment (similarity > 70%), show energy gain glow
-		if similarityFactor > 0.7 && energyRatio < 0.99 {
-			// Glow intensity based on how optimal the environment is
-			glowIntensity := (similarityFactor - 0.7) / 0.3 // 0-1 range
+	// Add pulsing effect for critically low energy
+	if energyRatio < 0.2 && pulseEffect > 1.0 {
+		sizeMultiplier *= pulseEffect * 0.8 // Pulsing size, slightly subdued
+	}
 
-			// Create a pulsing glow effect
-			glowFrequency := 2.0
-			glowPulse := 0.6 + 0.4*math.Sin(currentTime*glowFrequency*math.Pi*2) // 0.6-1.0 range
+	size := 4.0 * sizeMultiplier * org.BodySize()
+
+	// Calculate triangle vertices
+	// The triangle should point in the direction of heading
+	// First point: front of the triangle (in heading direction)
+	frontX := screenX + math.Cos(visualHeading)*size*1.5
+	frontY := screenY + math.Sin(visualHeading)*size*1.5
+
+	// Calculate the back corners (perpendicular to heading)
+	backOffsetX := math.Cos(visualHeading+math.Pi/2) * size
+	backOffsetY := math.Sin(visualHeading+math.Pi/2) * size
+
+	// Left back corner
+	leftX := screenX - math.Cos(visualHeading)*size/2 - backOffsetX
+	leftY := screenY - math.Sin(visualHeading)*size/2 - backOffsetY
+
+	// Right back corner
+	rightX := screenX - math.Cos(visualHeading)*size/2 + backOffsetX
+	rightY := screenY - math.Sin(visualHeading)*size/2 + backOffsetY
+
+	// Draw the triangle
+	r.drawTriangle(screen, frontX, frontY, leftX, leftY, rightX, rightY,
+		color.RGBA{red, green, blue, alpha})
+
+	// Add a border for better visibility
+	borderAlpha := uint8(150 + 50*energyRatio) // Border fades a bit when low energy
+	ebitenutil.DrawLine(screen, frontX, frontY, leftX, leftY, color.RGBA{255, 255, 255, borderAlpha})
+	ebitenutil.DrawLine(screen, leftX, leftY, rightX, rightY, color.RGBA{255, 255, 255, borderAlpha})
+	ebitenutil.DrawLine(screen, rightX, rightY, frontX, frontY, color.RGBA{255, 255, 255, borderAlpha})
+
+	// Draw energy bar
+	// Always draw the energy bar, enhanced version
+	barWidth := 12.0
+	barHeight := 2.5
+	barX := screenX - barWidth/2
+	barY := screenY - size*2.5 // Position higher above organism
+
+	// Background (empty) bar with border
+	bgAlpha := uint8(80 + 120*energyRatio) // More visible when energy is higher
+	ebitenutil.DrawRect(screen, barX-0.5, barY-0.5, barWidth+1, barHeight+1, color.RGBA{30, 30, 30, bgAlpha})
+	ebitenutil.DrawRect(screen, barX, barY, barWidth, barHeight, color.RGBA{50, 50, 50, bgAlpha})
+
+	// Filled portion based on energy
+	fillWidth := barWidth * energyRatio
+
+	// Color changes from red (low) to yellow (medium) to green (high)
+	barRed := uint8(255)
+	barGreen := uint8(0)
+
+	if energyRatio > 0.5 {
+		// Green increases as energy goes from 50% to 100%
+		barGreen = uint8(255 * (energyRatio - 0.5) * 2)
+	} else {
+		// Red stays at max, green increases as energy goes from 0% to 50%
+		barGreen = uint8(255 * energyRatio * 2)
+	}
 
-			// Glow color matches energy bar but more transparent
-			glowRed := barRed / 2
-			glowGreen := barGreen / 2
-			glowAlpha := uint8(100 * glowIntensity * glowPulse)
+	// Make bar pulse for critical energy
+	if energyRatio < 0.2 && pulseEffect > 1.0 {
+		// Make bar flash more intensely when critically low
+		barRed = uint8(math.Min(255, float64(barRed)*pulseEffect))
+	}
 
-			// Create a glow around the energy bar
-			ebitenutil.DrawRect(screen, barX-2, barY-2, fillWidth+4, barHeight+4,
-				color.RGBA{glowRed, glowGreen, 0, glowAlpha})
-		}
+	// Draw the energy bar with anti-aliasing by drawing multiple rects with varying alpha
+	aaOffset := 0.5
+	ebitenutil.DrawRect(screen, barX-aaOffset, barY-aaOffset, fillWidth+aaOffset*2, barHeight+aaOffset*2,
+		color.RGBA{barRed / 2, barGreen / 2, 0, 128})
+	ebitenutil.DrawRect(screen, barX, barY, fillWidth, barHeight,
+		color.RGBA{barRed, barGreen, 0, 230})
+
+	// Add glow effect for organisms gaining energy
+	// Detect if organism is in optimal environment and gaining energy
+	concentration := snapshot.GetConcentrationAt(org.Position)
+	similarityFactor := 1.0 - math.Min(math.Abs(concentration-org.ChemPreference)/org.ChemPreference, 1.0)
+
+	// If in optimal environment (similarity > 70%), show energy gain glow
+	if similarityFactor > 0.7 && energyRatio < 0.99 {
+		// Glow intensity based on how optimal the environment is
+		glowIntensity := (similarityFactor - 0.7) / 0.3 // 0-1 range
+
+		// Create a pulsing glow effect
+		glowFrequency := 2.0
+		glowPulse := 0.6 + 0.4*math.Sin(currentTime*glowFrequency*math.Pi*2) // 0.6-1.0 range
+
+		// Glow color matches energy bar but more transparent
+		glowRed := barRed / 2
+		glowGreen := barGreen / 2
+		glowAlpha := uint8(100 * glowIntensity * glowPulse)
+
+		// Create a glow around the energy bar
+		ebitenutil.DrawRect(screen, barX-2, barY-2, fillWidth+4, barHeight+4,
+			color.RGBA{glowRed, glowGreen, 0, glowAlpha})
+	}
 
-		// Draw sensors if enabled
-		if r.ShowSensors {
-			sensorPositions := org.GetSensorPositions(r.Config.Organism.SensorDistance)
+	// Draw sensors if enabled
+	if r.ShowSensors {
+		sensorPositions := org.GetSensorPositions(r.Config.Organism.SensorDistance)
 
-			// Draw lines to sensors
-			for _, sensorPos := range sensorPositions {
-				sensorX, sensorY := r.worldToScreen(sensorPos)
-				ebitenutil.DrawLine(screen, screenX, screenY, sensorX, sensorY, color.RGBA{200, 200, 200, 128})
-			}
+		// Draw lines to sensors
+		for _, sensorPos := range sensorPositions {
+			sensorX, sensorY := r.worldToScreen(sensorPos)
+			ebitenutil.DrawLine(screen, screenX, screenY, sensorX, sensorY, color.RGBA{200, 200, 200, 128})
 		}
+	}
 
-		// Draw generation number above energy bar if multi-generation simulation is running
-		if org.Generation > 1 {
-			// Only draw for non-first generation organisms
-			genText := fmt.Sprintf("Gen %d", org.Generation)
+	// Draw generation number above energy bar if multi-generation simulation is running
+	if org.Generation > 1 {
+		// Only draw for non-first generation organisms
+		genText := fmt.Sprintf("Gen %d", org.Generation)
 
-			// Calculate text position above energy bar
-			textX := int(barX)
-			textY := int(barY - 10)
+		// Calculate text position above energy bar
+		textX := int(barX)
+		textY := int(barY - 10)
 
-			ebitenutil.DebugPrintAt(screen, genText, textX, textY)
-		}
+		ebitenutil.DebugPrintAt(screen, genText, textX, textY)
 	}
 }
 
 // Draw statistics on screen
 func (r *Renderer) drawStats(screen *ebiten.Image) {
+	realElapsed := r.Simulator.RealTimeElapsed()
 	stats := []string{
 		fmt.Sprintf("FPS: %.1f", r.FPS),
 		fmt.Sprintf("Time: %.2f", r.Simulator.Time),
-		fmt.Sprintf("Organisms: %d", r.Stats.Organisms.Count),
+		fmt.Sprintf("Real: %s (%.1f sim s/s)", realElapsed.Round(time.Second),
+			simulation.SimTimeRatio(r.Simulator.Time, realElapsed)),
+		fmt.Sprintf("Organisms: %d (%d wall-stuck)", r.Stats.Organisms.Count, r.Stats.Organisms.WallStuckCount),
 		fmt.Sprintf("Speed: %.1fx", r.Simulator.SimulationSpeed),
 		fmt.Sprintf("Paused: %v", r.Simulator.IsPaused),
-		fmt.Sprintf("Avg Preference: %.1f", r.Stats.Organisms.AveragePreference),
-		fmt.Sprintf("Avg Energy: %.1f (%.0f%%)",
-			r.Stats.Organisms.AverageEnergy,
+		fmt.Sprintf("Avg Preference: %s", r.formatConcentration(r.Stats.Organisms.AveragePreference)),
+		fmt.Sprintf("Avg Energy: %s (%.0f%%)",
+			r.formatEnergy(r.Stats.Organisms.AverageEnergy),
 			r.Stats.Organisms.EnergyRatio*100),
 		fmt.Sprintf("Grid: %v", r.ShowGrid),
-		fmt.Sprintf("Trails: %v", r.ShowTrails),
+		fmt.Sprintf("Trails: %v (%s)", r.ShowTrails, r.TrailColorMode),
+		fmt.Sprintf("Organism Color: %s", r.OrganismColorMode),
+		fmt.Sprintf("Minimal Organisms: %v", r.MinimalOrganisms),
+		fmt.Sprintf("Contours: %v", r.ShowContours),
 	}
 
 	// Draw stats in the top-left corner
@@ -508,8 +874,17 @@ func (r *Renderer) drawStats(screen *ebiten.Image) {
 		"S: Toggle Sensors",
 		"L: Toggle Legend",
 		"T: Toggle Trails",
+		"H: Cycle Trail Heat",
+		"O: Cycle Organism Color",
+		"D: Toggle Minimal Organisms",
+		"Arrows/Middle-drag: Pan, Wheel: Zoom, Home: Home View",
 		"M: Cycle Color Schemes",
+		"N: Toggle Lineages",
+		"C: Toggle Contours",
+		"E: Export Scenario",
+		"Click: Select, Shift+Click: Probe, X: Clear",
 		"+/-: Adjust Speed",
+		"Hold B: Bullet-time (selected organism)",
 	}
 
 	// Draw controls in the bottom-left corner
@@ -523,6 +898,115 @@ func (r *Renderer) drawStats(screen *ebiten.Image) {
 	}
 }
 
+// drawLineagePanel lists the largest lineages and their share of the population
+// in the bottom-right corner
+func (r *Renderer) drawLineagePanel(screen *ebiten.Image) {
+	margin := 20
+	panelWidth := 220
+	lineHeight := 18
+	panelHeight := lineHeight * (LineagePanelSize + 1)
+	x := r.mainViewWidth() - panelWidth - margin
+	y := r.WindowHeight - panelHeight - margin
+
+	// Background for the panel
+	for ly := y - 5; ly < y+panelHeight; ly++ {
+		for lx := x - 5; lx < x+panelWidth; lx++ {
+			if lx >= 0 && lx < r.WindowWidth && ly >= 0 && ly < r.WindowHeight {
+				screen.Set(lx, ly, color.RGBA{0, 0, 0, 150})
+			}
+		}
+	}
+
+	ebitenutil.DebugPrintAt(screen, "TOP LINEAGES", x, y)
+	for i, lineage := range r.topLineages {
+		line := fmt.Sprintf("%d. #%06d  %4d  (%.0f%%)", i+1, lineage.RootID%1000000, lineage.Count, lineage.Share*100)
+		ebitenutil.DebugPrintAt(screen, line, x, y+(i+1)*lineHeight)
+	}
+}
+
+// traceWorldContours traces contours of the world's concentration field at
+// fixed fractions of its peak sampled value
+func (r *Renderer) traceWorldContours() []ContourLine {
+	field := sampleContourField(r.World.GetConcentrationAt, r.World.GetBounds(), ContourResolution)
+	return field.trace(contourLevels(field.maxValue))
+}
+
+// drawContours draws the concentration contours with their level labeled along each line
+func (r *Renderer) drawContours(screen *ebiten.Image) {
+	lineColor := color.RGBA{200, 200, 220, 120}
+	for _, line := range r.contours {
+		for _, segment := range line.Segments {
+			x1, y1 := r.worldToScreen(segment.Start)
+			x2, y2 := r.worldToScreen(segment.End)
+			ebitenutil.DrawLine(screen, x1, y1, x2, y2, lineColor)
+		}
+	}
+
+	// Center each label on its contour point
+	for _, label := range placeContourLabels(r.contours, r.worldToScreen, r.formatLevel, ContourLabelSpacing) {
+		ebitenutil.DebugPrintAt(screen, label.Text, int(label.X)-3*len(label.Text), int(label.Y)-8)
+	}
+}
+
+// drawHeatDeathBanner shows a banner across the top of the screen once the
+// world's energy has collapsed with no way to recover
+func (r *Renderer) drawHeatDeathBanner(screen *ebiten.Image) {
+	message := "HEAT DEATH: system energy collapsed and sources can't regenerate (R to reset)"
+	bannerHeight := 30
+	y := 10
+
+	// Background for the banner
+	for ly := y; ly < y+bannerHeight; ly++ {
+		for lx := 0; lx < r.mainViewWidth(); lx++ {
+			screen.Set(lx, ly, color.RGBA{140, 20, 20, 200})
+		}
+	}
+
+	// Center the message; the debug font is 6 pixels wide
+	x := (r.mainViewWidth() - len(message)*6) / 2
+	ebitenutil.DebugPrintAt(screen, message, x, y+bannerHeight/2-8)
+}
+
+// exportScenario saves the current organisms and chemical sources to a timestamped scenario file
+func (r *Renderer) exportScenario() {
+	path := fmt.Sprintf("scenario_%s.json", time.Now().Format("20060102-150405"))
+	if err := r.World.ExportScenario(path); err != nil {
+		fmt.Printf("Failed to export scenario: %v\n", err)
+		return
+	}
+	fmt.Printf("Exported scenario to %s\n", path)
+}
+
+// trailSegmentValues returns a 0-1 value for each segment of a trail: the energy
+// recorded at the segment's end, or the segment's length relative to the longest
+// segment. Points are recorded at a fixed interval, so length tracks speed.
+func trailSegmentValues(history []types.TrailPoint, mode TrailColorMode) []float64 {
+	if len(history) < 2 {
+		return nil
+	}
+
+	values := make([]float64, len(history)-1)
+	switch mode {
+	case TrailColorEnergy:
+		for i := range values {
+			values[i] = math.Max(0, math.Min(1, history[i+1].Energy))
+		}
+	case TrailColorSpeed:
+		longest := 0.0
+		for i := range values {
+			values[i] = history[i].Position.DistanceTo(history[i+1].Position)
+			longest = math.Max(longest, values[i])
+		}
+		if longest > 0 {
+			for i := range values {
+				values[i] /= longest
+			}
+		}
+	}
+
+	return values
+}
+
 // Draw a grid for visual reference
 func (r *Renderer) drawGrid(screen *ebiten.Image) {
 	bounds := r.World.GetBounds()
@@ -613,19 +1097,6 @@ func (r *Renderer) updateReproductionEvents(deltaTime float64) {
 		}
 	}
 	r.reproductionEvents = updatedEvents
-
-	// Check for new reproduction events by comparing organism count
-	currentCount, _ := r.World.GetPopulationInfo()
-	if currentCount > r.previousOrgCount {
-		// Get the newest organisms for visual effects
-		organisms := r.World.GetOrganisms()
-		if len(organisms) > 0 {
-			// Just add an effect at the newest organism position (the last in the list)
-			// In a more sophisticated implementation, we'd track exact reproduction events
-			r.AddReproductionEvent(organisms[len(organisms)-1].Position)
-		}
-	}
-	r.previousOrgCount = currentCount
 }
 
 // Draw reproduction events as expanding circles
@@ -647,23 +1118,60 @@ func (r *Renderer) drawReproductionEvents(screen *ebiten.Image) {
 			innerAlpha := alpha / uint8(i+1)
 
 			// Yellow-orange glow for reproduction
-			glowColor := color.RGBA{255, 200, 50, innerAlpha}
+			drawRing(screen, screenX, screenY, innerRadius, color.RGBA{255, 200, 50, innerAlpha})
+		}
+	}
+}
 
-			// Draw the circle approximately using line segments
-			const segments = 12
-			for j := 0; j < segments; j++ {
-				angle1 := float64(j) * 2 * math.Pi / segments
-				angle2 := float64(j+1) * 2 * math.Pi / segments
+// AddDeathEvent adds a fading ring where the organism died
+func (r *Renderer) AddDeathEvent(org types.Organism) {
+	r.deathEvents = append(r.deathEvents, DeathEvent{
+		Position: org.Position,
+		TimeLeft: 1.0, // 1 second duration
+	})
+}
 
-				x1 := screenX + math.Cos(angle1)*innerRadius
-				y1 := screenY + math.Sin(angle1)*innerRadius
-				x2 := screenX + math.Cos(angle2)*innerRadius
-				y2 := screenY + math.Sin(angle2)*innerRadius
+// Update death events (fade out over time)
+func (r *Renderer) updateDeathEvents(deltaTime float64) {
+	// If we have too many events, trim the list to prevent memory issues
+	if len(r.deathEvents) > 100 {
+		r.deathEvents = r.deathEvents[len(r.deathEvents)-100:]
+	}
 
-				ebitenutil.DrawLine(screen, x1, y1, x2, y2, glowColor)
-			}
+	updatedEvents := make([]DeathEvent, 0, len(r.deathEvents))
+	for _, event := range r.deathEvents {
+		event.TimeLeft -= deltaTime
+		if event.TimeLeft > 0 {
+			updatedEvents = append(updatedEvents, event)
 		}
 	}
+	r.deathEvents = updatedEvents
+}
+
+// Draw death events as grey rings that widen slightly as they fade
+func (r *Renderer) drawDeathEvents(screen *ebiten.Image) {
+	for _, event := range r.deathEvents {
+		screenX, screenY := r.worldToScreen(event.Position)
+		radius := 4.0 + 4.0*(1.0-event.TimeLeft)
+		alpha := uint8(200 * event.TimeLeft)
+		drawRing(screen, screenX, screenY, radius, color.RGBA{160, 160, 160, alpha})
+	}
+}
+
+// drawRing draws a circle outline approximately using line segments
+func drawRing(screen *ebiten.Image, x, y, radius float64, clr color.Color) {
+	const segments = 12
+	for j := 0; j < segments; j++ {
+		angle1 := float64(j) * 2 * math.Pi / segments
+		angle2 := float64(j+1) * 2 * math.Pi / segments
+
+		x1 := x + math.Cos(angle1)*radius
+		y1 := y + math.Sin(angle1)*radius
+		x2 := x + math.Cos(angle2)*radius
+		y2 := y + math.Sin(angle2)*radius
+
+		ebitenutil.DrawLine(screen, x1, y1, x2, y2, clr)
+	}
 }
 
 // drawLegend shows a legend explaining the colors and symbols used in the simulation
@@ -672,7 +1180,7 @@ func (r *Renderer) drawLegend(screen *ebiten.Image) {
 	margin := 20
 	legendWidth := 220
 	lineHeight := 18
-	x := r.WindowWidth - legendWidth - margin
+	x := r.mainViewWidth() - legendWidth - margin
 	y := margin
 
 	// Background for the legend
@@ -783,6 +1291,10 @@ func (r *Renderer) drawLegend(screen *ebiten.Image) {
 	ebitenutil.DebugPrintAt(screen, "Size indicates energy", x+45, y)
 	y += lineHeight
 	ebitenutil.DebugPrintAt(screen, "Color indicates decay rate", x+45, y)
+	y += lineHeight
+	ebitenutil.DebugPrintAt(screen, "Red arrow: draining fast", x+45, y)
+	y += lineHeight
+	ebitenutil.DebugPrintAt(screen, "(grazing, decay, births)", x+45, y)
 
 	y += lineHeight + 5
 
@@ -823,5 +1335,7 @@ func (r *Renderer) drawLegend(screen *ebiten.Image) {
 	y += lineHeight
 	ebitenutil.DebugPrintAt(screen, "T: Toggle Trails", x, y)
 	y += lineHeight
+	ebitenutil.DebugPrintAt(screen, "E: Export Scenario", x, y)
+	y += lineHeight
 	ebitenutil.DebugPrintAt(screen, "R: Reset Simulation", x, y)
 }
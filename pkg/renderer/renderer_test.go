@@ -0,0 +1,131 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/simulation"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+func TestDepletionMagnitude(t *testing.T) {
+	testCases := []struct {
+		name        string
+		energyDelta float64
+		maxEnergy   float64
+		deltaTime   float64
+		expected    float64
+	}{
+		{"No change", 0, 1000, 1.0, 0},
+		{"Regeneration", -500, 1000, 1.0, 0},
+		{"Half scale", 1000 * DepletionIndicatorFullScale / 2, 1000, 1.0, 0.5},
+		{"Full scale", 1000 * DepletionIndicatorFullScale, 1000, 1.0, 1.0},
+		{"Clamped", 1000, 1000, 1.0, 1.0},
+		{"Scales with time", 1000 * DepletionIndicatorFullScale / 2, 1000, 0.5, 1.0},
+		{"Zero capacity", 10, 0, 1.0, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := depletionMagnitude(tc.energyDelta, tc.maxEnergy, tc.deltaTime)
+			if math.Abs(got-tc.expected) > 1e-9 {
+				t.Errorf("depletionMagnitude(%v, %v, %v) = %v; want %v",
+					tc.energyDelta, tc.maxEnergy, tc.deltaTime, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTrailSegmentValues(t *testing.T) {
+	history := []types.TrailPoint{
+		{Position: types.Point{X: 0, Y: 0}, Energy: 0.9},
+		{Position: types.Point{X: 4, Y: 0}, Energy: 0.5},
+		{Position: types.Point{X: 6, Y: 0}, Energy: 1.2}, // Out of range values are clamped
+	}
+
+	testCases := []struct {
+		name     string
+		mode     TrailColorMode
+		expected []float64
+	}{
+		{"Energy", TrailColorEnergy, []float64{0.5, 1.0}},
+		{"Speed", TrailColorSpeed, []float64{1.0, 0.5}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := trailSegmentValues(history, tc.mode)
+			if len(got) != len(tc.expected) {
+				t.Fatalf("trailSegmentValues() = %v; want %v", got, tc.expected)
+			}
+			for i := range got {
+				if math.Abs(got[i]-tc.expected[i]) > 1e-9 {
+					t.Errorf("trailSegmentValues()[%d] = %v; want %v", i, got[i], tc.expected[i])
+				}
+			}
+		})
+	}
+
+	if got := trailSegmentValues(history[:1], TrailColorEnergy); got != nil {
+		t.Errorf("trailSegmentValues() of a single point = %v; want nil", got)
+	}
+}
+
+func TestLayoutFollowsWindowSize(t *testing.T) {
+	r := &Renderer{
+		World:        world.NewWorld(config.SimulationConfig{World: config.WorldConfig{Width: 1000, Height: 1000}}),
+		WindowWidth:  800,
+		WindowHeight: 800,
+	}
+
+	// Resize the window to something other than the configured size
+	width, height := r.Layout(600, 400)
+	if width != 600 || height != 400 {
+		t.Fatalf("Layout = %dx%d; want the window's 600x400", width, height)
+	}
+
+	// The square world fits the new height and is centered horizontally
+	x, y := r.worldToScreen(types.Point{X: 500, Y: 500})
+	if x != 300 || y != 200 {
+		t.Errorf("World center maps to (%v, %v); want (300, 200)", x, y)
+	}
+	x, y = r.worldToScreen(types.Point{X: 1000, Y: 1000})
+	if x != 500 || y != 400 {
+		t.Errorf("World corner maps to (%v, %v); want (500, 400)", x, y)
+	}
+}
+
+func TestResetClearsWorldReferences(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Organism.Count = 5
+	cfg.RandomSeed = 3
+	w := world.NewWorld(cfg)
+	r := &Renderer{
+		World:     w,
+		Simulator: simulation.NewSimulator(w, cfg),
+		ShowGrid:  true,
+		camera:    camera{center: types.Point{X: 100, Y: 200}, zoom: 2},
+	}
+
+	selected := w.GetOrganisms()[0]
+	r.selectedOrganism = &selected
+	r.probes = []types.Point{{X: 10, Y: 10}}
+	r.AddReproductionEvent(types.Point{X: 20, Y: 20})
+	r.AddDeathEvent(selected)
+	r.topLineages = world.TopLineages(w.GetOrganisms(), LineagePanelSize)
+
+	r.resetSimulation()
+
+	if r.selectedOrganism != nil {
+		t.Errorf("Selected organism %d survived the reset", r.selectedOrganism.ID)
+	}
+	if len(r.probes) != 0 || len(r.reproductionEvents) != 0 || len(r.deathEvents) != 0 || len(r.topLineages) != 0 {
+		t.Errorf("Reset left %d probes, %d birth and %d death effects and %d lineages; want none",
+			len(r.probes), len(r.reproductionEvents), len(r.deathEvents), len(r.topLineages))
+	}
+	if !r.ShowGrid || r.camera.zoom != 2 || r.camera.center != (types.Point{X: 100, Y: 200}) {
+		t.Errorf("Reset changed the view: grid %v, camera %+v", r.ShowGrid, r.camera)
+	}
+}
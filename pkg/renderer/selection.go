@@ -0,0 +1,114 @@
+package renderer
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// SelectionRadius is how close, in screen pixels, a click must land to an
+// organism to select it
+const SelectionRadius = 8.0
+
+// pickOrganism returns the index of the organism drawn nearest to the screen
+// point (x, y) within maxDistance pixels, or -1 if there is none
+func pickOrganism(organisms []types.Organism, x, y, maxDistance float64, toScreen func(types.Point) (float64, float64)) int {
+	nearest := -1
+	nearestDistance := maxDistance
+	for i, org := range organisms {
+		screenX, screenY := toScreen(org.Position)
+		if distance := math.Hypot(screenX-x, screenY-y); distance <= nearestDistance {
+			nearest = i
+			nearestDistance = distance
+		}
+	}
+	return nearest
+}
+
+// findOrganism returns the organism with the given ID, if it's still present
+func findOrganism(organisms []types.Organism, id int64) (types.Organism, bool) {
+	for _, org := range organisms {
+		if org.ID == id {
+			return org, true
+		}
+	}
+	return types.Organism{}, false
+}
+
+// updateMouse handles left clicks: Shift+click places a preference probe, and a
+// plain click selects the organism under the cursor or, on empty space, deselects
+func (r *Renderer) updateMouse() {
+	pressed := ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	justClicked := pressed && !r.clickHeld
+	r.clickHeld = pressed
+	if !justClicked {
+		return
+	}
+
+	x, y := ebiten.CursorPosition()
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		r.probes = append(r.probes, r.screenToWorld(float64(x), float64(y)))
+		if len(r.probes) > MaxProbes {
+			r.probes = r.probes[1:]
+		}
+		return
+	}
+
+	organisms := r.World.GetOrganisms()
+	if i := pickOrganism(organisms, float64(x), float64(y), SelectionRadius, r.worldToScreen); i >= 0 {
+		r.selectedOrganism = &organisms[i]
+	} else {
+		r.selectedOrganism = nil
+	}
+}
+
+// refreshSelection updates the selected organism to its current state, clearing
+// the selection once it has died
+func (r *Renderer) refreshSelection() {
+	if r.selectedOrganism == nil {
+		return
+	}
+
+	if org, ok := findOrganism(r.World.GetOrganisms(), r.selectedOrganism.ID); ok {
+		r.selectedOrganism = &org
+	} else {
+		r.selectedOrganism = nil
+	}
+}
+
+// drawSelection rings the selected organism and shows its details in a panel
+// below the legend
+func (r *Renderer) drawSelection(screen *ebiten.Image) {
+	org := r.selectedOrganism
+	if org == nil {
+		return
+	}
+
+	screenX, screenY := r.worldToScreen(org.Position)
+	drawRing(screen, screenX, screenY, SelectionRadius+2, color.RGBA{255, 255, 255, 220})
+
+	lines := []string{
+		fmt.Sprintf("ORGANISM #%d", org.ID),
+		fmt.Sprintf("Generation: %d", org.Generation),
+		fmt.Sprintf("Energy: %s / %s", r.formatEnergy(org.Energy), r.formatEnergy(org.EnergyCapacity)),
+		fmt.Sprintf("Preference: %s", r.formatConcentration(org.ChemPreference)),
+		fmt.Sprintf("Speed: %.2f", org.Speed),
+		fmt.Sprintf("Metabolic Rate: %.3f", org.MetabolicRate),
+	}
+
+	margin := 20
+	panelWidth := 220
+	lineHeight := 18
+	x := r.mainViewWidth() - panelWidth - margin
+	y := margin + 220 // Below the legend
+
+	ebitenutil.DrawRect(screen, float64(x-5), float64(y-5), float64(panelWidth), float64(lineHeight*len(lines)+5),
+		color.RGBA{0, 0, 0, 150})
+	for i, line := range lines {
+		ebitenutil.DebugPrintAt(screen, line, x, y+i*lineHeight)
+	}
+}
@@ -0,0 +1,46 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+func TestPickOrganism(t *testing.T) {
+	organisms := []types.Organism{
+		{ID: 1, Position: types.Point{X: 10, Y: 10}},
+		{ID: 2, Position: types.Point{X: 20, Y: 10}},
+		{ID: 3, Position: types.Point{X: 50, Y: 50}},
+	}
+	// Screen coordinates are twice the world coordinates
+	toScreen := func(p types.Point) (float64, float64) { return p.X * 2, p.Y * 2 }
+
+	tests := []struct {
+		name string
+		x, y float64
+		want int
+	}{
+		{"Click on an organism", 100, 100, 2},
+		{"Nearest of two within reach", 34, 20, 1},
+		{"Just within reach", 20, 28, 0},
+		{"Empty space", 70, 70, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pickOrganism(organisms, tt.x, tt.y, SelectionRadius, toScreen); got != tt.want {
+				t.Errorf("pickOrganism at (%v, %v) = %d; want %d", tt.x, tt.y, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindOrganism(t *testing.T) {
+	organisms := []types.Organism{{ID: 4, Energy: 7}, {ID: 9}}
+
+	if org, ok := findOrganism(organisms, 4); !ok || org.Energy != 7 {
+		t.Errorf("findOrganism(4) = %+v, %v; want the organism with energy 7", org, ok)
+	}
+	if _, ok := findOrganism(organisms, 5); ok {
+		t.Error("Expected a missing organism, such as one that died, not to be found")
+	}
+}
@@ -0,0 +1,130 @@
+package renderer
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// Statistics sidebar constants
+const (
+	SidebarWidth          = 220  // Width of the sidebar in pixels
+	SidebarMinWindowWidth = 1000 // Narrowest window that gets a sidebar
+	SidebarChartHeight    = 150  // Height of each histogram chart in pixels
+)
+
+// histogramBar is one bucket of a histogram, sized for drawing
+type histogramBar struct {
+	Label  string  // Bucket label from the histogram
+	Count  int     // Number of entries in the bucket
+	Height float64 // Bar height in pixels, relative to the fullest bucket
+}
+
+// histogramBars orders a histogram's buckets numerically and scales their counts
+// so the fullest bucket is maxHeight tall
+func histogramBars(histogram map[string]int, maxHeight float64) []histogramBar {
+	labels := make([]string, 0, len(histogram))
+	maxCount := 0
+	for label, count := range histogram {
+		labels = append(labels, label)
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		a, errA := strconv.ParseFloat(labels[i], 64)
+		b, errB := strconv.ParseFloat(labels[j], 64)
+		if errA != nil || errB != nil {
+			return labels[i] < labels[j]
+		}
+		return a < b
+	})
+
+	bars := make([]histogramBar, len(labels))
+	for i, label := range labels {
+		bars[i] = histogramBar{Label: label, Count: histogram[label]}
+		if maxCount > 0 {
+			bars[i].Height = float64(histogram[label]) / float64(maxCount) * maxHeight
+		}
+	}
+	return bars
+}
+
+// showSidebar reports whether the window is wide enough for the statistics sidebar
+func (r *Renderer) showSidebar() bool {
+	return r.WindowWidth >= SidebarMinWindowWidth
+}
+
+// mainViewWidth returns the width of the world view, which excludes the sidebar
+func (r *Renderer) mainViewWidth() int {
+	if r.showSidebar() {
+		return r.WindowWidth - SidebarWidth
+	}
+	return r.WindowWidth
+}
+
+// drawSidebar draws the statistics sidebar to the right of the world view
+func (r *Renderer) drawSidebar(screen *ebiten.Image, sources []types.ChemicalSource) {
+	const margin = 10
+	x := float64(r.mainViewWidth())
+	ebitenutil.DrawRect(screen, x, 0, SidebarWidth, float64(r.WindowHeight), color.RGBA{10, 10, 14, 255})
+
+	y := margin
+	y = r.drawHistogram(screen, "PREFERENCE", r.Stats.Organisms.PreferenceHistogram, int(x)+margin, y, color.RGBA{80, 160, 255, 255})
+	y = r.drawHistogram(screen, "ENERGY (% of capacity)", r.Stats.Organisms.EnergyHistogram, int(x)+margin, y+margin, color.RGBA{80, 220, 120, 255})
+
+	// Source energy totals
+	active := 0
+	energy, maxEnergy := 0.0, 0.0
+	for _, source := range sources {
+		if source.IsActive {
+			active++
+		}
+		energy += source.Energy
+		maxEnergy += source.MaxEnergy
+	}
+	percent := 0.0
+	if maxEnergy > 0 {
+		percent = energy / maxEnergy * 100
+	}
+
+	lineHeight := 16
+	y += margin
+	ebitenutil.DebugPrintAt(screen, "SOURCES", int(x)+margin, y)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Active: %d / %d", active, len(sources)), int(x)+margin, y+lineHeight)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Energy: %s / %s", r.formatEnergy(energy), r.formatEnergy(maxEnergy)), int(x)+margin, y+2*lineHeight)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("        (%.0f%%)", percent), int(x)+margin, y+3*lineHeight)
+}
+
+// drawHistogram draws a titled bar chart of a histogram with its first and last
+// bucket labeled underneath, returning the y coordinate just below it
+func (r *Renderer) drawHistogram(screen *ebiten.Image, title string, histogram map[string]int, x, y int, barColor color.Color) int {
+	const chartWidth = SidebarWidth - 20
+	ebitenutil.DebugPrintAt(screen, title, x, y)
+	top := y + 20
+
+	ebitenutil.DrawRect(screen, float64(x), float64(top), chartWidth, SidebarChartHeight, color.RGBA{30, 30, 38, 255})
+	bars := histogramBars(histogram, SidebarChartHeight)
+	if len(bars) > 0 {
+		barWidth := float64(chartWidth) / float64(len(bars))
+		bottom := float64(top + SidebarChartHeight)
+		for i, bar := range bars {
+			// Leave a one-pixel gap between bars when there's room for it
+			width := math.Max(1, barWidth-1)
+			ebitenutil.DrawRect(screen, float64(x)+float64(i)*barWidth, bottom-bar.Height, width, bar.Height, barColor)
+		}
+
+		ebitenutil.DebugPrintAt(screen, bars[0].Label, x, top+SidebarChartHeight+2)
+		last := bars[len(bars)-1].Label
+		ebitenutil.DebugPrintAt(screen, last, x+chartWidth-len(last)*6, top+SidebarChartHeight+2)
+	}
+
+	return top + SidebarChartHeight + 20
+}
@@ -0,0 +1,28 @@
+package renderer
+
+import "testing"
+
+func TestHistogramBars(t *testing.T) {
+	histogram := map[string]int{"10": 2, "5": 4, "100": 1, "0": 0}
+
+	bars := histogramBars(histogram, 80)
+
+	want := []histogramBar{
+		{Label: "0", Count: 0, Height: 0},
+		{Label: "5", Count: 4, Height: 80},
+		{Label: "10", Count: 2, Height: 40},
+		{Label: "100", Count: 1, Height: 20},
+	}
+	if len(bars) != len(want) {
+		t.Fatalf("Got %d bars; want %d", len(bars), len(want))
+	}
+	for i := range want {
+		if bars[i] != want[i] {
+			t.Errorf("Bar %d = %+v; want %+v", i, bars[i], want[i])
+		}
+	}
+
+	if empty := histogramBars(map[string]int{}, 80); len(empty) != 0 {
+		t.Errorf("Empty histogram gave %d bars; want none", len(empty))
+	}
+}
@@ -0,0 +1,152 @@
+package renderer
+
+import (
+	"math"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// CullMargin is how far outside the view, in pixels, an organism can be and still
+// be drawn, so ones partly inside the view aren't cut off
+const CullMargin = 20.0
+
+// viewport maps world coordinates onto the window with a single scale on both
+// axes, so the world keeps its aspect ratio and is centered with margins
+type viewport struct {
+	minX, minY       float64 // World coordinates shown at the viewport's top-left corner
+	scale            float64 // Screen pixels per world unit
+	offsetX, offsetY float64 // Margin before the world on each axis, in pixels
+}
+
+// fitViewport returns the largest viewport that shows all of bounds within the window
+func fitViewport(bounds types.Rect, windowWidth, windowHeight int) viewport {
+	width := bounds.Max.X - bounds.Min.X
+	height := bounds.Max.Y - bounds.Min.Y
+	if width <= 0 || height <= 0 {
+		return viewport{minX: bounds.Min.X, minY: bounds.Min.Y, scale: 1}
+	}
+
+	scale := math.Min(float64(windowWidth)/width, float64(windowHeight)/height)
+	return viewport{
+		minX:    bounds.Min.X,
+		minY:    bounds.Min.Y,
+		scale:   scale,
+		offsetX: (float64(windowWidth) - width*scale) / 2,
+		offsetY: (float64(windowHeight) - height*scale) / 2,
+	}
+}
+
+// Camera constants
+const (
+	MinCameraZoom  = 0.5  // Furthest out the camera zooms, relative to fitting the whole world
+	MaxCameraZoom  = 20.0 // Furthest in the camera zooms
+	CameraZoomStep = 1.1  // Zoom factor per mouse wheel notch
+	CameraPanSpeed = 0.5  // Fraction of the view's width panned per second with the arrow keys
+)
+
+// camera is what the main view shows: the world point at the view's center and a
+// zoom relative to fitting the whole world, with a home view to return to
+type camera struct {
+	center     types.Point
+	zoom       float64
+	homeCenter types.Point
+	homeZoom   float64
+}
+
+// newCamera returns a camera at the home view set in the render config, which
+// defaults to fitting the whole world
+func newCamera(bounds types.Rect, render config.RenderConfig) camera {
+	home := types.Point{X: (bounds.Min.X + bounds.Max.X) / 2, Y: (bounds.Min.Y + bounds.Max.Y) / 2}
+	if render.InitialCameraCenter != nil {
+		home = types.Point{X: render.InitialCameraCenter.X, Y: render.InitialCameraCenter.Y}
+	}
+
+	zoom := render.InitialZoom
+	if zoom <= 0 {
+		zoom = 1
+	}
+
+	c := camera{homeCenter: home, homeZoom: math.Max(MinCameraZoom, math.Min(zoom, MaxCameraZoom))}
+	c.goHome()
+	return c
+}
+
+// goHome returns the camera to its home view
+func (c *camera) goHome() {
+	c.center, c.zoom = c.homeCenter, c.homeZoom
+}
+
+// zoomBy zooms in by factor, or out for factors below 1, within the zoom limits
+func (c *camera) zoomBy(factor float64) {
+	c.zoom = math.Max(MinCameraZoom, math.Min(c.zoom*factor, MaxCameraZoom))
+}
+
+// zoomAt zooms like zoomBy while keeping the world point anchor, such as the one
+// under the cursor, at the same place on screen
+func (c *camera) zoomAt(factor float64, anchor types.Point) {
+	previous := c.zoom
+	c.zoomBy(factor)
+	ratio := previous / c.zoom
+	c.center = types.Point{
+		X: anchor.X + (c.center.X-anchor.X)*ratio,
+		Y: anchor.Y + (c.center.Y-anchor.Y)*ratio,
+	}
+}
+
+// pan moves the camera's center by (dx, dy) world units
+func (c *camera) pan(dx, dy float64) {
+	c.center.X += dx
+	c.center.Y += dy
+}
+
+// clamp keeps the view from straying more than one screen beyond the world: the
+// view may show at most a screen's width or height of empty space past an edge
+func (c *camera) clamp(bounds types.Rect, windowWidth, windowHeight int) {
+	if c.zoom <= 0 {
+		return
+	}
+
+	scale := fitViewport(bounds, windowWidth, windowHeight).scale * c.zoom
+	halfWidth := float64(windowWidth) / 2 / scale
+	halfHeight := float64(windowHeight) / 2 / scale
+	c.center.X = math.Max(bounds.Min.X-halfWidth, math.Min(c.center.X, bounds.Max.X+halfWidth))
+	c.center.Y = math.Max(bounds.Min.Y-halfHeight, math.Min(c.center.Y, bounds.Max.Y+halfHeight))
+}
+
+// viewport returns the camera's view of a world with the given bounds in a window
+// of the given size. A zero camera fits the whole world.
+func (c camera) viewport(bounds types.Rect, windowWidth, windowHeight int) viewport {
+	fit := fitViewport(bounds, windowWidth, windowHeight)
+	if c.zoom <= 0 {
+		return fit
+	}
+
+	return viewport{
+		minX:    c.center.X,
+		minY:    c.center.Y,
+		scale:   fit.scale * c.zoom,
+		offsetX: float64(windowWidth) / 2,
+		offsetY: float64(windowHeight) / 2,
+	}
+}
+
+// toScreen converts a world point to screen coordinates
+func (v viewport) toScreen(point types.Point) (float64, float64) {
+	return v.offsetX + (point.X-v.minX)*v.scale, v.offsetY + (point.Y-v.minY)*v.scale
+}
+
+// toWorld converts screen coordinates to a world point
+func (v viewport) toWorld(screenX, screenY float64) types.Point {
+	return types.Point{
+		X: v.minX + (screenX-v.offsetX)/v.scale,
+		Y: v.minY + (screenY-v.offsetY)/v.scale,
+	}
+}
+
+// onScreen reports whether screen position (x, y) is within margin pixels of a
+// width x height view, so anything outside it can be skipped when drawing
+func onScreen(x, y float64, width, height int, margin float64) bool {
+	return x >= -margin && x <= float64(width)+margin &&
+		y >= -margin && y <= float64(height)+margin
+}
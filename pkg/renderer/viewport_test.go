@@ -0,0 +1,184 @@
+package renderer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+func TestFitViewportPreservesAspectRatio(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height float64
+	}{
+		{"Wide world", 2000, 1000},
+		{"Tall world", 500, 1500},
+		{"Square world", 1000, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bounds := types.NewRect(0, 0, tt.width, tt.height)
+			v := fitViewport(bounds, 800, 800)
+
+			// A world circle should map to a screen circle
+			center := types.Point{X: tt.width / 2, Y: tt.height / 2}
+			radius := math.Min(tt.width, tt.height) / 4
+			cx, cy := v.toScreen(center)
+			rx, _ := v.toScreen(types.Point{X: center.X + radius, Y: center.Y})
+			_, ry := v.toScreen(types.Point{X: center.X, Y: center.Y + radius})
+			if math.Abs((rx-cx)-(ry-cy)) > 1e-9 {
+				t.Errorf("Circle radii on screen = %v, %v; want equal", rx-cx, ry-cy)
+			}
+
+			// The world is centered and fits inside the window
+			if math.Abs(cx-400) > 1e-9 || math.Abs(cy-400) > 1e-9 {
+				t.Errorf("World center maps to (%v, %v); want (400, 400)", cx, cy)
+			}
+			x0, y0 := v.toScreen(bounds.Min)
+			x1, y1 := v.toScreen(bounds.Max)
+			if x0 < -1e-9 || y0 < -1e-9 || x1 > 800+1e-9 || y1 > 800+1e-9 {
+				t.Errorf("World spans (%v, %v)-(%v, %v); want it inside the window", x0, y0, x1, y1)
+			}
+
+			// Converting back recovers the world point
+			back := v.toWorld(rx, cy)
+			if math.Abs(back.X-(center.X+radius)) > 1e-9 || math.Abs(back.Y-center.Y) > 1e-9 {
+				t.Errorf("Round trip = %v; want %v", back, types.Point{X: center.X + radius, Y: center.Y})
+			}
+		})
+	}
+}
+
+func TestOnScreen(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y float64
+		want bool
+	}{
+		{"Center", 400, 300, true},
+		{"Just outside within margin", -10, 300, true},
+		{"Far left", -100, 300, false},
+		{"Below the view", 400, 700, false},
+		{"Past the right edge", 900, 300, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := onScreen(tt.x, tt.y, 800, 600, CullMargin); got != tt.want {
+				t.Errorf("onScreen(%v, %v) = %v; want %v", tt.x, tt.y, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCameraHome(t *testing.T) {
+	bounds := types.NewRect(0, 0, 1000, 1000)
+
+	t.Run("Defaults to fitting the whole world", func(t *testing.T) {
+		got := newCamera(bounds, config.RenderConfig{}).viewport(bounds, 800, 600)
+		want := fitViewport(bounds, 800, 600)
+		for _, point := range []types.Point{bounds.Min, bounds.Max, {X: 250, Y: 750}} {
+			gotX, gotY := got.toScreen(point)
+			wantX, wantY := want.toScreen(point)
+			if math.Abs(gotX-wantX) > 1e-9 || math.Abs(gotY-wantY) > 1e-9 {
+				t.Errorf("Default camera maps %v to (%v, %v); want (%v, %v), as the whole-world fit does", point, gotX, gotY, wantX, wantY)
+			}
+		}
+	})
+
+	t.Run("Starts at the configured home", func(t *testing.T) {
+		c := newCamera(bounds, config.RenderConfig{InitialZoom: 4, InitialCameraCenter: &config.PointConfig{X: 200, Y: 300}})
+		v := c.viewport(bounds, 800, 800)
+
+		if x, y := v.toScreen(types.Point{X: 200, Y: 300}); x != 400 || y != 400 {
+			t.Errorf("Configured center maps to (%v, %v); want the view center (400, 400)", x, y)
+		}
+		if want := fitViewport(bounds, 800, 800).scale * 4; math.Abs(v.scale-want) > 1e-9 {
+			t.Errorf("Camera scale = %v; want 4x the whole-world fit, %v", v.scale, want)
+		}
+	})
+
+	t.Run("Home returns to the configured home, not the whole world", func(t *testing.T) {
+		c := newCamera(bounds, config.RenderConfig{InitialZoom: 4, InitialCameraCenter: &config.PointConfig{X: 200, Y: 300}})
+		home := c.viewport(bounds, 800, 800)
+
+		c.pan(150, -75)
+		c.zoomBy(0.5)
+		if moved := c.viewport(bounds, 800, 800); moved == home {
+			t.Fatal("Panning and zooming left the view unchanged")
+		}
+
+		c.goHome()
+		if got := c.viewport(bounds, 800, 800); got != home {
+			t.Errorf("View after Home = %+v; want the configured home %+v", got, home)
+		}
+	})
+
+	t.Run("Zoom is clamped", func(t *testing.T) {
+		c := newCamera(bounds, config.RenderConfig{InitialZoom: 1000})
+		if c.zoom != MaxCameraZoom {
+			t.Errorf("Zoom = %v; want the maximum %v", c.zoom, MaxCameraZoom)
+		}
+		c.zoomBy(1e-6)
+		if c.zoom != MinCameraZoom {
+			t.Errorf("Zoom = %v; want the minimum %v", c.zoom, MinCameraZoom)
+		}
+	})
+}
+
+func TestCameraPanAndZoom(t *testing.T) {
+	bounds := types.NewRect(0, 0, 1000, 1000)
+
+	t.Run("Zooming keeps the anchor under the cursor", func(t *testing.T) {
+		c := newCamera(bounds, config.RenderConfig{})
+		anchor := types.Point{X: 300, Y: 700}
+		beforeX, beforeY := c.viewport(bounds, 800, 800).toScreen(anchor)
+
+		for _, factor := range []float64{2, 3, 0.25} {
+			c.zoomAt(factor, anchor)
+			afterX, afterY := c.viewport(bounds, 800, 800).toScreen(anchor)
+			if math.Abs(afterX-beforeX) > 1e-9 || math.Abs(afterY-beforeY) > 1e-9 {
+				t.Errorf("After zooming by %v, anchor moved from (%v, %v) to (%v, %v)", factor, beforeX, beforeY, afterX, afterY)
+			}
+		}
+	})
+
+	t.Run("Screen and world conversions are inverses", func(t *testing.T) {
+		c := newCamera(bounds, config.RenderConfig{InitialZoom: 3})
+		c.pan(120, -40)
+		v := c.viewport(bounds, 800, 600)
+		for _, point := range []types.Point{{X: 0, Y: 0}, {X: 640, Y: 410}, {X: 1000, Y: 1000}} {
+			got := v.toWorld(v.toScreen(point))
+			if math.Abs(got.X-point.X) > 1e-9 || math.Abs(got.Y-point.Y) > 1e-9 {
+				t.Errorf("Round trip of %v gave %v", point, got)
+			}
+		}
+	})
+
+	t.Run("Panning stops one screen beyond the world", func(t *testing.T) {
+		c := newCamera(bounds, config.RenderConfig{InitialZoom: 2})
+		c.pan(1e6, -1e6)
+		c.clamp(bounds, 800, 800)
+
+		// At zoom 2 the view spans half the world, so its far edges may reach
+		// a view's width past the world's edges and no further
+		v := c.viewport(bounds, 800, 800)
+		if right := v.toWorld(800, 400).X; math.Abs(right-(bounds.Max.X+500)) > 1e-9 {
+			t.Errorf("View's right edge at x=%v; want one screen past the world, %v", right, bounds.Max.X+500)
+		}
+		if top := v.toWorld(400, 0).Y; math.Abs(top-(bounds.Min.Y-500)) > 1e-9 {
+			t.Errorf("View's top edge at y=%v; want one screen past the world, %v", top, bounds.Min.Y-500)
+		}
+
+		// A view inside the world is left alone
+		inside := newCamera(bounds, config.RenderConfig{InitialZoom: 2})
+		before := inside.center
+		inside.clamp(bounds, 800, 800)
+		if inside.center != before {
+			t.Errorf("Clamping moved a view inside the world from %v to %v", before, inside.center)
+		}
+	})
+}
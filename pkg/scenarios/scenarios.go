@@ -0,0 +1,152 @@
+// Package scenarios is a library of named, fully specified starting states with
+// fixed seeds, for integration tests that exercise the whole simulation pipeline
+package scenarios
+
+import (
+	"math"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/simulation"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+// Scenario names
+const (
+	SingleSourceSingleOrganism = "singleSourceSingleOrganism"
+	TwoCompetingLineages       = "twoCompetingLineages"
+	StarvationWorld            = "starvationWorld"
+)
+
+// scenarioSeed is the fixed random seed every scenario runs with
+const scenarioSeed = 20240601
+
+// Scenario is a named starting state: a configuration, with its seed, and the
+// organisms and chemical sources loaded in place of a random population
+type Scenario struct {
+	Name        string
+	Description string
+	Outcome     string // The qualitative result expected after Steps steps
+	Config      config.SimulationConfig
+	Start       world.Scenario
+	Steps       int64 // How long the scenario is meant to run
+}
+
+// NewSimulator returns a simulator in the scenario's starting state
+func (s Scenario) NewSimulator() *simulation.Simulator {
+	w := world.NewWorld(s.Config)
+	w.ApplyScenario(s.Start)
+	return simulation.NewSimulator(w, s.Config)
+}
+
+// All returns every scenario in the library
+func All() []Scenario {
+	return []Scenario{
+		singleSourceSingleOrganism(),
+		twoCompetingLineages(),
+		starvationWorld(),
+	}
+}
+
+// ByName returns the scenario with the given name
+func ByName(name string) (Scenario, bool) {
+	for _, scenario := range All() {
+		if scenario.Name == name {
+			return scenario, true
+		}
+	}
+	return Scenario{}, false
+}
+
+// baseConfig returns a small, seeded world with no random organisms or sources
+func baseConfig() config.SimulationConfig {
+	cfg := config.DefaultConfig()
+	cfg.World.Width, cfg.World.Height = 400, 400
+	cfg.Organism.Count = 0
+	cfg.Chemical.Count = 0
+	cfg.Chemical.RegenerationEnabled = false
+	cfg.RandomSeed = scenarioSeed
+	return cfg
+}
+
+// centerSource returns a chemical source in the middle of the scenario world
+func centerSource() types.ChemicalSource {
+	return types.NewChemicalSource(types.Point{X: 200, Y: 200}, 100, 0.02)
+}
+
+// founders returns count organisms spaced evenly on a circle of the given radius
+// around the source, facing along it, each with the preference given for its
+// starting point and the given fraction of its energy capacity
+func founders(source types.ChemicalSource, count int, radius float64, preference func(types.Point) float64, energy float64) []types.Organism {
+	organisms := make([]types.Organism, count)
+	for i := range organisms {
+		angle := 2 * math.Pi * float64(i) / float64(count)
+		position := types.Point{
+			X: source.Position.X + radius*math.Cos(angle),
+			Y: source.Position.Y + radius*math.Sin(angle),
+		}
+
+		org := types.NewOrganism(position, angle+math.Pi/2, preference(position), 1.0, types.DefaultSensorAngles())
+		org.Energy = energy * org.EnergyCapacity
+		organisms[i] = org
+	}
+	return organisms
+}
+
+// singleSourceSingleOrganism has one organism orbiting the only source at the
+// concentration it prefers
+func singleSourceSingleOrganism() Scenario {
+	source := centerSource()
+	return Scenario{
+		Name:        SingleSourceSingleOrganism,
+		Description: "One source and one organism that prefers the concentration it starts at",
+		Outcome:     "The population survives and stays near the source",
+		Config:      baseConfig(),
+		Start: world.Scenario{
+			Organisms:       founders(source, 1, 40, source.GetConcentrationAt, 0.8),
+			ChemicalSources: []types.ChemicalSource{source},
+		},
+		Steps: 3600,
+	}
+}
+
+// twoCompetingLineages alternates founders that prefer the concentration around
+// them with founders, too low on energy to reproduce straight away, that prefer
+// more than the source provides anywhere
+func twoCompetingLineages() Scenario {
+	source := centerSource()
+	matched := founders(source, 6, 40, source.GetConcentrationAt, 0.8)
+	mismatched := founders(source, 6, 60, func(types.Point) float64 { return 3 * source.Strength }, 0.5)
+
+	organisms := make([]types.Organism, 0, len(matched)+len(mismatched))
+	for i := range matched {
+		organisms = append(organisms, matched[i], mismatched[i])
+	}
+
+	return Scenario{
+		Name:        TwoCompetingLineages,
+		Description: "Founders suited to the source alternate with founders that prefer far more than it provides",
+		Outcome:     "The suited founders' lineages dominate the population",
+		Config:      baseConfig(),
+		Start: world.Scenario{
+			Organisms:       organisms,
+			ChemicalSources: []types.ChemicalSource{source},
+		},
+		Steps: 3600,
+	}
+}
+
+// starvationWorld has organisms low on energy and no sources to feed from
+func starvationWorld() Scenario {
+	source := centerSource()
+	return Scenario{
+		Name:        StarvationWorld,
+		Description: "Organisms low on energy in a world with no chemical sources",
+		Outcome:     "Every organism starves",
+		Config:      baseConfig(),
+		Start: world.Scenario{
+			Organisms: founders(source, 8, 60, source.GetConcentrationAt, 0.02),
+		},
+		Steps: 3600,
+	}
+}
@@ -0,0 +1,88 @@
+package scenarios
+
+import (
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/simulation"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// lineageShare returns the fraction of the organisms descended from the given roots
+func lineageShare(organisms []types.Organism, roots map[int64]bool) float64 {
+	if len(organisms) == 0 {
+		return 0
+	}
+
+	count := 0
+	for _, org := range organisms {
+		if roots[org.RootAncestor()] {
+			count++
+		}
+	}
+	return float64(count) / float64(len(organisms))
+}
+
+func TestScenarios(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(t *testing.T, start []types.Organism, sim *simulation.Simulator)
+	}{
+		{SingleSourceSingleOrganism, func(t *testing.T, start []types.Organism, sim *simulation.Simulator) {
+			organisms := sim.World.GetOrganisms()
+			if len(organisms) == 0 {
+				t.Fatal("Population died out")
+			}
+			source := sim.World.GetChemicalSources()[0]
+			for _, org := range organisms {
+				if distance := org.Position.DistanceTo(source.Position); distance > 150 {
+					t.Errorf("Organism %d wandered %.0f from the source", org.ID, distance)
+				}
+			}
+		}},
+		{TwoCompetingLineages, func(t *testing.T, start []types.Organism, sim *simulation.Simulator) {
+			// Founders alternate suited, unsuited
+			suited := make(map[int64]bool)
+			for i := 0; i < len(start); i += 2 {
+				suited[start[i].RootAncestor()] = true
+			}
+
+			organisms := sim.World.GetOrganisms()
+			if share := lineageShare(organisms, suited); share < 0.8 {
+				t.Errorf("Suited lineages hold %.0f%% of %d organisms; want them to dominate", share*100, len(organisms))
+			}
+		}},
+		{StarvationWorld, func(t *testing.T, start []types.Organism, sim *simulation.Simulator) {
+			if population, _ := sim.World.GetPopulationInfo(); population != 0 {
+				t.Errorf("%d organisms survived with nothing to eat", population)
+			}
+			if starved := sim.World.DeathsByCause()[types.DeathCauseStarvation]; starved != len(start) {
+				t.Errorf("%d organisms starved; want all %d", starved, len(start))
+			}
+		}},
+	}
+
+	if len(tests) != len(All()) {
+		t.Fatalf("Testing %d scenarios; the library has %d", len(tests), len(All()))
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenario, ok := ByName(tt.name)
+			if !ok {
+				t.Fatalf("No scenario named %q", tt.name)
+			}
+
+			sim := scenario.NewSimulator()
+			start := sim.World.GetOrganisms()
+			if len(start) != len(scenario.Start.Organisms) {
+				t.Fatalf("Scenario started with %d organisms; want %d", len(start), len(scenario.Start.Organisms))
+			}
+
+			for sim.StepCount < scenario.Steps {
+				sim.Step()
+			}
+			t.Logf("%s after %d steps: population %d", scenario.Name, sim.StepCount, len(sim.World.GetOrganisms()))
+			tt.check(t, start, sim)
+		})
+	}
+}
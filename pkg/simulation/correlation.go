@@ -0,0 +1,127 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// trait names a numeric organism trait and how to read it
+type trait struct {
+	name  string
+	value func(types.Organism) float64
+}
+
+// correlatedTraits are the traits compared by TraitCorrelationMatrix, in matrix order
+var correlatedTraits = []trait{
+	{"Preference", func(o types.Organism) float64 { return o.ChemPreference }},
+	{"Speed", func(o types.Organism) float64 { return o.Speed }},
+	{"EnergyEfficiency", func(o types.Organism) float64 { return o.EnergyEfficiency }},
+	{"MetabolicRate", func(o types.Organism) float64 { return o.MetabolicRate }},
+}
+
+// TraitCorrelations holds the pairwise Pearson correlations between organism traits.
+// Matrix[i][j] is the correlation between Traits[i] and Traits[j]; pairs involving a
+// trait that doesn't vary across the population are reported as 0.
+type TraitCorrelations struct {
+	Traits []string    `json:"traits"`
+	Matrix [][]float64 `json:"matrix"`
+}
+
+// TraitCorrelationMatrix computes the correlations between preference, speed,
+// efficiency and metabolic rate across the organisms. Organisms with NaN or
+// infinite values are left out, as in the other statistics.
+func TraitCorrelationMatrix(organisms []types.Organism) TraitCorrelations {
+	// Collect each trait's values across the usable organisms
+	values := make([][]float64, len(correlatedTraits))
+	names := make([]string, len(correlatedTraits))
+	for i, t := range correlatedTraits {
+		names[i] = t.name
+		for _, org := range organisms {
+			if finiteOrganism(org) {
+				values[i] = append(values[i], t.value(org))
+			}
+		}
+	}
+
+	matrix := make([][]float64, len(correlatedTraits))
+	for i := range matrix {
+		matrix[i] = make([]float64, len(correlatedTraits))
+		for j := range matrix[i] {
+			if i == j {
+				matrix[i][j] = 1
+				continue
+			}
+			matrix[i][j] = pearson(values[i], values[j])
+		}
+	}
+
+	return TraitCorrelations{Traits: names, Matrix: matrix}
+}
+
+// pearson returns the Pearson correlation of xs and ys, or 0 if either doesn't vary
+func pearson(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0
+	}
+
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var covariance, varianceX, varianceY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		covariance += dx * dy
+		varianceX += dx * dx
+		varianceY += dy * dy
+	}
+
+	if varianceX == 0 || varianceY == 0 {
+		return 0
+	}
+	return covariance / math.Sqrt(varianceX*varianceY)
+}
+
+// ExportCSV writes the matrix as CSV with the trait names as row and column headers
+func (c TraitCorrelations) ExportCSV(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(append([]string{"Trait"}, c.Traits...)); err != nil {
+		return err
+	}
+	for i, row := range c.Matrix {
+		record := []string{c.Traits[i]}
+		for _, value := range row {
+			record = append(record, fmt.Sprintf("%.4f", value))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportJSON writes the trait names and matrix to a JSON file
+func (c TraitCorrelations) ExportJSON(filename string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
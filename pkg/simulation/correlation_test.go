@@ -0,0 +1,65 @@
+package simulation
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+func TestTraitCorrelationMatrix(t *testing.T) {
+	// Speed rises in lockstep with preference; efficiency falls as preference rises
+	var organisms []types.Organism
+	for i := 0; i < 10; i++ {
+		org := types.NewOrganism(types.Point{X: 10, Y: 10}, 0, 10+float64(i)*5, 1.0+float64(i)*0.2, types.DefaultSensorAngles())
+		org.EnergyEfficiency = 2.0 - float64(i)*0.1
+		org.MetabolicRate = 0.1 // Never varies
+		organisms = append(organisms, org)
+	}
+
+	correlations := TraitCorrelationMatrix(organisms)
+
+	index := make(map[string]int)
+	for i, name := range correlations.Traits {
+		index[name] = i
+	}
+	at := func(a, b string) float64 {
+		return correlations.Matrix[index[a]][index[b]]
+	}
+
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Preference", "Speed", 1},
+		{"Speed", "Preference", 1},
+		{"Preference", "EnergyEfficiency", -1},
+		{"Preference", "MetabolicRate", 0},
+		{"MetabolicRate", "MetabolicRate", 1},
+	}
+	for _, tt := range tests {
+		if got := at(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Correlation of %s and %s = %v; want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	// The JSON export round-trips
+	path := filepath.Join(t.TempDir(), "correlations.json")
+	if err := correlations.ExportJSON(path); err != nil {
+		t.Fatalf("ExportJSON() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded TraitCorrelations
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(loaded.Matrix) != len(correlations.Traits) {
+		t.Errorf("Exported matrix has %d rows; want %d", len(loaded.Matrix), len(correlations.Traits))
+	}
+}
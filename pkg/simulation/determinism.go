@@ -0,0 +1,66 @@
+package simulation
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+// StateFingerprint serializes the world's organisms and chemical sources with gob,
+// so two worlds in the same state produce byte-identical fingerprints
+func StateFingerprint(w *world.World) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(w.CurrentScenario()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// VerifyDeterminism runs the seeded configuration twice from scratch for the given
+// number of steps and returns an error, describing the first differences, if the
+// final world states are not identical
+func VerifyDeterminism(cfg config.SimulationConfig, steps int) error {
+	return verifyDeterminism(cfg, steps, nil)
+}
+
+// verifyDeterminism is VerifyDeterminism with an optional hook that runs after each
+// step, given the run number (0 or 1) and the simulator, for injecting faults in tests
+func verifyDeterminism(cfg config.SimulationConfig, steps int, afterStep func(run int, s *Simulator)) error {
+	if cfg.RandomSeed == 0 {
+		return errors.New("determinism check needs a fixed, nonzero random seed")
+	}
+
+	var fingerprints [2][]byte
+	var worlds [2]*world.World
+	for run := range fingerprints {
+		simulator := NewSimulator(world.NewWorld(cfg), cfg)
+		for i := 0; i < steps; i++ {
+			simulator.Step()
+			if afterStep != nil {
+				afterStep(run, simulator)
+			}
+		}
+
+		fingerprint, err := StateFingerprint(simulator.World)
+		if err != nil {
+			return fmt.Errorf("failed to serialize world state: %w", err)
+		}
+		fingerprints[run] = fingerprint
+		worlds[run] = simulator.World
+	}
+
+	if !bytes.Equal(fingerprints[0], fingerprints[1]) {
+		diffs := world.DiffWorlds(worlds[0], worlds[1])
+		if len(diffs) == 0 {
+			diffs = []string{fmt.Sprintf("states %d and %d bytes", len(fingerprints[0]), len(fingerprints[1]))}
+		}
+		return fmt.Errorf("runs diverged after %d steps with seed %d: %s",
+			steps, cfg.RandomSeed, strings.Join(diffs, "; "))
+	}
+	return nil
+}
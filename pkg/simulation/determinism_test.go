@@ -0,0 +1,195 @@
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+func TestVerifyDeterminism(t *testing.T) {
+	// Organisms that feed, disperse and reproduce alongside regenerating sources, so
+	// the check covers turns, traits, IDs and mutations drawn from the seeded generators
+	cfg := config.DefaultConfig()
+	cfg.Organism.Count = 40
+	cfg.Organism.CrowdingThreshold = 1
+	cfg.Organism.CrowdingRadius = 30
+	cfg.Organism.DispersalDuration = 2
+	cfg.Chemical.RegenerationEnabled = true
+	cfg.Chemical.RegenerationProbability = 0.5
+	cfg.RandomSeed = 12345
+	cfg.SimulationSpeed = 5.0
+
+	t.Run("Seeded run is deterministic", func(t *testing.T) {
+		births := 0
+		err := verifyDeterminism(cfg, 200, func(run int, s *Simulator) {
+			if run == 0 && s.StepCount == 200 {
+				for _, org := range s.World.GetOrganisms() {
+					if org.ParentID != 0 {
+						births++
+					}
+				}
+			}
+		})
+		if err != nil {
+			t.Errorf("Expected identical runs, got: %v", err)
+		}
+		if births == 0 {
+			t.Error("Expected organisms to reproduce during the run")
+		}
+	})
+
+	t.Run("Detects injected nondeterminism", func(t *testing.T) {
+		injected := false
+		err := verifyDeterminism(cfg, 200, func(run int, s *Simulator) {
+			if run == 1 && !injected {
+				s.World.AddChemicalSource(types.NewChemicalSource(types.Point{X: 10, Y: 10}, 50, 0.01))
+				injected = true
+			}
+		})
+		if err == nil || !strings.Contains(err.Error(), "diverged") {
+			t.Fatalf("Expected divergence to be detected, got: %v", err)
+		}
+		if !strings.Contains(err.Error(), "chemical source count") {
+			t.Errorf("Expected the error to pinpoint the extra source, got: %v", err)
+		}
+	})
+
+	t.Run("Requires a fixed seed", func(t *testing.T) {
+		unseeded := cfg
+		unseeded.RandomSeed = 0
+		if err := VerifyDeterminism(unseeded, 1); err == nil {
+			t.Error("Expected an error without a fixed seed")
+		}
+	})
+}
+
+func TestSourceRegenerationDeterminism(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Organism.Count = 0
+	cfg.Chemical.Count = 4
+	cfg.Chemical.RegenerationEnabled = true
+	cfg.Chemical.RegenerationProbability = 5.0
+	cfg.Chemical.TargetSystemEnergy = 1e9
+
+	// Start one depleted source short of the target count, so regeneration both
+	// reactivates a source and creates new ones
+	run := func() []string {
+		w := world.NewWorld(cfg)
+		sources := w.GetChemicalSources()[:2]
+		sources[1].Energy = 0
+		sources[1].IsActive = false
+		w.ApplyScenario(world.Scenario{ChemicalSources: sources})
+
+		activePositions := func() string {
+			var active []types.Point
+			for _, source := range w.GetChemicalSources() {
+				if source.IsActive {
+					active = append(active, source.Position)
+				}
+			}
+			return fmt.Sprint(active)
+		}
+
+		sim := NewSimulator(w, cfg)
+		var events []string
+		previous := activePositions()
+		for i := 0; i < 600; i++ {
+			sim.Step()
+			if current := activePositions(); current != previous {
+				events = append(events, fmt.Sprintf("step %d: %s", sim.StepCount, current))
+				previous = current
+			}
+		}
+		return events
+	}
+
+	first, second := run(), run()
+	if len(first) < 2 {
+		t.Fatalf("Only %d regeneration events; want the test to exercise regeneration", len(first))
+	}
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Errorf("Seeded runs regenerated differently:\n%v\n%v", first, second)
+	}
+}
+
+func TestCustomSourceDeterminism(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Organism.Count = 0
+	cfg.Chemical.Count = 3
+	cfg.Chemical.RegenerationEnabled = true
+	cfg.Chemical.RegenerationProbability = 0.5
+
+	calls := 0
+	counting := func(seed int64) rand.Source {
+		calls++
+		return types.PCGSource(seed)
+	}
+
+	run := func(factory types.SourceFactory) []byte {
+		w := world.NewWorldWithSource(cfg, factory)
+		sim := NewSimulatorWithSource(w, cfg, factory)
+		for i := 0; i < 300; i++ {
+			sim.Step()
+		}
+		fingerprint, err := StateFingerprint(w)
+		if err != nil {
+			t.Fatalf("StateFingerprint() error = %v", err)
+		}
+		return fingerprint
+	}
+
+	first, second := run(counting), run(counting)
+	if calls == 0 {
+		t.Fatal("Custom source factory was never used")
+	}
+	if string(first) != string(second) {
+		t.Error("Runs with the same custom source and seed diverged")
+	}
+
+	// The layout really comes from the custom generator, not the default one
+	if string(first) == string(run(nil)) {
+		t.Error("Custom source produced the same run as the default source")
+	}
+}
+
+func TestSeededPopulationsMatch(t *testing.T) {
+	// Fast enough that the founders reproduce, with crowding to exercise dispersal
+	cfg := config.DefaultConfig()
+	cfg.Organism.Count = 40
+	cfg.Organism.CrowdingThreshold = 3
+	cfg.Organism.CrowdingRadius = 20
+	cfg.Organism.DispersalDuration = 2
+	cfg.RandomSeed = 21
+	cfg.SimulationSpeed = 5.0
+
+	type member struct {
+		ID         int64
+		Preference float64
+	}
+	run := func() []member {
+		sim := NewSimulator(world.NewWorld(cfg), cfg)
+		for i := 0; i < 200; i++ {
+			sim.Step()
+		}
+
+		var population []member
+		for _, org := range sim.World.GetOrganisms() {
+			population = append(population, member{org.ID, org.ChemPreference})
+		}
+		return population
+	}
+
+	first, second := run(), run()
+	if len(first) <= cfg.Organism.Count {
+		t.Fatalf("Population grew from %d to only %d; want the test to exercise reproduction", cfg.Organism.Count, len(first))
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Seeded populations differ:\n%v\n%v", first, second)
+	}
+}
@@ -0,0 +1,251 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+)
+
+// statsMetric is one numeric column of the statistics CSV export
+type statsMetric struct {
+	name  string
+	value func(SimulationStats) float64
+}
+
+// statsMetrics lists the columns of statsCSVHeader after Time, in the same order
+var statsMetrics = []statsMetric{
+	{"OrganismCount", func(s SimulationStats) float64 { return float64(s.Organisms.Count) }},
+	{"AveragePreference", func(s SimulationStats) float64 { return s.Organisms.AveragePreference }},
+	{"PreferenceStdDev", func(s SimulationStats) float64 { return s.Organisms.PreferenceStdDev }},
+	{"AverageConcentration", func(s SimulationStats) float64 { return s.Organisms.AverageConcentration }},
+	{"PreferenceExposureRatio", func(s SimulationStats) float64 { return s.Organisms.PreferenceExposureRatio }},
+	{"MaxConcentration", func(s SimulationStats) float64 { return s.Chemicals.MaxConcentration }},
+}
+
+// MetricSummary describes how one metric varied over a bucket
+type MetricSummary struct {
+	Mean float64
+	Min  float64
+	Max  float64
+}
+
+// StatsBucket aggregates the samples taken during one fixed span of simulation time
+type StatsBucket struct {
+	Start   float64                  // Simulation time the bucket starts at
+	End     float64                  // Simulation time the next bucket starts at
+	Samples int                      // Number of samples aggregated
+	Metrics map[string]MetricSummary // Keyed by statistics CSV column name
+}
+
+// statsBucketer aggregates a time-ordered series of samples into fixed buckets.
+// Buckets no sample falls into are skipped.
+type statsBucketer struct {
+	bucketSeconds float64
+	index         int64           // Bucket the current samples fall into
+	samples       int             // Samples aggregated into the current bucket
+	summaries     []MetricSummary // Running min and max, in statsMetrics order
+	sums          []float64       // Running totals for the means, in statsMetrics order
+}
+
+// newStatsBucketer creates a bucketer for buckets of the given length
+func newStatsBucketer(bucketSeconds float64) *statsBucketer {
+	return &statsBucketer{
+		bucketSeconds: bucketSeconds,
+		summaries:     make([]MetricSummary, len(statsMetrics)),
+		sums:          make([]float64, len(statsMetrics)),
+	}
+}
+
+// add aggregates a sample, returning the previous bucket if the sample starts a new one
+func (b *statsBucketer) add(stat SimulationStats) (StatsBucket, bool) {
+	var finished StatsBucket
+	index := int64(math.Floor(stat.Time / b.bucketSeconds))
+	done := b.samples > 0 && index != b.index
+	if done {
+		finished, _ = b.flush()
+	}
+
+	b.index = index
+	for i, metric := range statsMetrics {
+		value := metric.value(stat)
+		if b.samples == 0 {
+			b.summaries[i] = MetricSummary{Min: value, Max: value}
+		} else {
+			b.summaries[i].Min = math.Min(b.summaries[i].Min, value)
+			b.summaries[i].Max = math.Max(b.summaries[i].Max, value)
+		}
+		b.sums[i] += value
+	}
+	b.samples++
+
+	return finished, done
+}
+
+// flush returns the current bucket, if it has any samples, and starts an empty one
+func (b *statsBucketer) flush() (StatsBucket, bool) {
+	if b.samples == 0 {
+		return StatsBucket{}, false
+	}
+
+	bucket := StatsBucket{
+		Start:   float64(b.index) * b.bucketSeconds,
+		End:     float64(b.index+1) * b.bucketSeconds,
+		Samples: b.samples,
+		Metrics: make(map[string]MetricSummary, len(statsMetrics)),
+	}
+	for i, metric := range statsMetrics {
+		summary := b.summaries[i]
+		summary.Mean = b.sums[i] / float64(b.samples)
+		bucket.Metrics[metric.name] = summary
+		b.sums[i] = 0
+	}
+	b.samples = 0
+	return bucket, true
+}
+
+// DownsampleStats aggregates a time-ordered series of statistics into buckets of
+// bucketSeconds of simulation time, summarizing each exported metric by its mean,
+// minimum and maximum. It returns nil unless bucketSeconds is positive.
+func DownsampleStats(stats []SimulationStats, bucketSeconds float64) []StatsBucket {
+	if bucketSeconds <= 0 {
+		return nil
+	}
+
+	bucketer := newStatsBucketer(bucketSeconds)
+	var buckets []StatsBucket
+	for _, stat := range stats {
+		if bucket, ok := bucketer.add(stat); ok {
+			buckets = append(buckets, bucket)
+		}
+	}
+	if bucket, ok := bucketer.flush(); ok {
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// BucketWriter receives downsampled statistics one bucket at a time
+type BucketWriter interface {
+	WriteBucket(bucket StatsBucket) error
+	Close() error
+}
+
+// DownsamplingStatsSink aggregates samples into fixed time buckets, passing each
+// bucket on to its writers once a sample from a later bucket arrives. The last
+// bucket is written when the sink is closed.
+type DownsamplingStatsSink struct {
+	bucketer *statsBucketer
+	writers  []BucketWriter
+}
+
+// NewDownsamplingStatsSink creates a sink writing buckets of bucketSeconds of
+// simulation time, which must be positive, to every writer
+func NewDownsamplingStatsSink(bucketSeconds float64, writers ...BucketWriter) *DownsamplingStatsSink {
+	return &DownsamplingStatsSink{bucketer: newStatsBucketer(bucketSeconds), writers: writers}
+}
+
+// Write aggregates one sample, writing out the previous bucket if it's complete
+func (s *DownsamplingStatsSink) Write(stats SimulationStats) error {
+	if bucket, ok := s.bucketer.add(stats); ok {
+		return s.writeBucket(bucket)
+	}
+	return nil
+}
+
+// Close writes the last bucket and closes every writer
+func (s *DownsamplingStatsSink) Close() error {
+	var errs []error
+	if bucket, ok := s.bucketer.flush(); ok {
+		errs = append(errs, s.writeBucket(bucket))
+	}
+	for _, writer := range s.writers {
+		errs = append(errs, writer.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// writeBucket writes the bucket to every writer, even if an earlier one fails
+func (s *DownsamplingStatsSink) writeBucket(bucket StatsBucket) error {
+	var errs []error
+	for _, writer := range s.writers {
+		errs = append(errs, writer.WriteBucket(bucket))
+	}
+	return errors.Join(errs...)
+}
+
+// CSVBucketWriter writes buckets to a CSV file with a mean, min and max column
+// for each metric of the statistics CSV
+type CSVBucketWriter struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+// NewCSVBucketWriter creates the CSV file and writes its header
+func NewCSVBucketWriter(filename string) (*CSVBucketWriter, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	header := []string{"Start", "End", "Samples"}
+	for _, metric := range statsMetrics {
+		header = append(header, metric.name+"Mean", metric.name+"Min", metric.name+"Max")
+	}
+
+	w := &CSVBucketWriter{file: file, writer: csv.NewWriter(file)}
+	if err := w.writer.Write(header); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// WriteBucket appends one row for the bucket
+func (w *CSVBucketWriter) WriteBucket(bucket StatsBucket) error {
+	row := []string{
+		fmt.Sprintf("%.2f", bucket.Start),
+		fmt.Sprintf("%.2f", bucket.End),
+		fmt.Sprintf("%d", bucket.Samples),
+	}
+	for _, metric := range statsMetrics {
+		summary := bucket.Metrics[metric.name]
+		row = append(row,
+			fmt.Sprintf("%.2f", summary.Mean),
+			fmt.Sprintf("%.2f", summary.Min),
+			fmt.Sprintf("%.2f", summary.Max))
+	}
+	return w.writer.Write(row)
+}
+
+// Close flushes any buffered rows and closes the file
+func (w *CSVBucketWriter) Close() error {
+	w.writer.Flush()
+	return errors.Join(w.writer.Error(), w.file.Close())
+}
+
+// JSONBucketWriter writes buckets to a file as a JSON array, formatted as
+// json.MarshalIndent formats the result of DownsampleStats
+type JSONBucketWriter struct {
+	array *jsonArrayFile
+}
+
+// NewJSONBucketWriter creates the JSON file
+func NewJSONBucketWriter(filename string) (*JSONBucketWriter, error) {
+	array, err := newJSONArrayFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONBucketWriter{array: array}, nil
+}
+
+// WriteBucket appends one bucket to the array
+func (w *JSONBucketWriter) WriteBucket(bucket StatsBucket) error {
+	return w.array.append(bucket)
+}
+
+// Close ends the array and closes the file
+func (w *JSONBucketWriter) Close() error {
+	return w.array.close()
+}
@@ -0,0 +1,96 @@
+package simulation
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// downsampleTestStats returns samples at the given times whose values step with each sample
+func downsampleTestStats(times ...float64) []SimulationStats {
+	stats := make([]SimulationStats, len(times))
+	for i, time := range times {
+		stats[i] = SimulationStats{
+			Time:      time,
+			Organisms: OrganismStats{Count: i + 1, AveragePreference: 10 * float64(i+1)},
+			Chemicals: ChemicalStats{MaxConcentration: 100 - float64(i)},
+		}
+	}
+	return stats
+}
+
+func TestDownsampleStats(t *testing.T) {
+	// Buckets of 2s: [0,2) holds samples 1-2, [2,4) samples 3-4 and [6,8) sample 5;
+	// nothing falls in [4,6), so that bucket is skipped
+	buckets := DownsampleStats(downsampleTestStats(0, 1, 2, 3.5, 7), 2)
+
+	want := []struct {
+		start, end float64
+		samples    int
+		count      MetricSummary
+		preference MetricSummary
+	}{
+		{0, 2, 2, MetricSummary{Mean: 1.5, Min: 1, Max: 2}, MetricSummary{Mean: 15, Min: 10, Max: 20}},
+		{2, 4, 2, MetricSummary{Mean: 3.5, Min: 3, Max: 4}, MetricSummary{Mean: 35, Min: 30, Max: 40}},
+		{6, 8, 1, MetricSummary{Mean: 5, Min: 5, Max: 5}, MetricSummary{Mean: 50, Min: 50, Max: 50}},
+	}
+	if len(buckets) != len(want) {
+		t.Fatalf("Got %d buckets; want %d: %+v", len(buckets), len(want), buckets)
+	}
+	for i, w := range want {
+		got := buckets[i]
+		if got.Start != w.start || got.End != w.end || got.Samples != w.samples {
+			t.Errorf("Bucket %d spans [%v, %v) with %d samples; want [%v, %v) with %d",
+				i, got.Start, got.End, got.Samples, w.start, w.end, w.samples)
+		}
+		if got.Metrics["OrganismCount"] != w.count {
+			t.Errorf("Bucket %d OrganismCount = %+v; want %+v", i, got.Metrics["OrganismCount"], w.count)
+		}
+		if got.Metrics["AveragePreference"] != w.preference {
+			t.Errorf("Bucket %d AveragePreference = %+v; want %+v", i, got.Metrics["AveragePreference"], w.preference)
+		}
+	}
+
+	if buckets := DownsampleStats(downsampleTestStats(0, 1), 0); buckets != nil {
+		t.Errorf("Expected no buckets without a bucket length, got %+v", buckets)
+	}
+}
+
+func TestDownsamplingStatsSink(t *testing.T) {
+	dir := t.TempDir()
+	stats := downsampleTestStats(0, 1, 2, 3.5, 7)
+
+	csvPath := filepath.Join(dir, "stats.csv")
+	csvWriter, err := NewCSVBucketWriter(csvPath)
+	if err != nil {
+		t.Fatalf("Failed to open CSV writer: %v", err)
+	}
+	jsonPath := filepath.Join(dir, "stats.json")
+	jsonWriter, err := NewJSONBucketWriter(jsonPath)
+	if err != nil {
+		t.Fatalf("Failed to open JSON writer: %v", err)
+	}
+	writeToSink(t, NewDownsamplingStatsSink(2, csvWriter, jsonWriter), stats)
+
+	// The streamed JSON matches the batch result
+	data, err := json.MarshalIndent(DownsampleStats(stats, 2), "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal buckets: %v", err)
+	}
+	if got := readFile(t, jsonPath); got != string(data) {
+		t.Errorf("Sink output:\n%s\nwant batch output:\n%s", got, data)
+	}
+
+	// One header row plus a row per bucket, each with three columns per metric
+	rows := strings.Split(strings.TrimSpace(readFile(t, csvPath)), "\n")
+	if len(rows) != 4 {
+		t.Fatalf("Got %d CSV rows; want a header and 3 buckets:\n%s", len(rows), strings.Join(rows, "\n"))
+	}
+	if columns := len(strings.Split(rows[0], ",")); columns != 3+3*len(statsMetrics) {
+		t.Errorf("Got %d CSV columns; want %d", columns, 3+3*len(statsMetrics))
+	}
+	if !strings.HasPrefix(rows[1], "0.00,2.00,2,1.50,1.00,2.00,15.00,10.00,20.00,") {
+		t.Errorf("First bucket row = %q", rows[1])
+	}
+}
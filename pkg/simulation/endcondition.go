@@ -0,0 +1,234 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+// End condition metrics
+const (
+	EndMetricGeneration       = "generation"       // Highest generation among living organisms
+	EndMetricPopulation       = "population"       // Number of living organisms
+	EndMetricLineageShare     = "lineageShare"     // Largest lineage's share of the population (0-1)
+	EndMetricPreferenceChange = "preferenceChange" // Change in average preference over the last PreferenceWindow seconds
+	EndMetricTime             = "time"             // Simulation time in seconds
+	EndMetricSteps            = "steps"            // Steps taken since the start or last reset
+)
+
+// PreferenceWindow is the span of simulation time, in seconds, over which
+// preferenceChange measures how far the average preference has moved
+const PreferenceWindow = 10.0
+
+// endComparison is a single "<metric> <operator> <value>" test
+type endComparison struct {
+	metric   string
+	operator string
+	value    float64
+}
+
+// preferenceSample is the average preference at a moment of simulation time
+type preferenceSample struct {
+	time       float64
+	preference float64
+}
+
+// EndCondition is a stopping rule for a run, parsed from an expression such as
+// "generation >= 50" or "lineageShare > 0.8 or preferenceChange < 0.1".
+// Comparisons are joined with "and" and "or", and "and" binds tighter.
+type EndCondition struct {
+	Expression string
+	clauses    [][]endComparison // Met when every comparison of any one clause holds
+
+	// Average preference over the last PreferenceWindow seconds, oldest first
+	preferenceHistory []preferenceSample
+}
+
+// ParseEndCondition parses an end condition expression
+func ParseEndCondition(expression string) (*EndCondition, error) {
+	condition := &EndCondition{Expression: expression}
+
+	fields := strings.Fields(expression)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("end condition is empty")
+	}
+
+	clause := []endComparison{}
+	for len(fields) > 0 {
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("end condition %q: expected \"<metric> <operator> <value>\" at %q",
+				expression, strings.Join(fields, " "))
+		}
+		comparison, err := parseEndComparison(fields[0], fields[1], fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("end condition %q: %w", expression, err)
+		}
+		clause = append(clause, comparison)
+		fields = fields[3:]
+
+		if len(fields) == 0 {
+			break
+		}
+		switch strings.ToLower(fields[0]) {
+		case "and", "&&":
+		case "or", "||":
+			condition.clauses = append(condition.clauses, clause)
+			clause = []endComparison{}
+		default:
+			return nil, fmt.Errorf("end condition %q: expected \"and\" or \"or\", got %q", expression, fields[0])
+		}
+		fields = fields[1:]
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("end condition %q ends with a dangling operator", expression)
+		}
+	}
+	condition.clauses = append(condition.clauses, clause)
+
+	return condition, nil
+}
+
+// parseEndComparison checks and converts the three parts of a comparison
+func parseEndComparison(metric, operator, value string) (endComparison, error) {
+	switch metric {
+	case EndMetricGeneration, EndMetricPopulation, EndMetricLineageShare,
+		EndMetricPreferenceChange, EndMetricTime, EndMetricSteps:
+	default:
+		return endComparison{}, fmt.Errorf("unknown metric %q", metric)
+	}
+
+	switch operator {
+	case ">=", ">", "<=", "<", "==", "!=":
+	default:
+		return endComparison{}, fmt.Errorf("unknown operator %q", operator)
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return endComparison{}, fmt.Errorf("invalid value %q for %s", value, metric)
+	}
+
+	return endComparison{metric: metric, operator: operator, value: number}, nil
+}
+
+// holds reports whether the comparison is true for the measured value
+func (c endComparison) holds(measured float64) bool {
+	switch c.operator {
+	case ">=":
+		return measured >= c.value
+	case ">":
+		return measured > c.value
+	case "<=":
+		return measured <= c.value
+	case "<":
+		return measured < c.value
+	case "==":
+		return measured == c.value
+	default:
+		return measured != c.value
+	}
+}
+
+// Met evaluates the condition against the simulator's current state. It should be
+// called after every step, since preferenceChange is measured from the history of
+// these calls.
+func (c *EndCondition) Met(s *Simulator) bool {
+	organisms := s.World.GetOrganisms()
+	c.recordPreference(s.Time, organisms)
+
+	for _, clause := range c.clauses {
+		met := true
+		for _, comparison := range clause {
+			if !comparison.holds(c.measure(comparison.metric, s, organisms)) {
+				met = false
+				break
+			}
+		}
+		if met {
+			return true
+		}
+	}
+	return false
+}
+
+// measure returns the current value of metric
+func (c *EndCondition) measure(metric string, s *Simulator, organisms []types.Organism) float64 {
+	switch metric {
+	case EndMetricGeneration:
+		generation := 0
+		for _, org := range organisms {
+			generation = max(generation, org.Generation)
+		}
+		return float64(generation)
+	case EndMetricPopulation:
+		return float64(len(organisms))
+	case EndMetricLineageShare:
+		if top := world.TopLineages(organisms, 1); len(top) > 0 {
+			return top[0].Share
+		}
+		return 0
+	case EndMetricPreferenceChange:
+		return c.preferenceChange()
+	case EndMetricTime:
+		return s.Time
+	default:
+		return float64(s.StepCount)
+	}
+}
+
+// recordPreference adds the current average preference to the history and drops
+// samples older than the window, keeping one at or beyond its start
+func (c *EndCondition) recordPreference(now float64, organisms []types.Organism) {
+	if len(organisms) == 0 {
+		return
+	}
+	total := 0.0
+	for _, org := range organisms {
+		total += org.ChemPreference
+	}
+	c.preferenceHistory = append(c.preferenceHistory, preferenceSample{time: now, preference: total / float64(len(organisms))})
+
+	for len(c.preferenceHistory) > 1 && now-c.preferenceHistory[1].time >= PreferenceWindow {
+		c.preferenceHistory = c.preferenceHistory[1:]
+	}
+}
+
+// preferenceChange returns how far the average preference has moved over the last
+// PreferenceWindow seconds, or +Inf until a full window has been observed
+func (c *EndCondition) preferenceChange() float64 {
+	if len(c.preferenceHistory) == 0 {
+		return math.Inf(1)
+	}
+	oldest, newest := c.preferenceHistory[0], c.preferenceHistory[len(c.preferenceHistory)-1]
+	if newest.time-oldest.time < PreferenceWindow {
+		return math.Inf(1)
+	}
+	return math.Abs(newest.preference - oldest.preference)
+}
+
+// Reset forgets the observed history, for a fresh run
+func (c *EndCondition) Reset() {
+	c.preferenceHistory = nil
+}
+
+// EndConditionMet reports whether the simulator's end condition is met. A nil
+// condition is never met.
+func (s *Simulator) EndConditionMet() bool {
+	return s.EndCondition != nil && s.EndCondition.Met(s)
+}
+
+// Run advances the simulation until maxSteps steps have been taken since the start
+// or last reset, or until the end condition is met. Returns true if the end
+// condition stopped the run.
+func (s *Simulator) Run(maxSteps int64) bool {
+	for s.StepCount < maxSteps {
+		s.Step()
+		if s.EndConditionMet() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+// newFounderSimulator returns a simulator whose world holds a single founder that
+// pays no energy costs, so it reproduces as soon as its cooldown allows
+func newFounderSimulator() *Simulator {
+	cfg := createTestConfig()
+	cfg.Organism.Count = 0
+	cfg.Chemical.Count = 0
+	w := world.NewWorld(cfg)
+
+	founder := types.NewOrganism(types.Point{X: 50, Y: 50}, 0, 30.0, 0, types.DefaultSensorAngles())
+	founder.Energy = founder.EnergyCapacity
+	founder.MetabolicRate, founder.MovementCost, founder.SensingCost = 0, 0, 0
+	w.AddOrganism(founder)
+
+	return NewSimulator(w, cfg)
+}
+
+// highestGeneration returns the highest generation among the simulator's organisms
+func highestGeneration(sim *Simulator) int {
+	generation := 0
+	for _, org := range sim.World.GetOrganisms() {
+		generation = max(generation, org.Generation)
+	}
+	return generation
+}
+
+func TestGenerationEndConditionStopsRun(t *testing.T) {
+	// Find the step on which the second generation is born
+	reference := newFounderSimulator()
+	for highestGeneration(reference) < 2 {
+		if reference.StepCount > 10000 {
+			t.Fatal("Founder never reproduced")
+		}
+		reference.Step()
+	}
+	if reference.StepCount < 2 {
+		t.Fatalf("Second generation born at step %d; want a later birth so stopping early is detectable", reference.StepCount)
+	}
+
+	sim := newFounderSimulator()
+	condition, err := ParseEndCondition("generation >= 2")
+	if err != nil {
+		t.Fatalf("ParseEndCondition() error = %v", err)
+	}
+	sim.EndCondition = condition
+
+	if !sim.Run(100000) {
+		t.Fatalf("Run() stopped after %d steps without meeting the end condition", sim.StepCount)
+	}
+	if sim.StepCount != reference.StepCount {
+		t.Errorf("Run() stopped at step %d; want step %d, when the second generation is born", sim.StepCount, reference.StepCount)
+	}
+	if generation := highestGeneration(sim); generation != 2 {
+		t.Errorf("Highest generation when stopped = %d; want 2", generation)
+	}
+
+	// Without a condition the run goes the distance
+	free := newFounderSimulator()
+	if free.Run(reference.StepCount+10) || free.StepCount != reference.StepCount+10 {
+		t.Errorf("Run() without an end condition stopped at step %d; want %d", free.StepCount, reference.StepCount+10)
+	}
+}
+
+func TestParseEndCondition(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    bool
+	}{
+		{"generation >= 50", false},
+		{"lineageShare > 0.8 or preferenceChange < 0.1", false},
+		{"population < 10 and time > 60 || steps >= 1000", false},
+		{"", true},
+		{"generation >=", true},
+		{"age > 5", true},
+		{"generation => 5", true},
+		{"generation >= five", true},
+		{"generation >= 5 but population < 3", true},
+		{"generation >= 5 and", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := ParseEndCondition(tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseEndCondition(%q) error = %v; want error %v", tt.expression, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEndConditionOperators(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Organism.Count = 3
+	sim := NewSimulator(world.NewWorld(cfg), cfg)
+	sim.Time = 30
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{"population == 3", true},
+		{"population != 3", false},
+		{"time > 30", false},
+		{"time >= 30", true},
+		{"population < 3 or time <= 30", true},
+		{"population < 3 and time <= 30", false},
+		{"population > 1 and lineageShare <= 0.5 or steps > 5", true}, // "and" binds tighter
+		{"preferenceChange < 1000", false},                            // No full window observed yet
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			condition, err := ParseEndCondition(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := condition.Met(sim); got != tt.want {
+				t.Errorf("Met() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreferenceChangeNeedsFullWindow(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Organism.Count = 5
+	cfg.Control.EnergyEnabled = false
+	sim := NewSimulator(world.NewWorld(cfg), cfg)
+
+	condition, err := ParseEndCondition("preferenceChange < 0.001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without births or deaths the average preference never moves, so the condition
+	// holds as soon as a full window has been observed
+	for sim.Time < PreferenceWindow-sim.TimeStep {
+		sim.Step()
+		if condition.Met(sim) {
+			t.Fatalf("Met() at %.2fs; want false before a full %vs window", sim.Time, PreferenceWindow)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		sim.Step()
+		condition.Met(sim)
+	}
+	if change := condition.preferenceChange(); math.IsInf(change, 1) || change >= 0.001 {
+		t.Errorf("preferenceChange() = %v; want a small finite change once the window is full", change)
+	}
+}
@@ -0,0 +1,119 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// EventKind identifies what happened in a logged event
+type EventKind string
+
+const (
+	EventReproduction EventKind = "reproduction"
+	EventDeath        EventKind = "death"
+)
+
+// Event is a single reproduction or death. For reproductions, OrganismID is the
+// offspring and ParentID its parent; for deaths, Age and Cause describe the death.
+type Event struct {
+	Kind       EventKind
+	Time       float64
+	OrganismID int64
+	ParentID   int64
+	Age        float64
+	Position   types.Point
+	Cause      types.DeathCause
+}
+
+// Events returns the reproduction and death events recorded so far, in order
+func (s *Simulator) Events() []Event {
+	events := make([]Event, len(s.events))
+	copy(events, s.events)
+	return events
+}
+
+// recordDeaths logs every organism in organisms that has died, with its cause.
+// Call it just before the dead are removed from the world.
+func (s *Simulator) recordDeaths(organisms []types.Organism) {
+	for _, org := range organisms {
+		cause := org.CauseOfDeath()
+		if cause == "" {
+			continue
+		}
+		s.events = append(s.events, Event{
+			Kind:       EventDeath,
+			Time:       s.Time,
+			OrganismID: org.ID,
+			ParentID:   org.ParentID,
+			Age:        s.Time - s.birthTimes[org.ID],
+			Position:   org.Position,
+			Cause:      cause,
+		})
+		delete(s.birthTimes, org.ID)
+	}
+}
+
+// recordBirths logs every organism in after whose ID is not in before
+func (s *Simulator) recordBirths(before, after []types.Organism) {
+	existing := make(map[int64]bool, len(before))
+	for _, org := range before {
+		existing[org.ID] = true
+	}
+
+	for _, org := range after {
+		if existing[org.ID] {
+			continue
+		}
+		s.events = append(s.events, Event{
+			Kind:       EventReproduction,
+			Time:       s.Time,
+			OrganismID: org.ID,
+			ParentID:   org.ParentID,
+			Position:   org.Position,
+		})
+		s.birthTimes[org.ID] = s.Time
+	}
+}
+
+// ExportEventsCSV writes the recorded reproduction and death events to a CSV file.
+// Organisms present from the start are treated as born at time zero when computing age.
+func (s *Simulator) ExportEventsCSV(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := []string{"Time", "Event", "OrganismID", "ParentID", "Age", "X", "Y", "Cause"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, event := range s.events {
+		age := ""
+		if event.Kind == EventDeath {
+			age = fmt.Sprintf("%.2f", event.Age)
+		}
+		row := []string{
+			fmt.Sprintf("%.2f", event.Time),
+			string(event.Kind),
+			fmt.Sprintf("%d", event.OrganismID),
+			fmt.Sprintf("%d", event.ParentID),
+			age,
+			fmt.Sprintf("%.2f", event.Position.X),
+			fmt.Sprintf("%.2f", event.Position.Y),
+			string(event.Cause),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return writer.Error()
+}
@@ -0,0 +1,75 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+func TestExportEventsCSV(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Organism.Count = 0
+	cfg.Chemical.Count = 0
+	w := world.NewWorld(cfg)
+
+	// One organism ready to reproduce and one about to starve
+	parent := types.NewOrganism(types.Point{X: 25, Y: 50}, 0, 30.0, 1.0, types.DefaultSensorAngles())
+	parent.Energy = parent.EnergyCapacity
+	parent.TimeSinceReproduction = types.ReproductionCooldown
+	w.AddOrganism(parent)
+
+	doomed := types.NewOrganism(types.Point{X: 75, Y: 50}, 0, 30.0, 1.0, types.DefaultSensorAngles())
+	doomed.Energy = 1e-9
+	w.AddOrganism(doomed)
+
+	sim := NewSimulator(w, cfg)
+	sim.RecordEvents = true
+	sim.Step()
+
+	var births, deaths []Event
+	for _, event := range sim.Events() {
+		switch event.Kind {
+		case EventReproduction:
+			births = append(births, event)
+		case EventDeath:
+			deaths = append(deaths, event)
+		}
+	}
+	if len(births) != 1 || births[0].ParentID != parent.ID {
+		t.Fatalf("Births = %+v; want one offspring of organism %d", births, parent.ID)
+	}
+	if len(deaths) != 1 || deaths[0].OrganismID != doomed.ID || deaths[0].Cause != types.DeathCauseStarvation {
+		t.Fatalf("Deaths = %+v; want organism %d to starve", deaths, doomed.ID)
+	}
+
+	path := filepath.Join(t.TempDir(), "events.csv")
+	if err := sim.ExportEventsCSV(path); err != nil {
+		t.Fatalf("Failed to export events: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open events file: %v", err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read events file: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Events file has %d rows; want a header and 2 events", len(rows))
+	}
+
+	kinds := map[string]bool{}
+	for _, row := range rows[1:] {
+		kinds[row[1]] = true
+	}
+	if !kinds[string(EventReproduction)] || !kinds[string(EventDeath)] {
+		t.Errorf("Event rows = %v; want a reproduction and a death", rows[1:])
+	}
+}
@@ -0,0 +1,196 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+// DefaultMutantFraction is the share of founders given the mutant trait value
+// when an invasion doesn't specify one
+const DefaultMutantFraction = 0.1
+
+// invasionTraits are the traits an invasion can vary, named as in the trait
+// correlation matrix, and how to set them
+var invasionTraits = map[string]func(*types.Organism, float64){
+	"Preference":       func(o *types.Organism, v float64) { o.ChemPreference = v },
+	"Speed":            func(o *types.Organism, v float64) { o.Speed = v },
+	"EnergyEfficiency": func(o *types.Organism, v float64) { o.EnergyEfficiency = v },
+	"MetabolicRate":    func(o *types.Organism, v float64) { o.MetabolicRate = v },
+}
+
+// InvasionSetup describes an invasion experiment: a resident population with one
+// trait value, seeded with a few mutants carrying another
+type InvasionSetup struct {
+	Trait          string
+	ResidentValue  float64
+	MutantValue    float64
+	MutantFraction float64         // Share of founders that are mutants; 0 uses DefaultMutantFraction
+	Start          *world.Scenario // Optional starting world; nil populates one from the config
+}
+
+// InvasionSample is the mutants' share of the population at a moment of the run
+type InvasionSample struct {
+	Time        float64
+	Population  int
+	MutantShare float64
+}
+
+// Invasion tracks whether mutant founders' lineages gain ground in a resident
+// population, the invasion fitness of the mutant trait value
+type Invasion struct {
+	Setup       InvasionSetup
+	MutantRoots map[int64]bool // Root IDs of the mutant founders
+	Samples     []InvasionSample
+}
+
+// ParseInvasion reads a "trait,resident,mutant[,fraction]" invasion description
+func ParseInvasion(spec string) (InvasionSetup, error) {
+	fields := strings.Split(spec, ",")
+	if len(fields) != 3 && len(fields) != 4 {
+		return InvasionSetup{}, fmt.Errorf("invasion needs trait,resident,mutant[,fraction], got %q", spec)
+	}
+
+	setup := InvasionSetup{Trait: strings.TrimSpace(fields[0])}
+	values := []*float64{&setup.ResidentValue, &setup.MutantValue, &setup.MutantFraction}
+	for i, field := range fields[1:] {
+		value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return InvasionSetup{}, fmt.Errorf("invalid number %q in invasion %q", field, spec)
+		}
+		*values[i] = value
+	}
+	return setup, setup.validate()
+}
+
+// validate checks that the trait is known and the mutant fraction leaves room
+// for both residents and mutants
+func (s InvasionSetup) validate() error {
+	if _, ok := invasionTraits[s.Trait]; !ok {
+		return fmt.Errorf("unknown invasion trait %q, want one of %s", s.Trait, strings.Join(InvasionTraits(), ", "))
+	}
+	if s.MutantFraction < 0 || s.MutantFraction >= 1 {
+		return fmt.Errorf("mutant fraction must be between 0 and 1, got %v", s.MutantFraction)
+	}
+	return nil
+}
+
+// InvasionTraits returns the names of the traits an invasion can vary, sorted
+func InvasionTraits() []string {
+	names := make([]string, 0, len(invasionTraits))
+	for name := range invasionTraits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewInvasion gives every organism in the world the resident trait value, then
+// makes an evenly spread MutantFraction of them, at least one, mutants
+func NewInvasion(w *world.World, setup InvasionSetup) (*Invasion, error) {
+	if err := setup.validate(); err != nil {
+		return nil, err
+	}
+	if setup.MutantFraction == 0 {
+		setup.MutantFraction = DefaultMutantFraction
+	}
+
+	organisms := w.GetOrganisms()
+	if len(organisms) < 2 {
+		return nil, fmt.Errorf("invasion needs at least 2 organisms, got %d", len(organisms))
+	}
+
+	// Spread the mutants through the population rather than taking the first few
+	mutants := max(1, int(math.Round(setup.MutantFraction*float64(len(organisms)))))
+	isMutant := make(map[int]bool, mutants)
+	for i := 0; i < mutants; i++ {
+		isMutant[i*len(organisms)/mutants] = true
+	}
+
+	invasion := &Invasion{Setup: setup, MutantRoots: make(map[int64]bool)}
+	setTrait := invasionTraits[setup.Trait]
+	for i := range organisms {
+		if isMutant[i] {
+			setTrait(&organisms[i], setup.MutantValue)
+			invasion.MutantRoots[organisms[i].RootAncestor()] = true
+		} else {
+			setTrait(&organisms[i], setup.ResidentValue)
+		}
+	}
+	w.UpdateOrganisms(organisms)
+
+	return invasion, nil
+}
+
+// MutantShare returns the share of the organisms descended from mutant founders
+func (inv *Invasion) MutantShare(organisms []types.Organism) float64 {
+	if len(organisms) == 0 {
+		return 0
+	}
+
+	mutants := 0
+	for _, org := range organisms {
+		if inv.MutantRoots[org.RootAncestor()] {
+			mutants++
+		}
+	}
+	return float64(mutants) / float64(len(organisms))
+}
+
+// Record samples the mutants' current share of the simulator's population
+func (inv *Invasion) Record(s *Simulator) InvasionSample {
+	organisms := s.World.GetOrganisms()
+	sample := InvasionSample{
+		Time:        s.Time,
+		Population:  len(organisms),
+		MutantShare: inv.MutantShare(organisms),
+	}
+	inv.Samples = append(inv.Samples, sample)
+	return sample
+}
+
+// ShareChange returns how much the mutants' share grew between the first and last
+// samples. It's positive when the mutant invades and negative when it's driven out.
+func (inv *Invasion) ShareChange() float64 {
+	if len(inv.Samples) == 0 {
+		return 0
+	}
+	return inv.Samples[len(inv.Samples)-1].MutantShare - inv.Samples[0].MutantShare
+}
+
+// Invades reports whether the mutants' share grew over the run
+func (inv *Invasion) Invades() bool {
+	return inv.ShareChange() > 0
+}
+
+// RunInvasion runs a headless invasion experiment for steps steps, sampling the
+// mutants' share at the start, every sampleInterval steps, and at the end
+func RunInvasion(cfg config.SimulationConfig, setup InvasionSetup, steps, sampleInterval int64) (*Invasion, error) {
+	w := world.NewWorld(cfg)
+	if setup.Start != nil {
+		w.ApplyScenario(*setup.Start)
+	}
+	simulator := NewSimulator(w, cfg)
+
+	invasion, err := NewInvasion(w, setup)
+	if err != nil {
+		return nil, err
+	}
+
+	invasion.Record(simulator)
+	for simulator.StepCount < steps {
+		simulator.Step()
+		if sampleInterval > 0 && simulator.StepCount%sampleInterval == 0 && simulator.StepCount < steps {
+			invasion.Record(simulator)
+		}
+	}
+	invasion.Record(simulator)
+
+	return invasion, nil
+}
@@ -0,0 +1,90 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+func TestParseInvasion(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    InvasionSetup
+		wantErr bool
+	}{
+		{"MetabolicRate,0.5,0.05", InvasionSetup{Trait: "MetabolicRate", ResidentValue: 0.5, MutantValue: 0.05}, false},
+		{" Preference , 30, 45, 0.2 ", InvasionSetup{Trait: "Preference", ResidentValue: 30, MutantValue: 45, MutantFraction: 0.2}, false},
+		{"Preference,30", InvasionSetup{}, true},
+		{"Wingspan,1,2", InvasionSetup{}, true},
+		{"Speed,1,fast", InvasionSetup{}, true},
+		{"Speed,1,2,1", InvasionSetup{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseInvasion(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseInvasion(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseInvasion(%q) = %+v; want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewInvasionSeedsMutants(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Organism.Count = 50
+	cfg.RandomSeed = 3
+	w := world.NewWorld(cfg)
+
+	invasion, err := NewInvasion(w, InvasionSetup{Trait: "Speed", ResidentValue: 1, MutantValue: 3, MutantFraction: 0.2})
+	if err != nil {
+		t.Fatalf("NewInvasion failed: %v", err)
+	}
+
+	residents, mutants := 0, 0
+	for _, org := range w.GetOrganisms() {
+		switch {
+		case org.Speed == 3 && invasion.MutantRoots[org.RootAncestor()]:
+			mutants++
+		case org.Speed == 1 && !invasion.MutantRoots[org.RootAncestor()]:
+			residents++
+		default:
+			t.Errorf("Organism %d has speed %v; want it to match its group", org.ID, org.Speed)
+		}
+	}
+	if mutants != 10 || residents != 40 {
+		t.Errorf("Seeded %d mutants and %d residents; want 10 and 40", mutants, residents)
+	}
+	if share := invasion.MutantShare(w.GetOrganisms()); share != 0.2 {
+		t.Errorf("MutantShare = %v; want 0.2", share)
+	}
+}
+
+func TestFitterMutantInvades(t *testing.T) {
+	// A mutant that burns far less energy just existing, and is otherwise the same
+	for seed := int64(1); seed <= 3; seed++ {
+		cfg := config.DefaultConfig()
+		cfg.RandomSeed = seed
+
+		setup := InvasionSetup{Trait: "MetabolicRate", ResidentValue: 0.5, MutantValue: 0.05, MutantFraction: 0.2}
+		invasion, err := RunInvasion(cfg, setup, 1800, 600)
+		if err != nil {
+			t.Fatalf("RunInvasion failed: %v", err)
+		}
+
+		if !invasion.Invades() {
+			t.Errorf("Seed %d: fitter mutant's share went from %v to %v; want it to grow", seed,
+				invasion.Samples[0].MutantShare, invasion.Samples[len(invasion.Samples)-1].MutantShare)
+		}
+		for i := 1; i < len(invasion.Samples); i++ {
+			if invasion.Samples[i].MutantShare <= invasion.Samples[i-1].MutantShare {
+				t.Errorf("Seed %d: mutant share fell from %v to %v at %.0fs", seed,
+					invasion.Samples[i-1].MutantShare, invasion.Samples[i].MutantShare, invasion.Samples[i].Time)
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// DefaultOccupancyCellSize is the side of an occupancy grid cell in world units
+const DefaultOccupancyCellSize = 10.0
+
+// OccupancyGrid accumulates how much organism-time has been spent in each cell of
+// the world, showing which parts of the habitat get used
+type OccupancyGrid struct {
+	Bounds   types.Rect
+	CellSize float64
+	Counts   [][]float64 // Organism-seconds spent in each cell, indexed [row][column]
+}
+
+// NewOccupancyGrid creates an empty occupancy grid covering bounds
+func NewOccupancyGrid(bounds types.Rect, cellSize float64) *OccupancyGrid {
+	if cellSize <= 0 {
+		cellSize = DefaultOccupancyCellSize
+	}
+
+	columns := max(int(math.Ceil(bounds.Width/cellSize)), 1)
+	rows := max(int(math.Ceil(bounds.Height/cellSize)), 1)
+	counts := make([][]float64, rows)
+	for row := range counts {
+		counts[row] = make([]float64, columns)
+	}
+
+	return &OccupancyGrid{Bounds: bounds, CellSize: cellSize, Counts: counts}
+}
+
+// Cell returns the row and column of the cell containing point, clamped to the grid
+func (g *OccupancyGrid) Cell(point types.Point) (int, int) {
+	row := int((point.Y - g.Bounds.Min.Y) / g.CellSize)
+	column := int((point.X - g.Bounds.Min.X) / g.CellSize)
+	row = min(max(row, 0), len(g.Counts)-1)
+	column = min(max(column, 0), len(g.Counts[0])-1)
+	return row, column
+}
+
+// Record adds deltaTime to the cell of every organism
+func (g *OccupancyGrid) Record(organisms []types.Organism, deltaTime float64) {
+	for _, org := range organisms {
+		row, column := g.Cell(org.Position)
+		g.Counts[row][column] += deltaTime
+	}
+}
+
+// Export writes the grid to filename, as a PNG image if the name ends in .png and
+// as CSV otherwise
+func (g *OccupancyGrid) Export(filename string) error {
+	if strings.EqualFold(filepath.Ext(filename), ".png") {
+		return g.ExportPNG(filename)
+	}
+	return g.ExportCSV(filename)
+}
+
+// ExportCSV writes the grid as CSV, one row of cells per line
+func (g *OccupancyGrid) ExportCSV(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	for _, counts := range g.Counts {
+		row := make([]string, len(counts))
+		for column, count := range counts {
+			row[column] = fmt.Sprintf("%.2f", count)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportPNG writes the grid as a grayscale image with one pixel per cell, the
+// most used cell in white
+func (g *OccupancyGrid) ExportPNG(filename string) error {
+	peak := 0.0
+	for _, counts := range g.Counts {
+		for _, count := range counts {
+			peak = math.Max(peak, count)
+		}
+	}
+
+	img := image.NewGray(image.Rect(0, 0, len(g.Counts[0]), len(g.Counts)))
+	if peak > 0 {
+		for row, counts := range g.Counts {
+			for column, count := range counts {
+				img.SetGray(column, row, color.Gray{Y: uint8(math.Round(count / peak * 255))})
+			}
+		}
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,86 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+func TestOccupancyExportFindsStationaryOrganism(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Organism.Count = 0
+	cfg.Chemical.Count = 0
+	cfg.Control.EnergyEnabled = false
+	w := world.NewWorld(cfg)
+
+	// One organism that never moves, and one wandering across the world
+	sitter := types.NewOrganism(types.Point{X: 73, Y: 27}, 0, 30.0, 0, types.DefaultSensorAngles())
+	wanderer := types.NewOrganism(types.Point{X: 5, Y: 50}, 0, 30.0, 1.0, types.DefaultSensorAngles())
+	w.AddOrganism(sitter)
+	w.AddOrganism(wanderer)
+
+	sim := NewSimulator(w, cfg)
+	sim.Occupancy = NewOccupancyGrid(w.GetBounds(), 10.0)
+	for i := 0; i < 100; i++ {
+		sim.Step()
+	}
+
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "heatmap.csv")
+	if err := sim.Occupancy.Export(csvPath); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	file, err := os.Open(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hottestRow, hottestColumn, hottest := -1, -1, -1.0
+	for r, row := range rows {
+		for c, cell := range row {
+			value, err := strconv.ParseFloat(cell, 64)
+			if err != nil {
+				t.Fatalf("Cell (%d, %d) = %q; want a number", r, c, cell)
+			}
+			if value > hottest {
+				hottestRow, hottestColumn, hottest = r, c, value
+			}
+		}
+	}
+
+	wantRow, wantColumn := sim.Occupancy.Cell(sitter.Position)
+	if hottestRow != wantRow || hottestColumn != wantColumn {
+		t.Errorf("Hottest cell = (%d, %d); want the stationary organism's (%d, %d)",
+			hottestRow, hottestColumn, wantRow, wantColumn)
+	}
+
+	// The PNG export brightens the same cell to white
+	pngPath := filepath.Join(dir, "heatmap.png")
+	if err := sim.Occupancy.Export(pngPath); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	imageFile, err := os.Open(pngPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer imageFile.Close()
+	img, err := png.Decode(imageFile)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if gray, _, _, _ := img.At(wantColumn, wantRow).RGBA(); gray != 0xffff {
+		t.Errorf("Stationary organism's pixel = %#x; want white", gray)
+	}
+}
@@ -0,0 +1,52 @@
+package simulation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// progressBarWidth is the number of characters in the bar itself
+const progressBarWidth = 30
+
+// EstimateRemaining estimates the time left in a run from the time elapsed so far and
+// the fraction of the run completed, assuming the step rate stays constant.
+// Returns 0 before any progress has been made or once the run is complete.
+func EstimateRemaining(elapsed time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 || fraction >= 1 {
+		return 0
+	}
+
+	total := float64(elapsed) / fraction
+	return time.Duration(total - float64(elapsed))
+}
+
+// FormatProgress renders a single-line progress bar with percent complete, step rate
+// and estimated time remaining, e.g. "[=====>    ]  52.0% | 812 steps/s | ETA 1m3s"
+func FormatProgress(step, totalSteps int, elapsed time.Duration) string {
+	fraction := 0.0
+	if totalSteps > 0 {
+		fraction = float64(step) / float64(totalSteps)
+	}
+
+	filled := int(fraction * progressBarWidth)
+	if filled > progressBarWidth {
+		filled = progressBarWidth
+	}
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+
+	stepsPerSecond := 0.0
+	if elapsed > 0 {
+		stepsPerSecond = float64(step) / elapsed.Seconds()
+	}
+
+	eta := "--"
+	if step > 0 {
+		eta = EstimateRemaining(elapsed, fraction).Round(time.Second).String()
+	}
+
+	return fmt.Sprintf("[%s] %5.1f%% | %.0f steps/s | ETA %s", bar, fraction*100, stepsPerSecond, eta)
+}
@@ -0,0 +1,45 @@
+package simulation
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEstimateRemaining(t *testing.T) {
+	tests := []struct {
+		name     string
+		elapsed  time.Duration
+		fraction float64
+		want     time.Duration
+	}{
+		{"quarter done", 10 * time.Second, 0.25, 30 * time.Second},
+		{"half done", time.Minute, 0.5, time.Minute},
+		{"almost done", 99 * time.Second, 0.99, time.Second},
+		{"finished", time.Minute, 1.0, 0},
+		{"not started", 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateRemaining(tt.elapsed, tt.fraction)
+			if diff := got - tt.want; diff < -time.Millisecond || diff > time.Millisecond {
+				t.Errorf("EstimateRemaining(%v, %v) = %v; want %v", tt.elapsed, tt.fraction, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatProgress(t *testing.T) {
+	line := FormatProgress(500, 1000, 10*time.Second)
+
+	for _, want := range []string{"50.0%", "50 steps/s", "ETA 10s"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("FormatProgress() = %q; want it to contain %q", line, want)
+		}
+	}
+
+	if line := FormatProgress(0, 1000, 0); !strings.Contains(line, "ETA --") {
+		t.Errorf("FormatProgress() at start = %q; want an unknown ETA", line)
+	}
+}
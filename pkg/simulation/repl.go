@@ -0,0 +1,184 @@
+package simulation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// REPLAction tells the caller what to do after a REPL command has run
+type REPLAction int
+
+const (
+	REPLStay   REPLAction = iota // Keep reading commands
+	REPLResume                   // Resume the simulation run
+	REPLQuit                     // Stop the simulation run
+)
+
+// REPL is a simple command loop for inspecting a headless simulation
+type REPL struct {
+	Simulator *Simulator
+	in        *bufio.Scanner
+	out       io.Writer
+}
+
+// NewREPL creates a REPL that reads commands from in and writes results to out
+func NewREPL(simulator *Simulator, in io.Reader, out io.Writer) *REPL {
+	return &REPL{
+		Simulator: simulator,
+		in:        bufio.NewScanner(in),
+		out:       out,
+	}
+}
+
+// Run reads and executes commands until the user resumes or quits the run.
+// Returns true if the run should stop. Reaching the end of input resumes the run.
+func (r *REPL) Run() bool {
+	for {
+		fmt.Fprintf(r.out, "[t=%.2fs] > ", r.Simulator.Time)
+		if !r.in.Scan() {
+			fmt.Fprintln(r.out)
+			return false
+		}
+
+		switch r.Execute(r.in.Text()) {
+		case REPLResume:
+			return false
+		case REPLQuit:
+			return true
+		}
+	}
+}
+
+// Execute runs a single command line and reports what the caller should do next
+func (r *REPL) Execute(line string) REPLAction {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return REPLStay
+	}
+
+	command, args := fields[0], fields[1:]
+	switch command {
+	case "stats":
+		r.printStats()
+	case "step":
+		r.step(args)
+	case "org":
+		r.printOrganism(args)
+	case "source":
+		r.printSource(args)
+	case "help":
+		r.printHelp()
+	case "continue", "c":
+		return REPLResume
+	case "quit", "q":
+		return REPLQuit
+	default:
+		fmt.Fprintf(r.out, "Unknown command %q (type \"help\" for a list)\n", command)
+	}
+
+	return REPLStay
+}
+
+// printStats prints a summary of the current simulation statistics
+func (r *REPL) printStats() {
+	stats := r.Simulator.CollectStats()
+	fmt.Fprintf(r.out, "Time: %.2fs\n", stats.Time)
+	if stats.Organisms.Empty {
+		fmt.Fprintln(r.out, "Organisms: 0 (population empty)")
+	} else {
+		fmt.Fprintf(r.out, "Organisms: %d (avg energy %.2f, %.0f%% of capacity)\n",
+			stats.Organisms.Count, stats.Organisms.AverageEnergy, stats.Organisms.EnergyRatio*100)
+		fmt.Fprintf(r.out, "Preference: mean %.2f, stddev %.2f, range [%.2f, %.2f]\n",
+			stats.Organisms.AveragePreference, stats.Organisms.PreferenceStdDev,
+			stats.Organisms.MinPreference, stats.Organisms.MaxPreference)
+	}
+	if stats.Chemicals.Empty {
+		fmt.Fprintln(r.out, "Chemical sources: 0 (no field)")
+	} else {
+		fmt.Fprintf(r.out, "Chemical sources: %d (avg concentration %.2f, max %.2f)\n",
+			stats.Chemicals.SourceCount, stats.Chemicals.AverageConcentration, stats.Chemicals.MaxConcentration)
+	}
+	totalEnergy, targetEnergy := r.Simulator.World.GetSystemEnergyInfo()
+	fmt.Fprintf(r.out, "System energy: %.2f / %.2f\n", totalEnergy, targetEnergy)
+}
+
+// step advances the simulation by the given number of steps (default 1)
+func (r *REPL) step(args []string) {
+	steps := 1
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			fmt.Fprintf(r.out, "Invalid step count %q\n", args[0])
+			return
+		}
+		steps = n
+	}
+
+	for i := 0; i < steps; i++ {
+		r.Simulator.Step()
+	}
+	fmt.Fprintf(r.out, "Advanced %d steps to t=%.2fs\n", steps, r.Simulator.Time)
+}
+
+// printOrganism prints the organism with the given ID
+func (r *REPL) printOrganism(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(r.out, "Usage: org <id>")
+		return
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		fmt.Fprintf(r.out, "Invalid organism ID %q\n", args[0])
+		return
+	}
+
+	for _, org := range r.Simulator.World.GetOrganisms() {
+		if org.ID != id {
+			continue
+		}
+		fmt.Fprintf(r.out, "Organism %d (generation %d, parent %d)\n", org.ID, org.Generation, org.ParentID)
+		fmt.Fprintf(r.out, "  Position: (%.2f, %.2f), heading %.2f rad\n", org.Position.X, org.Position.Y, org.Heading)
+		fmt.Fprintf(r.out, "  Preference: %.2f, concentration here: %.2f\n",
+			org.ChemPreference, r.Simulator.World.GetConcentrationAt(org.Position))
+		fmt.Fprintf(r.out, "  Speed: %.2f, turn bias: %.2f\n", org.Speed, org.TurnBias)
+		fmt.Fprintf(r.out, "  Energy: %.2f / %.2f (efficiency %.2f)\n", org.Energy, org.EnergyCapacity, org.EnergyEfficiency)
+		return
+	}
+
+	fmt.Fprintf(r.out, "No organism with ID %d\n", id)
+}
+
+// printSource prints the chemical source at the given index
+func (r *REPL) printSource(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(r.out, "Usage: source <index>")
+		return
+	}
+
+	index, err := strconv.Atoi(args[0])
+	sources := r.Simulator.World.GetChemicalSources()
+	if err != nil || index < 0 || index >= len(sources) {
+		fmt.Fprintf(r.out, "Invalid source index %q (have %d sources)\n", args[0], len(sources))
+		return
+	}
+
+	source := sources[index]
+	fmt.Fprintf(r.out, "Source %d at (%.2f, %.2f)\n", index, source.Position.X, source.Position.Y)
+	fmt.Fprintf(r.out, "  Strength: %.2f, decay: %.4f\n", source.Strength, source.DecayFactor)
+	fmt.Fprintf(r.out, "  Energy: %.2f / %.2f, active: %t\n", source.Energy, source.MaxEnergy, source.IsActive)
+}
+
+// printHelp lists the available commands
+func (r *REPL) printHelp() {
+	fmt.Fprintln(r.out, "Commands:")
+	fmt.Fprintln(r.out, "  stats          Show population and chemical statistics")
+	fmt.Fprintln(r.out, "  step [n]       Advance the simulation n steps (default 1)")
+	fmt.Fprintln(r.out, "  org <id>       Show the organism with the given ID")
+	fmt.Fprintln(r.out, "  source <i>     Show the chemical source at index i")
+	fmt.Fprintln(r.out, "  continue, c    Resume the run")
+	fmt.Fprintln(r.out, "  quit, q        Stop the run")
+}
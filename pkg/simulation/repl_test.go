@@ -0,0 +1,74 @@
+package simulation
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+func TestREPLExecute(t *testing.T) {
+	cfg := createTestConfig()
+	w := world.NewWorld(cfg)
+	sim := NewSimulator(w, cfg)
+
+	var out bytes.Buffer
+	repl := NewREPL(sim, strings.NewReader(""), &out)
+
+	firstID := w.GetOrganisms()[0].ID
+
+	tests := []struct {
+		name       string
+		input      string
+		wantAction REPLAction
+		wantOutput string
+	}{
+		{"stats", "stats", REPLStay, "Organisms: 10"},
+		{"organism", fmt.Sprintf("org %d", firstID), REPLStay, fmt.Sprintf("Organism %d", firstID)},
+		{"missing organism", "org -5", REPLStay, "No organism with ID -5"},
+		{"source", "source 0", REPLStay, "Source 0 at"},
+		{"step", "step 10", REPLStay, "Advanced 10 steps"},
+		{"bad step count", "step abc", REPLStay, "Invalid step count"},
+		{"bad source index", "source 9", REPLStay, "Invalid source index"},
+		{"unknown", "frobnicate", REPLStay, "Unknown command"},
+		{"blank line", "   ", REPLStay, ""},
+		{"continue", "continue", REPLResume, ""},
+		{"quit", "quit", REPLQuit, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out.Reset()
+			if got := repl.Execute(tt.input); got != tt.wantAction {
+				t.Errorf("Execute(%q) action = %v; want %v", tt.input, got, tt.wantAction)
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("Execute(%q) output = %q; want it to contain %q", tt.input, out.String(), tt.wantOutput)
+			}
+		})
+	}
+
+	// "step 10" should have advanced the simulation
+	if want := 10 * sim.TimeStep * sim.SimulationSpeed; math.Abs(sim.Time-want) > 1e-9 {
+		t.Errorf("Simulation time = %v after stepping; want %v", sim.Time, want)
+	}
+}
+
+func TestREPLRun(t *testing.T) {
+	cfg := createTestConfig()
+	sim := NewSimulator(world.NewWorld(cfg), cfg)
+
+	var out bytes.Buffer
+	if quit := NewREPL(sim, strings.NewReader("step 2\ncontinue\n"), &out).Run(); quit {
+		t.Error("Expected continue to resume the run")
+	}
+	if quit := NewREPL(sim, strings.NewReader("stats\nquit\n"), &out).Run(); !quit {
+		t.Error("Expected quit to stop the run")
+	}
+	if quit := NewREPL(sim, strings.NewReader(""), &out).Run(); quit {
+		t.Error("Expected end of input to resume the run")
+	}
+}
@@ -1,7 +1,9 @@
 package simulation
 
 import (
+	"math"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/zachbeta/evolve_sim/pkg/config"
@@ -10,42 +12,168 @@ import (
 	"github.com/zachbeta/evolve_sim/pkg/world"
 )
 
+// exactSensingWorld routes organism concentration and gradient lookups straight to
+// the chemical sources, bypassing the world's interpolated concentration grid
+type exactSensingWorld struct {
+	*world.World
+}
+
+// GetConcentrationAt returns the exact concentration at the point
+func (w exactSensingWorld) GetConcentrationAt(point types.Point) float64 {
+	return w.World.GetExactConcentrationAt(point)
+}
+
+// GetConcentrationsAt returns the exact concentration at each point
+func (w exactSensingWorld) GetConcentrationsAt(points []types.Point) []float64 {
+	return w.World.GetExactConcentrationsAt(points)
+}
+
+// GetConcentrationGradientVectorAt returns the exact gradient at the point
+func (w exactSensingWorld) GetConcentrationGradientVectorAt(point types.Point) types.Point {
+	return w.World.GetExactConcentrationGradientVectorAt(point)
+}
+
+// organismWorld is the view of the world that organisms sense and feed from
+type organismWorld interface {
+	GetConcentrationAt(types.Point) float64
+	GetConcentrationsAt([]types.Point) []float64
+	GetConcentrationGradientVectorAt(types.Point) types.Point
+	Season() (types.SeasonalCycle, float64)
+	DepleteEnergyFromSourcesWithin(types.Point, float64, float64)
+}
+
+// fixedSourcesWorld lets organisms sense a world without their feeding draining
+// its chemical sources, for runs with the energy system or feeding depletion off
+type fixedSourcesWorld struct {
+	organismWorld
+}
+
+// DepleteEnergyFromSourcesWithin leaves the sources untouched
+func (fixedSourcesWorld) DepleteEnergyFromSourcesWithin(types.Point, float64, float64) {}
+
+// accumulatingWorld records the depletion from organisms' feeding in a worker's
+// accumulator instead of draining the world's sources directly
+type accumulatingWorld struct {
+	organismWorld
+	accumulator *world.DepletionAccumulator
+}
+
+// DepleteEnergyFromSourcesWithin records the depletion in the accumulator
+func (w accumulatingWorld) DepleteEnergyFromSourcesWithin(position types.Point, radius, amount float64) {
+	w.accumulator.DepleteEnergyFromSourcesWithin(position, radius, amount)
+}
+
+// PredatorsNear returns the predators the wrapped world reports, if any
+func (w accumulatingWorld) PredatorsNear(org *types.Organism) []types.Point {
+	if pw, ok := w.organismWorld.(predatorWorld); ok {
+		return pw.PredatorsNear(org)
+	}
+	return nil
+}
+
+// predatorWorld lets organisms sense the predators found around them at the start
+// of the step
+type predatorWorld struct {
+	organismWorld
+	predators map[int64][]types.Point
+}
+
+// PredatorsNear returns the positions of the organism's predators
+func (w predatorWorld) PredatorsNear(org *types.Organism) []types.Point {
+	return w.predators[org.ID]
+}
+
+// BulletTimeSpeed is the simulation speed while bullet-time is engaged
+const BulletTimeSpeed = 0.1
+
+// StepRateWindow is how much wall-clock time each steps-per-second measurement covers
+const StepRateWindow = time.Second
+
 // ReproductionEventHandler is a function that handles reproduction events
 type ReproductionEventHandler func(types.Point)
 
+// DeathEventHandler is a function that handles organism deaths, receiving the
+// organism as it was when it was removed
+type DeathEventHandler func(types.Organism)
+
 // Simulator handles the simulation loop and organism updates
 type Simulator struct {
 	World           *world.World
 	Config          config.SimulationConfig
 	Time            float64                  // Simulation time in seconds
+	StepCount       int64                    // Number of steps advanced since the start or last reset
 	TimeStep        float64                  // Fixed time step in seconds
 	IsPaused        bool                     // Flag to pause/resume simulation
 	SimulationSpeed float64                  // Speed multiplier
 	rng             *rand.Rand               // Random number generator
+	rngSource       *types.CountingSource    // rng's source, which tracks its position
+	sourceFactory   types.SourceFactory      // Creates rng (nil uses math/rand's default)
 	OnReproduction  ReproductionEventHandler // Optional handler for reproduction events
+	OnDeath         DeathEventHandler        // Optional handler for organism deaths
+	RecordEvents    bool                     // Whether to log reproductions and deaths for ExportEventsCSV
+	Timeline        *EventTimeline           // Optional scripted environmental changes
+	Occupancy       *OccupancyGrid           // Optional record of where organisms spend their time
+	EndCondition    *EndCondition            // Optional rule that stops Run and headless runs early
+
+	// Bullet-time state
+	bulletTime            bool    // Whether bullet-time is engaged
+	speedBeforeBulletTime float64 // Speed to restore when bullet-time ends
+
+	// Steps-per-second measurement
+	rateWindowStart time.Time // Wall-clock start of the current measurement window
+	rateWindowSteps int64     // Steps taken in the current measurement window
+	stepsPerSecond  float64   // Rate measured over the last complete window
+	startTime       time.Time // Wall-clock time the run started or was last reset
+
+	// Event log, kept while RecordEvents is set
+	events     []Event
+	birthTimes map[int64]float64 // Simulation time each organism was born, by ID
 }
 
 // NewSimulator creates a new simulation engine with the given world and config
 func NewSimulator(world *world.World, config config.SimulationConfig) *Simulator {
-	// Create RNG
-	var seed int64
-	if config.RandomSeed != 0 {
-		seed = config.RandomSeed
-	} else {
-		seed = time.Now().UnixNano()
-	}
-	rng := rand.New(rand.NewSource(seed))
+	return NewSimulatorWithSource(world, config, nil)
+}
 
-	return &Simulator{
+// NewSimulatorWithSource creates a simulation engine like NewSimulator whose random
+// draws come from a generator made by factory. Pair it with a world made by
+// world.NewWorldWithSource to control every generator in a run.
+func NewSimulatorWithSource(world *world.World, config config.SimulationConfig, factory types.SourceFactory) *Simulator {
+	s := &Simulator{
 		World:           world,
 		Config:          config,
 		Time:            0.0,
 		TimeStep:        1.0 / 60.0, // Default to 60 FPS
 		IsPaused:        false,
 		SimulationSpeed: config.SimulationSpeed,
-		rng:             rng,
+		sourceFactory:   factory,
 		OnReproduction:  nil,
+		birthTimes:      make(map[int64]float64),
+		startTime:       time.Now(),
+	}
+	s.rng, s.rngSource = types.NewCountingRand(factory, config.RandomSeed)
+	return s
+}
+
+// sensingWorld returns the world organisms should sense, honoring Control.ExactSensing,
+// whether their feeding depletes sources, and whether they flee predators
+func (s *Simulator) sensingWorld() organismWorld {
+	var sensed organismWorld = s.World
+	if s.Config.Control.ExactSensing {
+		sensed = exactSensingWorld{s.World}
+	}
+	if !s.feedingDepletes() {
+		sensed = fixedSourcesWorld{sensed}
 	}
+	if ratio := s.Config.Organism.PredatorSizeRatio; ratio > 0 {
+		sensed = predatorWorld{sensed, s.World.PredatorsNear(ratio, organism.PredatorDetectionRadius)}
+	}
+	return sensed
+}
+
+// feedingDepletes reports whether organisms' feeding drains the chemical sources
+func (s *Simulator) feedingDepletes() bool {
+	return s.Config.Control.EnergyEnabled && s.Config.Energy.FeedingDepletion
 }
 
 // SetReproductionHandler sets a function to be called when reproduction events occur
@@ -53,6 +181,11 @@ func (s *Simulator) SetReproductionHandler(handler ReproductionEventHandler) {
 	s.OnReproduction = handler
 }
 
+// SetDeathHandler sets a function to be called for each organism that dies
+func (s *Simulator) SetDeathHandler(handler DeathEventHandler) {
+	s.OnDeath = handler
+}
+
 // Step advances the simulation by one time step
 func (s *Simulator) Step() {
 	if s.IsPaused {
@@ -65,31 +198,110 @@ func (s *Simulator) Step() {
 	// Get world bounds
 	bounds := s.World.GetBounds()
 
+	// With the energy system off, sources hold steady and organisms only navigate,
+	// never starving or growing old
+	energyEnabled := s.Config.Control.EnergyEnabled
+
+	// Apply scripted environmental changes that have come due
+	if s.Timeline != nil {
+		for _, event := range s.Timeline.Due(s.Time) {
+			event.apply(s.World)
+		}
+	}
+
 	// Update chemical sources
-	s.World.UpdateChemicalSources(adjustedTimeStep, s.rng)
+	if energyEnabled {
+		s.World.UpdateChemicalSources(adjustedTimeStep, s.rng)
+	}
+
+	// Scatter organisms out of overcrowded neighborhoods
+	if s.Config.Organism.CrowdingThreshold > 0 {
+		s.World.TriggerCrowdedDispersal(s.Config.Organism.CrowdingRadius, s.Config.Organism.CrowdingThreshold, s.Config.Organism.DispersalDuration)
+	}
 
-	// Update each organism
+	// Update each organism, or only this step's batch of them over the time since
+	// their last update
 	organisms := s.World.GetOrganisms()
-	for i := range organisms {
-		organism.Update(
-			&organisms[i],
-			s.World,
+	sensed := s.sensingWorld()
+	batches := updateBatches(s.Config.Organism.UpdateFraction)
+	organismTimeStep := adjustedTimeStep * float64(batches)
+	update := func(org *types.Organism, sensed organismWorld, rng *rand.Rand) {
+		if !dueForUpdate(org, s.StepCount, batches) {
+			return
+		}
+
+		previousEnergy := org.Energy
+		previousReserve := org.Reserve
+		organism.UpdateWithRand(
+			org,
+			sensed,
 			bounds,
 			s.Config.Organism.SensorDistance,
 			s.Config.Organism.TurnSpeed,
-			adjustedTimeStep,
+			organismTimeStep,
+			rng,
+			energyEnabled,
 		)
+
+		// Pure chemotaxis: undo the step's energy changes so organisms never starve
+		if !energyEnabled {
+			org.Energy = previousEnergy
+			org.Reserve = previousReserve
+			org.ClearStarvation()
+			return
+		}
+
+		// Keep large time steps from swinging energy too far at once; a batched
+		// update covers several steps, so it may move energy that much further
+		org.LimitEnergyChange(previousEnergy, s.Config.Energy.MaxEnergyChangePerStep*float64(batches))
+	}
+	if workers := s.Config.Control.UpdateWorkers; workers > 1 {
+		s.updateInParallel(organisms, sensed, workers, update)
+	} else {
+		for i := range organisms {
+			update(&organisms[i], sensed, s.rng)
+		}
 	}
 
-	// Update world with modified organisms
+	// Update world with modified organisms, then push apart any that now overlap
 	s.World.UpdateOrganisms(organisms)
+	if s.Config.Organism.CollisionRadius > 0 {
+		s.World.ResolveCollisions(s.Config.Organism.CollisionRadius)
+	}
+	if s.Occupancy != nil {
+		s.Occupancy.Record(organisms, adjustedTimeStep)
+	}
+
+	if !energyEnabled {
+		s.advanceTime(adjustedTimeStep)
+		return
+	}
+
+	// Let well-fed organisms support struggling kin nearby
+	if s.Config.Energy.KinShareRate > 0 {
+		s.World.ShareEnergyAmongKin(s.Config.Energy.KinShareRadius, s.Config.Energy.KinShareRate, adjustedTimeStep)
+	}
 
-	// Remove dead organisms (those with no energy)
-	s.World.RemoveDeadOrganisms()
+	// Remove dead organisms (those with no energy), then log and report them
+	dead := s.World.RemoveDeadOrganismsDetailed()
+	var beforeReproduction []types.Organism
+	if s.RecordEvents {
+		s.recordDeaths(dead)
+		beforeReproduction = s.World.GetOrganisms()
+	}
+	if s.OnDeath != nil {
+		for _, org := range dead {
+			s.OnDeath(org)
+		}
+	}
 
 	// Process reproduction with our configuration
 	reproCount, reproPositions := s.World.ProcessReproductionWithConfig(s.Config.Reproduction)
 
+	if s.RecordEvents && reproCount > 0 {
+		s.recordBirths(beforeReproduction, s.World.GetOrganisms())
+	}
+
 	// If reproduction events occurred and we have a handler, call it for each event
 	if reproCount > 0 && s.OnReproduction != nil {
 		for _, pos := range reproPositions {
@@ -97,17 +309,143 @@ func (s *Simulator) Step() {
 		}
 	}
 
-	// Update simulation time
-	s.Time += adjustedTimeStep
+	s.advanceTime(adjustedTimeStep)
+}
+
+// updateInParallel splits the organisms into one contiguous chunk per worker and
+// updates each chunk in its own goroutine. Each worker draws from its own generator,
+// seeded from the simulator's so seeded runs stay repeatable, and feeds through its
+// own depletion accumulator, so workers never contend for the source lock. The
+// accumulators are merged into the world once every worker is done.
+func (s *Simulator) updateInParallel(
+	organisms []types.Organism,
+	sensed organismWorld,
+	workers int,
+	update func(*types.Organism, organismWorld, *rand.Rand),
+) {
+	chunk := (len(organisms) + workers - 1) / workers
+	var accumulators []*world.DepletionAccumulator
+	var wg sync.WaitGroup
+	for start := 0; start < len(organisms); start += chunk {
+		view := sensed
+		if s.feedingDepletes() {
+			accumulator := s.World.NewDepletionAccumulator()
+			accumulators = append(accumulators, accumulator)
+			view = accumulatingWorld{sensed, accumulator}
+		}
+		rng, _ := types.NewCountingRand(s.sourceFactory, s.rng.Int63())
+
+		wg.Add(1)
+		go func(batch []types.Organism, view organismWorld, rng *rand.Rand) {
+			defer wg.Done()
+			for i := range batch {
+				update(&batch[i], view, rng)
+			}
+		}(organisms[start:min(start+chunk, len(organisms))], view, rng)
+	}
+	wg.Wait()
+
+	s.World.MergeDepletion(accumulators...)
+}
+
+// advanceTime moves the simulation clock forward by one step of the given length
+func (s *Simulator) advanceTime(timeStep float64) {
+	s.Time += timeStep
+	s.StepCount++
+	s.measureStepRate(time.Now())
+}
+
+// measureStepRate counts a step toward the current measurement window, closing
+// the window once it has covered StepRateWindow of wall-clock time
+func (s *Simulator) measureStepRate(now time.Time) {
+	if s.rateWindowStart.IsZero() {
+		s.rateWindowStart = now
+	}
+	s.rateWindowSteps++
+
+	if elapsed := now.Sub(s.rateWindowStart); elapsed >= StepRateWindow {
+		s.stepsPerSecond = float64(s.rateWindowSteps) / elapsed.Seconds()
+		s.rateWindowStart = now
+		s.rateWindowSteps = 0
+	}
+}
+
+// updateBatches returns how many steps it takes to update every organism when
+// only fraction of them are updated each step
+func updateBatches(fraction float64) int64 {
+	if fraction <= 0 || fraction >= 1 {
+		return 1
+	}
+	return int64(math.Ceil(1 / fraction))
+}
+
+// dueForUpdate reports whether the organism is in the batch updated on the given
+// step. Batches are picked by ID, so each organism is updated exactly every
+// batches steps however the population changes around it.
+func dueForUpdate(org *types.Organism, step, batches int64) bool {
+	return uint64(org.ID)%uint64(batches) == uint64(step)%uint64(batches)
+}
+
+// Steps returns the number of steps advanced since the start or last reset
+func (s *Simulator) Steps() int64 {
+	return s.StepCount
+}
+
+// StepsPerSecond returns the number of steps advanced per wall-clock second over
+// the last complete measurement window. Before the first window completes, the
+// rate so far is used instead.
+func (s *Simulator) StepsPerSecond() float64 {
+	if s.stepsPerSecond > 0 || s.rateWindowSteps == 0 {
+		return s.stepsPerSecond
+	}
+
+	elapsed := time.Since(s.rateWindowStart).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.rateWindowSteps) / elapsed
+}
+
+// RealTimeElapsed returns the wall-clock time since the run started or was last reset
+func (s *Simulator) RealTimeElapsed() time.Duration {
+	return time.Since(s.startTime)
+}
+
+// SimTimeRatio returns how many simulated seconds passed per real second, or 0
+// before any real time has passed
+func SimTimeRatio(simSeconds float64, realElapsed time.Duration) float64 {
+	if realElapsed <= 0 {
+		return 0
+	}
+	return simSeconds / realElapsed.Seconds()
 }
 
 // Reset resets the simulation to its initial state
 func (s *Simulator) Reset() {
-	// Reset simulation time
+	// Reset simulation time and step counting
 	s.Time = 0.0
+	s.StepCount = 0
+	s.rateWindowStart = time.Time{}
+	s.rateWindowSteps = 0
+	s.stepsPerSecond = 0
+	s.startTime = time.Now()
+
+	// Start a fresh event log
+	s.events = nil
+	s.birthTimes = make(map[int64]float64)
 
-	// Reset the world
+	// Reset the world, and restart the random sequence so a seeded run repeats itself
 	s.World.Reset(s.Config)
+	s.rng, s.rngSource = types.NewCountingRand(s.sourceFactory, s.Config.RandomSeed)
+	if s.Timeline != nil {
+		s.Timeline.Rewind()
+	}
+	if s.Occupancy != nil {
+		s.Occupancy = NewOccupancyGrid(s.Occupancy.Bounds, s.Occupancy.CellSize)
+	}
+	if s.EndCondition != nil {
+		s.EndCondition.Reset()
+	}
 
 	// Unpause the simulation
 	s.IsPaused = false
@@ -132,3 +470,25 @@ func (s *Simulator) SetSimulationSpeed(speed float64) {
 
 	s.SimulationSpeed = speed
 }
+
+// SetBulletTime slows the simulation to BulletTimeSpeed, saving the current speed,
+// or restores the saved speed when disabled. Repeated calls with the same state
+// have no effect, so this can be called every frame while a key is held.
+func (s *Simulator) SetBulletTime(enabled bool) {
+	if enabled == s.bulletTime {
+		return
+	}
+
+	if enabled {
+		s.speedBeforeBulletTime = s.SimulationSpeed
+		s.SetSimulationSpeed(BulletTimeSpeed)
+	} else {
+		s.SetSimulationSpeed(s.speedBeforeBulletTime)
+	}
+	s.bulletTime = enabled
+}
+
+// InBulletTime reports whether bullet-time is engaged
+func (s *Simulator) InBulletTime() bool {
+	return s.bulletTime
+}
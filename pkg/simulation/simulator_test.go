@@ -1,9 +1,15 @@
 package simulation
 
 import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/organism"
 	"github.com/zachbeta/evolve_sim/pkg/types"
 	"github.com/zachbeta/evolve_sim/pkg/world"
 )
@@ -35,6 +41,9 @@ func createTestConfig() config.SimulationConfig {
 			WindowHeight: 480,
 			FrameRate:    60,
 		},
+		Control: config.SimulationControl{
+			EnergyEnabled: true,
+		},
 		RandomSeed:      12345,
 		SimulationSpeed: 1.0,
 	}
@@ -183,6 +192,35 @@ func TestReset(t *testing.T) {
 	}
 }
 
+func TestResetMatchesFreshRun(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RandomSeed = 7
+	cfg.Organism.Count = 40
+
+	// run steps the simulator and returns the fingerprint of its world
+	run := func(sim *Simulator, steps int) []byte {
+		for i := 0; i < steps; i++ {
+			sim.Step()
+		}
+		fingerprint, err := StateFingerprint(sim.World)
+		if err != nil {
+			t.Fatalf("Failed to fingerprint world: %v", err)
+		}
+		return fingerprint
+	}
+
+	fresh := NewSimulator(world.NewWorld(cfg), cfg)
+	want := run(fresh, 600)
+
+	reset := NewSimulator(world.NewWorld(cfg), cfg)
+	run(reset, 300)
+	reset.Reset()
+	if got := run(reset, 600); !bytes.Equal(got, want) {
+		t.Errorf("World after reset and 600 steps differs from a fresh run: %s",
+			strings.Join(world.DiffWorlds(reset.World, fresh.World), "; "))
+	}
+}
+
 func TestSimulationSpeed(t *testing.T) {
 	// Create test config
 	cfg := createTestConfig()
@@ -248,9 +286,11 @@ func TestFullSimulationEnergyBalance(t *testing.T) {
 			MinDecayFactor:          0.001,
 			MaxDecayFactor:          0.01,
 			DepletionRate:           1.0, // Higher depletion rate for testing
+			RegenerationEnabled:     true,
 			RegenerationProbability: 0.1,
 			TargetSystemEnergy:      100000,
 		},
+		Control:    config.SimulationControl{EnergyEnabled: true},
 		RandomSeed: 42, // Fixed seed for deterministic testing
 	}
 
@@ -332,3 +372,486 @@ func TestFullSimulationEnergyBalance(t *testing.T) {
 	t.Logf("Final state: Energy=%v/%v, Sources=%v/%v active, %v partially depleted, Population=%v, AvgEnergy=%v",
 		finalEnergy, targetEnergy, activeCount, len(currentSources), partiallyDepletedCount, populationCount, avgEnergy)
 }
+
+func TestExactSensing(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Chemical.Count = 3
+	cfg.Control.ExactSensing = true
+	sim := NewSimulator(world.NewWorld(cfg), cfg)
+
+	sources := sim.World.GetChemicalSources()
+	directSum := func(p types.Point) float64 {
+		total := 0.0
+		for _, source := range sources {
+			total += source.GetConcentrationAt(p)
+		}
+		return total
+	}
+
+	// The gradient of Strength*energyRatio/(1 + d^2*decay), summed over the sources
+	analyticGradient := func(p types.Point) types.Point {
+		var gradient types.Point
+		for _, source := range sources {
+			dx, dy := p.X-source.Position.X, p.Y-source.Position.Y
+			d2 := dx*dx + dy*dy
+			if d2 > source.EffectiveRadius()*source.EffectiveRadius() {
+				continue
+			}
+			denominator := 1 + d2*source.DecayFactor
+			scale := -2 * source.Strength * source.Energy / source.MaxEnergy * source.DecayFactor / (denominator * denominator)
+			gradient.X += scale * dx
+			gradient.Y += scale * dy
+		}
+		return gradient
+	}
+
+	for _, org := range sim.World.GetOrganisms() {
+		readings := organism.ReadSensors(&org, sim.sensingWorld(), cfg.Organism.SensorDistance)
+		positions := org.GetSensorPositions(cfg.Organism.SensorDistance)
+
+		got := [3]float64{readings.Front, readings.Left, readings.Right}
+		for i, p := range positions {
+			if want := directSum(p); got[i] != want {
+				t.Errorf("Organism %d sensor %d = %v; want direct sum %v", org.ID, i, got[i], want)
+			}
+		}
+
+		want := analyticGradient(org.Position)
+		wantMagnitude := math.Hypot(want.X, want.Y)
+		if math.Abs(readings.GradientMagnitude-wantMagnitude) > 1e-9*wantMagnitude+1e-12 {
+			t.Errorf("Organism %d gradient magnitude = %v; want analytic %v", org.ID, readings.GradientMagnitude, wantMagnitude)
+		}
+		if wantDirection := math.Atan2(want.Y, want.X); math.Abs(readings.GradientDirection-wantDirection) > 1e-9 {
+			t.Errorf("Organism %d gradient direction = %v; want analytic %v", org.ID, readings.GradientDirection, wantDirection)
+		}
+	}
+}
+
+func TestBulletTime(t *testing.T) {
+	cfg := createTestConfig()
+	sim := NewSimulator(world.NewWorld(cfg), cfg)
+	sim.SetSimulationSpeed(4.0)
+
+	// Engaging slows the simulation down
+	sim.SetBulletTime(true)
+	if !sim.InBulletTime() || sim.SimulationSpeed != BulletTimeSpeed {
+		t.Errorf("After engaging: bullet-time %v, speed %v; want true, %v", sim.InBulletTime(), sim.SimulationSpeed, BulletTimeSpeed)
+	}
+
+	// Holding the key calls this every frame; the saved speed must survive
+	sim.SetBulletTime(true)
+	sim.SetBulletTime(false)
+	if sim.InBulletTime() || sim.SimulationSpeed != 4.0 {
+		t.Errorf("After release: bullet-time %v, speed %v; want false, 4.0", sim.InBulletTime(), sim.SimulationSpeed)
+	}
+
+	// Releasing again leaves the speed alone
+	sim.SetSimulationSpeed(6.0)
+	sim.SetBulletTime(false)
+	if sim.SimulationSpeed != 6.0 {
+		t.Errorf("Redundant release changed speed to %v; want 6.0", sim.SimulationSpeed)
+	}
+}
+
+func TestStepCount(t *testing.T) {
+	cfg := createTestConfig()
+	sim := NewSimulator(world.NewWorld(cfg), cfg)
+
+	const n = 25
+	for i := 0; i < n; i++ {
+		sim.Step()
+	}
+	if sim.StepCount != n || sim.Steps() != n {
+		t.Errorf("After %d steps: StepCount %d, Steps() %d; want %d", n, sim.StepCount, sim.Steps(), n)
+	}
+	if sim.StepsPerSecond() <= 0 {
+		t.Errorf("StepsPerSecond() = %v; want a positive rate after stepping", sim.StepsPerSecond())
+	}
+
+	// Paused steps don't count
+	sim.SetPaused(true)
+	sim.Step()
+	if sim.StepCount != n {
+		t.Errorf("StepCount after a paused step = %d; want %d", sim.StepCount, n)
+	}
+
+	sim.Reset()
+	if sim.StepCount != 0 || sim.StepsPerSecond() != 0 {
+		t.Errorf("After reset: StepCount %d, StepsPerSecond %v; want 0, 0", sim.StepCount, sim.StepsPerSecond())
+	}
+}
+
+func TestDeathHandler(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.World = config.WorldConfig{Width: 100, Height: 100}
+	cfg.Organism.Count = 0
+	cfg.Chemical.Count = 0
+	w := world.NewWorld(cfg)
+
+	// Three organisms out of energy and one with plenty to spare
+	dead := make(map[int64]bool)
+	for i := 0; i < 3; i++ {
+		org := types.NewOrganism(types.Point{X: float64(20 + i*20), Y: 50}, 0, 30.0, 1.0, types.DefaultSensorAngles())
+		org.Energy = 0
+		w.AddOrganism(org)
+		dead[org.ID] = true
+	}
+	survivor := types.NewOrganism(types.Point{X: 50, Y: 80}, 0, 30.0, 1.0, types.DefaultSensorAngles())
+	survivor.Energy = survivor.EnergyCapacity
+	w.AddOrganism(survivor)
+
+	sim := NewSimulator(w, cfg)
+	calls := make(map[int64]int)
+	sim.SetDeathHandler(func(org types.Organism) {
+		calls[org.ID]++
+	})
+
+	// Later steps must not report the same deaths again
+	for i := 0; i < 3; i++ {
+		sim.Step()
+	}
+
+	if len(calls) != len(dead) {
+		t.Errorf("Death handler saw %d organisms; want %d", len(calls), len(dead))
+	}
+	for id, count := range calls {
+		if !dead[id] {
+			t.Errorf("Death handler fired for living organism %d", id)
+		}
+		if count != 1 {
+			t.Errorf("Death handler fired %d times for organism %d; want once", count, id)
+		}
+	}
+}
+
+func TestCollisionsSeparateOrganisms(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.World = config.WorldConfig{Width: 100, Height: 100}
+	cfg.Organism.Count = 0
+	cfg.Chemical.Count = 0
+	cfg.Organism.CollisionRadius = 3
+	w := world.NewWorld(cfg)
+
+	// Two organisms on the same spot
+	for i := 0; i < 2; i++ {
+		org := types.NewOrganism(types.Point{X: 50, Y: 50}, 0, 30.0, 1.0, types.DefaultSensorAngles())
+		org.Energy = org.EnergyCapacity * 0.5
+		w.AddOrganism(org)
+	}
+
+	sim := NewSimulator(w, cfg)
+	sim.Step()
+
+	orgs := sim.World.GetOrganisms()
+	if len(orgs) != 2 {
+		t.Fatalf("Got %d organisms after a step; want 2", len(orgs))
+	}
+	contact := (orgs[0].BodySize() + orgs[1].BodySize()) * cfg.Organism.CollisionRadius
+	if distance := orgs[0].Position.DistanceTo(orgs[1].Position); distance < contact-1e-9 {
+		t.Errorf("Organisms %v apart after a step; want at least the sum of their radii, %v", distance, contact)
+	}
+}
+
+func TestPreyFleesLargerOrganisms(t *testing.T) {
+	distanceAfter := func(sizeRatio float64) float64 {
+		cfg := config.DefaultConfig()
+		cfg.World = config.WorldConfig{Width: 100, Height: 100}
+		cfg.Organism.Count = 0
+		cfg.Chemical.Count = 0
+		cfg.Organism.PredatorSizeRatio = sizeRatio
+		w := world.NewWorld(cfg)
+
+		// Small prey heading straight at a much larger organism that stays put
+		prey := types.NewOrganism(types.Point{X: 40, Y: 50}, 0, 30.0, 1.0, types.DefaultSensorAngles())
+		predator := types.NewOrganism(types.Point{X: 55, Y: 50}, 0, 30.0, 0, types.DefaultSensorAngles())
+		predator.Size = 3
+		for _, org := range []types.Organism{prey, predator} {
+			org.Energy = org.EnergyCapacity * 0.5
+			w.AddOrganism(org)
+		}
+
+		sim := NewSimulator(w, cfg)
+		for i := 0; i < 60; i++ {
+			sim.Step()
+		}
+
+		orgs := sim.World.GetOrganisms()
+		return orgs[0].Position.DistanceTo(orgs[1].Position)
+	}
+
+	ignoring, fleeing := distanceAfter(0), distanceAfter(2)
+	if fleeing <= ignoring {
+		t.Errorf("Prey ends %v from the larger organism with fleeing on; want farther than %v with it off", fleeing, ignoring)
+	}
+}
+
+func TestEnergyDisabled(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Control.EnergyEnabled = false
+	cfg.Energy = config.DefaultConfig().Energy
+	sim := NewSimulator(world.NewWorld(cfg), cfg)
+
+	before := sim.World.GetOrganisms()
+	sourcesBefore := sim.World.GetChemicalSources()
+	for i := 0; i < 2000; i++ {
+		sim.Step()
+	}
+	after := sim.World.GetOrganisms()
+
+	if len(after) != len(before) {
+		t.Fatalf("Organism count changed from %d to %d with energy disabled", len(before), len(after))
+	}
+
+	moved := false
+	for i := range after {
+		if after[i].ID != before[i].ID {
+			t.Fatalf("Organism %d changed identity from %d to %d", i, before[i].ID, after[i].ID)
+		}
+		if after[i].Energy != before[i].Energy {
+			t.Errorf("Organism %d energy changed from %v to %v", i, before[i].Energy, after[i].Energy)
+		}
+		moved = moved || after[i].Position != before[i].Position
+	}
+	if !moved {
+		t.Error("Expected organisms to keep navigating with energy disabled")
+	}
+
+	for i, source := range sim.World.GetChemicalSources() {
+		if source.Energy != sourcesBefore[i].Energy {
+			t.Errorf("Source %d energy changed from %v to %v", i, sourcesBefore[i].Energy, source.Energy)
+		}
+	}
+}
+
+func TestEnergyDisabledStopsAging(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Control.EnergyEnabled = false
+	cfg.Energy = config.DefaultConfig().Energy
+	cfg.Organism.MaxAge = 1
+	sim := NewSimulator(world.NewWorld(cfg), cfg)
+
+	// Step well past the lifespan, then check organisms are alive and still moving
+	for i := 0; i < 300; i++ {
+		sim.Step()
+	}
+	before := sim.World.GetOrganisms()
+	sim.Step()
+	after := sim.World.GetOrganisms()
+
+	if len(after) != cfg.Organism.Count {
+		t.Fatalf("Organism count = %d past MaxAge with energy disabled; want %d", len(after), cfg.Organism.Count)
+	}
+	moved := false
+	for i := range after {
+		if after[i].Age != 0 || after[i].MarkForRemoval {
+			t.Errorf("Organism %d has age %v and removal mark %v; want 0 and unmarked", i, after[i].Age, after[i].MarkForRemoval)
+		}
+		moved = moved || after[i].Position != before[i].Position
+	}
+	if !moved {
+		t.Error("Expected organisms to keep navigating past MaxAge with energy disabled")
+	}
+}
+
+func TestDefaultRunStaysPopulated(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RandomSeed = 7
+	sim := NewSimulator(world.NewWorld(cfg), cfg)
+
+	for sim.Time < 1500 {
+		sim.Step()
+	}
+	if population, _ := sim.World.GetPopulationInfo(); population == 0 {
+		t.Errorf("Default seeded run went extinct by t=%.0fs; want a surviving population", sim.Time)
+	}
+}
+
+func TestSensingWorldReportsGradients(t *testing.T) {
+	for _, energyEnabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("energyEnabled=%v", energyEnabled), func(t *testing.T) {
+			cfg := createTestConfig()
+			cfg.Organism.Count = 0
+			cfg.Control.EnergyEnabled = energyEnabled
+			sim := NewSimulator(world.NewWorld(cfg), cfg)
+
+			// Beside the source, where the landscape slopes toward it
+			source := sim.World.GetChemicalSources()[0]
+			org := types.NewOrganism(types.Point{X: source.Position.X + 10, Y: source.Position.Y}, 0, 30.0, 1.0, types.DefaultSensorAngles())
+			if readings := organism.ReadSensors(&org, sim.sensingWorld(), cfg.Organism.SensorDistance); readings.GradientMagnitude == 0 {
+				t.Error("Organisms sense no gradient beside a source; want the world's gradient passed through")
+			}
+		})
+	}
+}
+
+func TestFeedingRadiusDepletesSourcesInReach(t *testing.T) {
+	feeder := types.Point{X: 300, Y: 500}
+
+	// drainedFrom returns the energy the far source has after one step with an
+	// organism of the given size (none if 0) feeding at the near source's center.
+	// The far source sits just beyond reach of feeder, by more than a size-1
+	// organism's feeding radius but less than a size-4 one's.
+	drainedFrom := func(size float64) float64 {
+		cfg := createTestConfig()
+		cfg.World = config.WorldConfig{Width: 1000, Height: 1000}
+		cfg.Organism.Count = 0
+		cfg.Chemical.Count = 0
+		cfg.Energy.FeedingDepletion = true
+		w := world.NewWorld(cfg)
+
+		w.AddChemicalSource(types.NewChemicalSource(feeder, 100, 1))
+		far := types.NewChemicalSource(types.Point{Y: feeder.Y}, 100, 1)
+		far.Position.X = feeder.X + far.EffectiveRadius() + 2.5*types.FeedingRadiusPerSize
+		w.AddChemicalSource(far)
+
+		if size > 0 {
+			org := types.NewOrganism(feeder, math.Pi, w.GetConcentrationAt(feeder), 1.0, types.DefaultSensorAngles())
+			org.Size = size
+			w.AddOrganism(org)
+		}
+
+		NewSimulator(w, cfg).Step()
+		return w.GetChemicalSources()[1].Energy
+	}
+
+	untouched := drainedFrom(0)
+	if got := drainedFrom(1); got != untouched {
+		t.Errorf("Far source holds %v after a size-1 organism fed; want %v, as if nothing fed", got, untouched)
+	}
+	if got := drainedFrom(4); got >= untouched {
+		t.Errorf("Far source holds %v after a size-4 organism fed; want below %v", got, untouched)
+	}
+}
+
+func TestParallelUpdate(t *testing.T) {
+	// run takes steps with the given number of update workers and returns the
+	// world's fingerprint and the energy its sources lost
+	run := func(workers, steps int) ([]byte, float64) {
+		cfg := config.DefaultConfig()
+		cfg.RandomSeed = 7
+		cfg.Organism.Count = 200
+		cfg.Energy.FeedingDepletion = true
+		cfg.Control.UpdateWorkers = workers
+		sim := NewSimulator(world.NewWorld(cfg), cfg)
+
+		sourceEnergy := func() float64 {
+			total := 0.0
+			for _, source := range sim.World.GetChemicalSources() {
+				total += source.Energy
+			}
+			return total
+		}
+
+		before := sourceEnergy()
+		for i := 0; i < steps; i++ {
+			sim.Step()
+		}
+		fingerprint, err := StateFingerprint(sim.World)
+		if err != nil {
+			t.Fatalf("Failed to fingerprint world: %v", err)
+		}
+		return fingerprint, before - sourceEnergy()
+	}
+
+	// Workers feed through accumulators merged after the step; the sources should
+	// lose what they lose when organisms feed one at a time
+	_, sequentialDrain := run(0, 1)
+	_, parallelDrain := run(4, 1)
+	if sequentialDrain <= 0 {
+		t.Fatalf("Sources lost %v in a sequential step; want feeding to drain them", sequentialDrain)
+	}
+	if math.Abs(parallelDrain-sequentialDrain) > 1e-6*sequentialDrain {
+		t.Errorf("Sources lost %v in a parallel step; want %v, as in a sequential one", parallelDrain, sequentialDrain)
+	}
+
+	first, _ := run(4, 120)
+	second, _ := run(4, 120)
+	if !bytes.Equal(first, second) {
+		t.Error("Seeded parallel runs differ; want them repeatable")
+	}
+}
+
+func TestSimTimeRatio(t *testing.T) {
+	tests := []struct {
+		name        string
+		simSeconds  float64
+		realElapsed time.Duration
+		want        float64
+	}{
+		{"Faster than real time", 30, 3 * time.Second, 10},
+		{"Slower than real time", 1, 4 * time.Second, 0.25},
+		{"No real time yet", 5, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SimTimeRatio(tt.simSeconds, tt.realElapsed); got != tt.want {
+				t.Errorf("SimTimeRatio(%v, %v) = %v; want %v", tt.simSeconds, tt.realElapsed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchedUpdatesCompensateTimeStep(t *testing.T) {
+	// newWalker returns a simulator with one organism walking straight across an
+	// empty world, updating the given fraction of organisms per step
+	newWalker := func(fraction float64) *Simulator {
+		cfg := createTestConfig()
+		cfg.World = config.WorldConfig{Width: 1000, Height: 1000}
+		cfg.Organism.Count = 0
+		cfg.Organism.UpdateFraction = fraction
+		cfg.Chemical.Count = 0
+		cfg.Control.EnergyEnabled = false
+		w := world.NewWorld(cfg)
+
+		org := types.NewOrganism(types.Point{X: 100, Y: 500}, 0, 30.0, 1.0, types.DefaultSensorAngles())
+		org.ID = 1 // In the batch updated on odd steps when every other organism is updated
+		w.AddOrganism(org)
+
+		return NewSimulator(w, cfg)
+	}
+
+	everyStep, everyOtherStep := newWalker(0), newWalker(0.5)
+
+	everyOtherStep.Step()
+	if x := everyOtherStep.World.GetOrganisms()[0].Position.X; x != 100 {
+		t.Fatalf("Organism moved to x=%v on a step outside its batch", x)
+	}
+	everyOtherStep.Step()
+
+	for everyStep.StepCount < 2 {
+		everyStep.Step()
+	}
+	for everyOtherStep.StepCount < 600 {
+		everyStep.Step()
+		everyOtherStep.Step()
+	}
+
+	want := everyStep.World.GetOrganisms()[0].Position
+	got := everyOtherStep.World.GetOrganisms()[0].Position
+	if math.Abs(got.X-want.X) > 1e-9 || math.Abs(got.Y-want.Y) > 1e-9 {
+		t.Errorf("Organism updated every other step ended at %v; want %v, where one updated every step ended", got, want)
+	}
+	if distance := want.X - 100; math.Abs(distance-600*everyStep.TimeStep) > 1e-9 {
+		t.Errorf("Organism updated every step walked %v; want %v", distance, 600*everyStep.TimeStep)
+	}
+}
+
+func BenchmarkStepUpdateFraction(b *testing.B) {
+	for _, fraction := range []float64{0, 0.25} {
+		b.Run(fmt.Sprintf("fraction=%v", fraction), func(b *testing.B) {
+			cfg := config.DefaultConfig()
+			cfg.RandomSeed = 1
+			cfg.Organism.Count = 5000
+			cfg.Organism.UpdateFraction = fraction
+			cfg.Reproduction.MaxPopulation = 5000
+			sim := NewSimulator(world.NewWorld(cfg), cfg)
+
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				sim.Step()
+			}
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "steps/s")
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package simulation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+// StatsSink receives simulation statistics one sample at a time as a run progresses.
+// Implement it to send statistics somewhere other than a file, such as a database.
+type StatsSink interface {
+	Write(stats SimulationStats) error
+	Close() error
+}
+
+// NopStatsSink discards all statistics
+type NopStatsSink struct{}
+
+// Write discards the statistics
+func (NopStatsSink) Write(SimulationStats) error { return nil }
+
+// Close does nothing
+func (NopStatsSink) Close() error { return nil }
+
+// CSVStatsSink writes statistics to a CSV file in the same format as ExportStatsCSV
+type CSVStatsSink struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+// NewCSVStatsSink creates the CSV file and writes its header
+func NewCSVStatsSink(filename string) (*CSVStatsSink, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &CSVStatsSink{file: file, writer: csv.NewWriter(file)}
+	if err := sink.writer.Write(statsCSVHeader); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return sink, nil
+}
+
+// Write appends one row of statistics
+func (s *CSVStatsSink) Write(stats SimulationStats) error {
+	return s.writer.Write(statsCSVRow(stats))
+}
+
+// Close flushes any buffered rows and closes the file
+func (s *CSVStatsSink) Close() error {
+	s.writer.Flush()
+	return errors.Join(s.writer.Error(), s.file.Close())
+}
+
+// JSONStatsSink writes statistics to a file as a JSON array, in the same format
+// as ExportStatsJSON. The array is only complete once the sink is closed.
+type JSONStatsSink struct {
+	array *jsonArrayFile
+}
+
+// NewJSONStatsSink creates the JSON file
+func NewJSONStatsSink(filename string) (*JSONStatsSink, error) {
+	array, err := newJSONArrayFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONStatsSink{array: array}, nil
+}
+
+// Write appends one sample to the array
+func (s *JSONStatsSink) Write(stats SimulationStats) error {
+	return s.array.append(stats)
+}
+
+// Close ends the array and closes the file
+func (s *JSONStatsSink) Close() error {
+	return s.array.close()
+}
+
+// jsonArrayFile writes a JSON array to a file one element at a time, indented
+// like json.MarshalIndent would indent the whole array
+type jsonArrayFile struct {
+	file  *os.File
+	count int // Number of elements written so far
+}
+
+// newJSONArrayFile creates the file
+func newJSONArrayFile(filename string) (*jsonArrayFile, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonArrayFile{file: file}, nil
+}
+
+// append writes one element
+func (a *jsonArrayFile) append(v any) error {
+	data, err := json.MarshalIndent(v, "  ", "  ")
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n  "
+	if a.count == 0 {
+		separator = "[\n  "
+	}
+	if _, err := a.file.WriteString(separator); err != nil {
+		return err
+	}
+	if _, err := a.file.Write(data); err != nil {
+		return err
+	}
+	a.count++
+	return nil
+}
+
+// close ends the array and closes the file
+func (a *jsonArrayFile) close() error {
+	closing := "\n]"
+	if a.count == 0 {
+		closing = "[]"
+	}
+	_, err := a.file.WriteString(closing)
+	return errors.Join(err, a.file.Close())
+}
+
+// multiStatsSink writes every sample to several sinks
+type multiStatsSink []StatsSink
+
+// NewMultiStatsSink returns a sink that writes to all of the given sinks
+func NewMultiStatsSink(sinks ...StatsSink) StatsSink {
+	return multiStatsSink(sinks)
+}
+
+// Write writes the sample to every sink, even if an earlier one fails
+func (m multiStatsSink) Write(stats SimulationStats) error {
+	var errs []error
+	for _, sink := range m {
+		errs = append(errs, sink.Write(stats))
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every sink
+func (m multiStatsSink) Close() error {
+	var errs []error
+	for _, sink := range m {
+		errs = append(errs, sink.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,107 @@
+package simulation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// sinkTestStats returns a short series of statistics for comparing exporters
+func sinkTestStats() []SimulationStats {
+	return []SimulationStats{
+		{
+			Time:      0.0,
+			Organisms: OrganismStats{Count: 10, AveragePreference: 25.0, PreferenceStdDev: 5.0},
+			Chemicals: ChemicalStats{SourceCount: 3, AverageConcentration: 30.0, MaxConcentration: 100.0},
+		},
+		{
+			Time: 10.0,
+			Organisms: OrganismStats{
+				Count:               12,
+				AveragePreference:   25.5,
+				PreferenceStdDev:    4.8,
+				PreferenceHistogram: map[string]int{"20": 5, "25": 7},
+			},
+			Chemicals: ChemicalStats{SourceCount: 3, AverageConcentration: 29.0, MaxConcentration: 98.5},
+		},
+	}
+}
+
+// writeToSink streams stats through a sink and closes it
+func writeToSink(t *testing.T, sink StatsSink, stats []SimulationStats) {
+	t.Helper()
+	for _, stat := range stats {
+		if err := sink.Write(stat); err != nil {
+			t.Fatalf("Failed to write to sink: %v", err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Failed to close sink: %v", err)
+	}
+}
+
+// readFile returns the contents of a file, failing the test if it can't be read
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestStatsSinksMatchBatchExport(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, tt := range []struct {
+		name   string
+		stats  []SimulationStats
+		ext    string
+		batch  func([]SimulationStats, string) error
+		stream func(string) (StatsSink, error)
+	}{
+		{"CSV", sinkTestStats(), "csv", ExportStatsCSV,
+			func(path string) (StatsSink, error) { return NewCSVStatsSink(path) }},
+		{"JSON", sinkTestStats(), "json", ExportStatsJSON,
+			func(path string) (StatsSink, error) { return NewJSONStatsSink(path) }},
+		{"Empty JSON", []SimulationStats{}, "json", ExportStatsJSON,
+			func(path string) (StatsSink, error) { return NewJSONStatsSink(path) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			batchPath := filepath.Join(dir, tt.name+"_batch."+tt.ext)
+			if err := tt.batch(tt.stats, batchPath); err != nil {
+				t.Fatalf("Batch export failed: %v", err)
+			}
+
+			streamPath := filepath.Join(dir, tt.name+"_stream."+tt.ext)
+			sink, err := tt.stream(streamPath)
+			if err != nil {
+				t.Fatalf("Failed to open sink: %v", err)
+			}
+			writeToSink(t, sink, tt.stats)
+
+			if got, want := readFile(t, streamPath), readFile(t, batchPath); got != want {
+				t.Errorf("Sink output:\n%s\nwant batch output:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestMultiStatsSink(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "stats.csv")
+	csvSink, err := NewCSVStatsSink(csvPath)
+	if err != nil {
+		t.Fatalf("Failed to open CSV sink: %v", err)
+	}
+
+	writeToSink(t, NewMultiStatsSink(csvSink, NopStatsSink{}), sinkTestStats())
+
+	batchPath := filepath.Join(dir, "batch.csv")
+	if err := ExportStatsCSV(sinkTestStats(), batchPath); err != nil {
+		t.Fatalf("Batch export failed: %v", err)
+	}
+	if readFile(t, csvPath) != readFile(t, batchPath) {
+		t.Error("Expected the multi-sink's CSV output to match the batch export")
+	}
+}
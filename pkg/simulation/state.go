@@ -0,0 +1,68 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+// SavedState is a checkpoint of a run, written by SaveState and resumed by LoadState
+type SavedState struct {
+	Config          config.SimulationConfig `json:"config"`
+	Time            float64                 `json:"time"`
+	StepCount       int64                   `json:"stepCount"`
+	SimulationSpeed float64                 `json:"simulationSpeed"`
+	Rng             types.RandState         `json:"rng"`
+	World           world.State             `json:"world"`
+}
+
+// SaveState writes a checkpoint of the simulation to a JSON file. The timeline,
+// occupancy grid, end condition and event log aren't included.
+func SaveState(sim *Simulator, path string) error {
+	state := SavedState{
+		Config:          sim.Config,
+		Time:            sim.Time,
+		StepCount:       sim.StepCount,
+		SimulationSpeed: sim.SimulationSpeed,
+		Rng:             sim.rngSource.State(),
+		World:           sim.World.SaveState(),
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadState resumes a simulation from a checkpoint written by SaveState, with the
+// config it was saved with. The seeded generators pick up where they left off, so
+// stepping it matches a run that never stopped.
+func LoadState(path string) (*Simulator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var state SavedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid saved state: %w", err)
+	}
+	if err := state.Config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config in saved state: %w", err)
+	}
+
+	w := world.NewWorld(state.Config)
+	w.RestoreState(state.World)
+
+	sim := NewSimulator(w, state.Config)
+	sim.Time = state.Time
+	sim.StepCount = state.StepCount
+	sim.SimulationSpeed = state.SimulationSpeed
+	sim.rng, sim.rngSource = types.RestoreRand(sim.sourceFactory, state.Rng)
+	return sim, nil
+}
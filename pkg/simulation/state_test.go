@@ -0,0 +1,89 @@
+package simulation
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+func TestSaveAndLoadState(t *testing.T) {
+	// Saved just before the founders first reproduce
+	cfg := config.DefaultConfig()
+	cfg.Organism.Count = 40
+	cfg.Chemical.RegenerationProbability = 30
+	cfg.RandomSeed = 7
+	cfg.SimulationSpeed = 5.0
+
+	original := NewSimulator(world.NewWorld(cfg), cfg)
+	for i := 0; i < 10; i++ {
+		original.Step()
+	}
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := SaveState(original, path); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+
+	atSave := make(map[int64]bool)
+	for _, org := range original.World.GetOrganisms() {
+		atSave[org.ID] = true
+	}
+
+	for i := 0; i < 100; i++ {
+		original.Step()
+		loaded.Step()
+	}
+
+	births := 0
+	for _, org := range original.World.GetOrganisms() {
+		if !atSave[org.ID] {
+			births++
+		}
+	}
+	if births == 0 {
+		t.Fatal("Expected births after the save")
+	}
+
+	if loaded.Time != original.Time || loaded.StepCount != original.StepCount {
+		t.Errorf("Loaded run at %vs, step %d; want %vs, step %d", loaded.Time, loaded.StepCount, original.Time, original.StepCount)
+	}
+	if loaded.rngSource.State() != original.rngSource.State() {
+		t.Errorf("Loaded generator at %+v; want %+v", loaded.rngSource.State(), original.rngSource.State())
+	}
+
+	want, err := StateFingerprint(original.World)
+	if err != nil {
+		t.Fatalf("Failed to fingerprint the original: %v", err)
+	}
+	got, err := StateFingerprint(loaded.World)
+	if err != nil {
+		t.Fatalf("Failed to fingerprint the loaded run: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Loaded run diverged from the original: %v", world.DiffWorlds(original.World, loaded.World))
+	}
+}
+
+func TestLoadStateErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadState(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+
+	sim := NewSimulator(world.NewWorld(createTestConfig()), createTestConfig())
+	sim.Config.Chemical.MinDecayFactor = -1
+	path := filepath.Join(dir, "invalid.json")
+	if err := SaveState(sim, path); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+	if _, err := LoadState(path); err == nil {
+		t.Error("Expected an error for a saved state with an invalid config")
+	}
+}
@@ -8,11 +8,14 @@ import (
 	"os"
 	"time"
 
+	"github.com/zachbeta/evolve_sim/pkg/organism"
 	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
 )
 
 // OrganismStats holds statistics about organisms in the simulation
 type OrganismStats struct {
+	Empty                   bool // No organisms were measured; averages are zero for lack of data, not measured as zero
 	Count                   int
 	AveragePreference       float64
 	PreferenceStdDev        float64
@@ -23,15 +26,32 @@ type OrganismStats struct {
 	PreferenceExposureRatio float64        // Average ratio of preference to actual concentration
 	AverageEnergy           float64        // Average energy level of organisms
 	EnergyRatio             float64        // Average energy as percentage of capacity
+	EnergyHistogram         map[string]int // Bucketized energy, as a percentage of capacity
+	WallStuckCount          int            // Organisms bouncing repeatedly off the walls
+	NonFiniteCount          int            // Organisms left out of the other statistics for NaN, infinite or zero-capacity values
+
+	// Per-generation averages, to check whether later generations outperform earlier ones
+	EnergyRatioByGeneration   map[int]float64 // Average energy ratio of each generation
+	ExposureRatioByGeneration map[int]float64 // Average preference exposure ratio of each generation
+
+	// Organisms removed so far over the whole run, by cause of death
+	DeathsByCause map[types.DeathCause]int
+
+	// Share of the population using each foraging strategy, by config name
+	StrategyShares map[string]float64
 }
 
 // ChemicalStats holds statistics about chemical concentrations
 type ChemicalStats struct {
+	Empty                  bool // No chemical sources; the field is zero everywhere and was not sampled
 	SourceCount            int
 	AverageConcentration   float64
 	MaxConcentration       float64
 	MinConcentration       float64
 	ConcentrationHistogram map[string]int // Bucketized concentrations
+
+	// Energy left in the sources of each chemical type
+	EnergyByType map[int]world.ChemicalEnergyInfo
 }
 
 // SimulationStats holds all statistics for a simulation
@@ -42,24 +62,48 @@ type SimulationStats struct {
 	Chemicals       ChemicalStats
 }
 
-// Histogram bucket size
-const histogramBucketSize = 5.0
+// Histogram bucket sizes
+const (
+	histogramBucketSize       = 5.0  // Preference and concentration units per bucket
+	energyHistogramBucketSize = 10.0 // Percent of capacity per energy bucket
+)
 
 // calculateOrganismStats calculates statistics about organisms
 func calculateOrganismStats(organisms []types.Organism, world interface{ GetConcentrationAt(types.Point) float64 }) OrganismStats {
+	// Leave out organisms whose values would turn every average into NaN
+	valid := make([]types.Organism, 0, len(organisms))
+	for _, org := range organisms {
+		if finiteOrganism(org) {
+			valid = append(valid, org)
+		}
+	}
+	nonFinite := len(organisms) - len(valid)
+	organisms = valid
+
 	if len(organisms) == 0 {
 		return OrganismStats{
-			Count:               0,
-			PreferenceHistogram: make(map[string]int),
+			Empty:                     true,
+			Count:                     0,
+			NonFiniteCount:            nonFinite,
+			PreferenceHistogram:       make(map[string]int),
+			EnergyHistogram:           make(map[string]int),
+			EnergyRatioByGeneration:   make(map[int]float64),
+			ExposureRatioByGeneration: make(map[int]float64),
+			StrategyShares:            make(map[string]float64),
 		}
 	}
 
 	// Initialize stats
 	stats := OrganismStats{
-		Count:               len(organisms),
-		MinPreference:       math.MaxFloat64,
-		MaxPreference:       -math.MaxFloat64,
-		PreferenceHistogram: make(map[string]int),
+		Count:                     len(organisms),
+		NonFiniteCount:            nonFinite,
+		MinPreference:             math.MaxFloat64,
+		MaxPreference:             -math.MaxFloat64,
+		PreferenceHistogram:       make(map[string]int),
+		EnergyHistogram:           make(map[string]int),
+		EnergyRatioByGeneration:   make(map[int]float64),
+		ExposureRatioByGeneration: make(map[int]float64),
+		StrategyShares:            make(map[string]float64),
 	}
 
 	// Sum for average calculation
@@ -70,6 +114,7 @@ func calculateOrganismStats(organisms []types.Organism, world interface{ GetConc
 	var energySum float64
 	var energyRatioSum float64
 	preferences := make([]float64, len(organisms))
+	generationCounts := make(map[int]int)
 
 	// Collect data
 	for i, org := range organisms {
@@ -95,17 +140,34 @@ func calculateOrganismStats(organisms []types.Organism, world interface{ GetConc
 
 		// Calculate preference exposure ratio (how close organism is to its preferred concentration)
 		// Avoid division by zero
+		exposureRatio := 0.0
 		if conc > 0 {
-			ratio := pref / conc
-			if ratio > 1 {
-				ratio = 1 / ratio // Normalize to 0-1 range
+			exposureRatio = pref / conc
+			if exposureRatio > 1 {
+				exposureRatio = 1 / exposureRatio // Normalize to 0-1 range
 			}
-			exposureRatioSum += ratio
+			exposureRatioSum += exposureRatio
+		}
+
+		if organism.WallStuck(org) {
+			stats.WallStuckCount++
 		}
 
 		// Add energy statistics
 		energySum += org.Energy
-		energyRatioSum += org.Energy / org.EnergyCapacity
+		energyRatio := org.Energy / org.EnergyCapacity
+		energyRatioSum += energyRatio
+
+		// Sum per generation; divided into averages below
+		generationCounts[org.Generation]++
+		stats.EnergyRatioByGeneration[org.Generation] += energyRatio
+		stats.ExposureRatioByGeneration[org.Generation] += exposureRatio
+		stats.StrategyShares[organism.StrategyName(org.ForagingStrategy)]++
+
+		// Full organisms share the top bucket rather than getting one of their own
+		energyPercent := math.Min(org.Energy/org.EnergyCapacity*100, 100-energyHistogramBucketSize)
+		energyBucket := fmt.Sprintf("%.0f", math.Floor(energyPercent/energyHistogramBucketSize)*energyHistogramBucketSize)
+		stats.EnergyHistogram[energyBucket]++
 	}
 
 	// Calculate averages
@@ -114,6 +176,13 @@ func calculateOrganismStats(organisms []types.Organism, world interface{ GetConc
 	stats.PreferenceExposureRatio = exposureRatioSum / float64(len(organisms))
 	stats.AverageEnergy = energySum / float64(len(organisms))
 	stats.EnergyRatio = energyRatioSum / float64(len(organisms))
+	for generation, count := range generationCounts {
+		stats.EnergyRatioByGeneration[generation] /= float64(count)
+		stats.ExposureRatioByGeneration[generation] /= float64(count)
+	}
+	for strategy := range stats.StrategyShares {
+		stats.StrategyShares[strategy] /= float64(len(organisms))
+	}
 
 	// Calculate standard deviation
 	for _, pref := range preferences {
@@ -125,8 +194,29 @@ func calculateOrganismStats(organisms []types.Organism, world interface{ GetConc
 	return stats
 }
 
+// finiteOrganism reports whether the values statistics are computed from are all
+// finite, including the energy ratio, which needs a positive capacity
+func finiteOrganism(org types.Organism) bool {
+	if org.EnergyCapacity <= 0 {
+		return false
+	}
+	for _, value := range []float64{org.ChemPreference, org.Energy, org.EnergyCapacity, org.Position.X, org.Position.Y} {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return false
+		}
+	}
+	return true
+}
+
 // calculateChemicalStats calculates statistics about chemical concentrations
 func calculateChemicalStats(sources []types.ChemicalSource, world interface{ GetConcentrationAt(types.Point) float64 }, bounds types.Rect) ChemicalStats {
+	if len(sources) == 0 {
+		return ChemicalStats{
+			Empty:                  true,
+			ConcentrationHistogram: make(map[string]int),
+		}
+	}
+
 	stats := ChemicalStats{
 		SourceCount:            len(sources),
 		MinConcentration:       math.MaxFloat64,
@@ -183,12 +273,39 @@ func calculateChemicalStats(sources []types.ChemicalSource, world interface{ Get
 
 // CollectStats collects statistics for the current simulation state
 func (s *Simulator) CollectStats() SimulationStats {
-	return SimulationStats{
+	stats := SimulationStats{
 		Time:            s.Time,
 		RealTimeElapsed: time.Duration(0), // Will be set by caller if needed
 		Organisms:       calculateOrganismStats(s.World.GetOrganisms(), s.World),
 		Chemicals:       calculateChemicalStats(s.World.GetChemicalSources(), s.World, s.World.GetBounds()),
 	}
+	stats.Organisms.DeathsByCause = s.World.DeathsByCause()
+	stats.Chemicals.EnergyByType = s.World.ChemicalEnergyBreakdown()
+	return stats
+}
+
+// statsCSVHeader names the columns of a statistics CSV file
+var statsCSVHeader = []string{
+	"Time",
+	"OrganismCount",
+	"AveragePreference",
+	"PreferenceStdDev",
+	"AverageConcentration",
+	"PreferenceExposureRatio",
+	"MaxConcentration",
+}
+
+// statsCSVRow formats one sample of statistics as a CSV row
+func statsCSVRow(stat SimulationStats) []string {
+	return []string{
+		fmt.Sprintf("%.2f", stat.Time),
+		fmt.Sprintf("%d", stat.Organisms.Count),
+		fmt.Sprintf("%.2f", stat.Organisms.AveragePreference),
+		fmt.Sprintf("%.2f", stat.Organisms.PreferenceStdDev),
+		fmt.Sprintf("%.2f", stat.Organisms.AverageConcentration),
+		fmt.Sprintf("%.2f", stat.Organisms.PreferenceExposureRatio),
+		fmt.Sprintf("%.2f", stat.Chemicals.MaxConcentration),
+	}
 }
 
 // ExportStatsCSV exports a time series of simulation statistics to a CSV file
@@ -205,31 +322,13 @@ func ExportStatsCSV(stats []SimulationStats, filename string) error {
 	defer writer.Flush()
 
 	// Write header
-	header := []string{
-		"Time",
-		"OrganismCount",
-		"AveragePreference",
-		"PreferenceStdDev",
-		"AverageConcentration",
-		"PreferenceExposureRatio",
-		"MaxConcentration",
-	}
-	if err := writer.Write(header); err != nil {
+	if err := writer.Write(statsCSVHeader); err != nil {
 		return err
 	}
 
 	// Write data rows
 	for _, stat := range stats {
-		row := []string{
-			fmt.Sprintf("%.2f", stat.Time),
-			fmt.Sprintf("%d", stat.Organisms.Count),
-			fmt.Sprintf("%.2f", stat.Organisms.AveragePreference),
-			fmt.Sprintf("%.2f", stat.Organisms.PreferenceStdDev),
-			fmt.Sprintf("%.2f", stat.Organisms.AverageConcentration),
-			fmt.Sprintf("%.2f", stat.Organisms.PreferenceExposureRatio),
-			fmt.Sprintf("%.2f", stat.Chemicals.MaxConcentration),
-		}
-		if err := writer.Write(row); err != nil {
+		if err := writer.Write(statsCSVRow(stat)); err != nil {
 			return err
 		}
 	}
@@ -1,10 +1,14 @@
 package simulation
 
 import (
+	"math"
 	"os"
+	"reflect"
 	"testing"
 
+	"github.com/zachbeta/evolve_sim/pkg/organism"
 	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
 )
 
 // mockWorld implements a simple world that returns predefined concentrations
@@ -85,6 +89,11 @@ func TestCalculateOrganismStats(t *testing.T) {
 		}
 	}
 
+	// All three start at 80% of capacity
+	if stats.EnergyHistogram["80"] != 3 {
+		t.Errorf("Expected energy bucket 80 to have count 3, got %d", stats.EnergyHistogram["80"])
+	}
+
 	// Test with empty organisms list
 	emptyStats := calculateOrganismStats([]types.Organism{}, mockWorld)
 	if emptyStats.Count != 0 {
@@ -92,6 +101,113 @@ func TestCalculateOrganismStats(t *testing.T) {
 	}
 }
 
+// TestStatsByGeneration tests that energy and exposure ratios are averaged per generation
+func TestStatsByGeneration(t *testing.T) {
+	// Every organism sits at exactly its preferred concentration
+	world := mockWorld{
+		concentrationFn: func(p types.Point) float64 { return 50.0 },
+	}
+
+	newOrg := func(generation int, energyRatio float64) types.Organism {
+		org := types.NewOrganism(types.Point{X: 10, Y: 10}, 0, 50.0, 1.0, types.DefaultSensorAngles())
+		org.Generation = generation
+		org.Energy = org.EnergyCapacity * energyRatio
+		return org
+	}
+
+	organisms := []types.Organism{
+		newOrg(1, 0.2),
+		newOrg(1, 0.4),
+		newOrg(2, 0.7),
+		newOrg(2, 0.9),
+	}
+
+	stats := calculateOrganismStats(organisms, world)
+
+	want := map[int]float64{1: 0.3, 2: 0.8}
+	if len(stats.EnergyRatioByGeneration) != len(want) {
+		t.Fatalf("EnergyRatioByGeneration = %v; want generations %v", stats.EnergyRatioByGeneration, want)
+	}
+	for generation, ratio := range want {
+		if got := stats.EnergyRatioByGeneration[generation]; math.Abs(got-ratio) > 1e-9 {
+			t.Errorf("EnergyRatioByGeneration[%d] = %v; want %v", generation, got, ratio)
+		}
+		if got := stats.ExposureRatioByGeneration[generation]; math.Abs(got-1.0) > 1e-9 {
+			t.Errorf("ExposureRatioByGeneration[%d] = %v; want 1", generation, got)
+		}
+	}
+}
+
+// TestStatsSkipNonFiniteOrganisms tests that a corrupted organism doesn't poison the averages
+func TestStatsSkipNonFiniteOrganisms(t *testing.T) {
+	world := mockWorld{
+		concentrationFn: func(p types.Point) float64 { return 40.0 },
+	}
+
+	healthy := types.NewOrganism(types.Point{X: 10, Y: 10}, 0, 40.0, 1.0, types.DefaultSensorAngles())
+	alsoHealthy := types.NewOrganism(types.Point{X: 20, Y: 10}, 0, 60.0, 1.0, types.DefaultSensorAngles())
+	corrupted := types.NewOrganism(types.Point{X: 30, Y: 10}, 0, math.NaN(), 1.0, types.DefaultSensorAngles())
+	drained := types.NewOrganism(types.Point{X: 40, Y: 10}, 0, 50.0, 1.0, types.DefaultSensorAngles())
+	drained.EnergyCapacity = 0
+
+	stats := calculateOrganismStats([]types.Organism{healthy, corrupted, alsoHealthy, drained}, world)
+
+	if stats.NonFiniteCount != 2 {
+		t.Errorf("NonFiniteCount = %d; want 2", stats.NonFiniteCount)
+	}
+	if stats.Count != 2 {
+		t.Errorf("Count = %d; want the 2 valid organisms", stats.Count)
+	}
+	if stats.AveragePreference != 50.0 {
+		t.Errorf("AveragePreference = %v; want 50 from the valid organisms", stats.AveragePreference)
+	}
+	for name, value := range map[string]float64{
+		"PreferenceStdDev":        stats.PreferenceStdDev,
+		"EnergyRatio":             stats.EnergyRatio,
+		"PreferenceExposureRatio": stats.PreferenceExposureRatio,
+	} {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			t.Errorf("%s = %v; want a finite value", name, value)
+		}
+	}
+}
+
+// TestWallStuckCount tests that an organism bouncing off the same wall is counted
+func TestWallStuckCount(t *testing.T) {
+	world := mockWorld{
+		concentrationFn: func(p types.Point) float64 { return 0 },
+	}
+	bounds := types.NewRect(0, 0, 100, 100)
+
+	bouncer := types.NewOrganism(types.Point{X: 99.5, Y: 50}, 0, 50.0, 1.0, types.DefaultSensorAngles())
+	roamer := types.NewOrganism(types.Point{X: 20, Y: 50}, 0, 50.0, 1.0, types.DefaultSensorAngles())
+
+	for i := 0; i < organism.WallStuckHits; i++ {
+		// Keep steering the bouncer back into the east wall
+		bouncer.Heading = 0
+		organism.Move(&bouncer, bounds, 0.5)
+		organism.Move(&roamer, bounds, 0.5)
+
+		stats := calculateOrganismStats([]types.Organism{bouncer, roamer}, world)
+		want := 0
+		if i+1 >= organism.WallStuckHits {
+			want = 1
+		}
+		if stats.WallStuckCount != want {
+			t.Errorf("After %d collisions WallStuckCount = %d; want %d", i+1, stats.WallStuckCount, want)
+		}
+	}
+
+	// A single collision long ago no longer counts
+	for i := 0; i < 10; i++ {
+		bouncer.Heading = math.Pi
+		organism.Move(&bouncer, bounds, 0.5)
+	}
+	if stats := calculateOrganismStats([]types.Organism{bouncer}, world); stats.WallStuckCount != 0 {
+		t.Errorf("After leaving the wall WallStuckCount = %d; want 0", stats.WallStuckCount)
+	}
+}
+
 // TestCalculateChemicalStats tests the chemical statistics calculation
 func TestCalculateChemicalStats(t *testing.T) {
 	// Create a bounded test area
@@ -249,3 +365,94 @@ func TestExportStatsJSON(t *testing.T) {
 		t.Errorf("Expected non-empty JSON file")
 	}
 }
+
+func TestStatsDeathsByCause(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Organism.Count = 0
+	cfg.Chemical.Count = 0
+	w := world.NewWorld(cfg)
+
+	starving := types.NewOrganism(types.Point{X: 25, Y: 50}, 0, 30.0, 1.0, types.DefaultSensorAngles())
+	starving.Energy = 1e-9
+	w.AddOrganism(starving)
+
+	elder := types.NewOrganism(types.Point{X: 75, Y: 50}, 0, 30.0, 1.0, types.DefaultSensorAngles())
+	elder.Energy = elder.EnergyCapacity * 0.5
+	elder.MaxAge = 10
+	elder.Age = 10
+	w.AddOrganism(elder)
+
+	survivor := types.NewOrganism(types.Point{X: 50, Y: 25}, 0, 30.0, 1.0, types.DefaultSensorAngles())
+	survivor.Energy = survivor.EnergyCapacity * 0.5
+	survivor.MaxAge = 10
+	w.AddOrganism(survivor)
+
+	sim := NewSimulator(w, cfg)
+	sim.Step()
+
+	stats := sim.CollectStats()
+	want := map[types.DeathCause]int{
+		types.DeathCauseStarvation: 1,
+		types.DeathCauseOldAge:     1,
+	}
+	if !reflect.DeepEqual(stats.Organisms.DeathsByCause, want) {
+		t.Errorf("DeathsByCause = %v; want %v", stats.Organisms.DeathsByCause, want)
+	}
+	if stats.Organisms.Count != 1 {
+		t.Errorf("Organism count = %d; want only the young, fed organism left", stats.Organisms.Count)
+	}
+}
+
+// TestStatsEmptyWorld checks that a world with no organisms and no sources
+// reports finite, explicitly empty statistics
+func TestStatsEmptyWorld(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Organism.Count = 0
+	cfg.Chemical.Count = 0
+	sim := NewSimulator(world.NewWorld(cfg), cfg)
+	sim.Step()
+
+	stats := sim.CollectStats()
+	if !stats.Organisms.Empty {
+		t.Error("Expected organism stats to be marked empty")
+	}
+	if !stats.Chemicals.Empty {
+		t.Error("Expected chemical stats to be marked empty")
+	}
+
+	values := map[string]float64{
+		"AveragePreference":       stats.Organisms.AveragePreference,
+		"PreferenceStdDev":        stats.Organisms.PreferenceStdDev,
+		"MinPreference":           stats.Organisms.MinPreference,
+		"MaxPreference":           stats.Organisms.MaxPreference,
+		"PreferenceExposureRatio": stats.Organisms.PreferenceExposureRatio,
+		"AverageEnergy":           stats.Organisms.AverageEnergy,
+		"EnergyRatio":             stats.Organisms.EnergyRatio,
+		"AverageConcentration":    stats.Chemicals.AverageConcentration,
+		"MinConcentration":        stats.Chemicals.MinConcentration,
+		"MaxConcentration":        stats.Chemicals.MaxConcentration,
+	}
+	for name, value := range values {
+		if value != 0 {
+			t.Errorf("%s = %v; want 0 for an empty world", name, value)
+		}
+	}
+	if len(stats.Chemicals.ConcentrationHistogram) != 0 {
+		t.Errorf("Expected no concentration histogram buckets, got %v", stats.Chemicals.ConcentrationHistogram)
+	}
+	if len(stats.Organisms.PreferenceHistogram) != 0 || len(stats.Organisms.EnergyHistogram) != 0 {
+		t.Error("Expected no organism histogram buckets")
+	}
+
+	// A populated world must not be marked empty
+	populated := calculateChemicalStats([]types.ChemicalSource{{Strength: 1}}, mockWorld{
+		concentrationFn: func(types.Point) float64 { return 1 },
+	}, types.Rect{})
+	if populated.Empty {
+		t.Error("Expected stats with a source not to be marked empty")
+	}
+	if math.IsInf(populated.MinConcentration, 0) || math.IsInf(populated.MaxConcentration, 0) {
+		t.Errorf("Expected finite concentration range for zero-size bounds, got [%v, %v]",
+			populated.MinConcentration, populated.MaxConcentration)
+	}
+}
@@ -0,0 +1,110 @@
+package simulation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// Timelapse saves numbered SVG frames of the world at regular step intervals,
+// each annotated with the generation, population and simulation time
+type Timelapse struct {
+	Dir      string // Directory the frames are written to
+	Interval int64  // Steps between frames
+	Frames   int    // Number of frames to capture
+	captured int
+}
+
+// NewTimelapse creates dir if needed and returns a timelapse that captures frames
+// evenly spaced over a run of totalSteps steps
+func NewTimelapse(dir string, totalSteps int64, frames int) (*Timelapse, error) {
+	if frames <= 0 {
+		return nil, fmt.Errorf("timelapse needs at least one frame, got %d", frames)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &Timelapse{
+		Dir:      dir,
+		Interval: max(totalSteps/int64(frames), 1),
+		Frames:   frames,
+	}, nil
+}
+
+// Captured returns the number of frames written so far
+func (t *Timelapse) Captured() int {
+	return t.captured
+}
+
+// Capture writes the next frame if the simulator has reached the next interval
+// and the requested number of frames has not been written yet
+func (t *Timelapse) Capture(s *Simulator) error {
+	if t.captured >= t.Frames || s.StepCount%t.Interval != 0 {
+		return nil
+	}
+
+	filename := filepath.Join(t.Dir, fmt.Sprintf("frame_%05d.svg", t.captured+1))
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := WriteFrameSVG(file, s); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	t.captured++
+	return nil
+}
+
+// WriteFrameSVG draws the world as an SVG image: chemical sources as translucent
+// circles sized by their effective radius, organisms as dots colored from blue
+// (low preference) to red (high), and a caption with the current statistics
+func WriteFrameSVG(w io.Writer, s *Simulator) error {
+	snapshot := s.World.Snapshot()
+	bounds := s.World.GetBounds()
+
+	generation, minPref, maxPref := 0, math.Inf(1), math.Inf(-1)
+	for _, org := range snapshot.Organisms {
+		generation = max(generation, org.Generation)
+		minPref = math.Min(minPref, org.ChemPreference)
+		maxPref = math.Max(maxPref, org.ChemPreference)
+	}
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%g %g %g %g" width="%g" height="%g">`+"\n",
+		bounds.X, bounds.Y, bounds.Width, bounds.Height, bounds.Width, bounds.Height)
+	fmt.Fprintf(out, `<rect x="%g" y="%g" width="%g" height="%g" fill="black"/>`+"\n",
+		bounds.X, bounds.Y, bounds.Width, bounds.Height)
+
+	for _, source := range snapshot.ChemicalSources {
+		if !source.IsActive {
+			continue
+		}
+		fmt.Fprintf(out, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="green" fill-opacity="0.2"/>`+"\n",
+			source.Position.X, source.Position.Y, source.EffectiveRadius())
+	}
+
+	for _, org := range snapshot.Organisms {
+		t := 0.5
+		if maxPref > minPref {
+			t = (org.ChemPreference - minPref) / (maxPref - minPref)
+		}
+		fmt.Fprintf(out, `<circle cx="%.1f" cy="%.1f" r="3" fill="rgb(%d,64,%d)"/>`+"\n",
+			org.Position.X, org.Position.Y, int(t*255), int((1-t)*255))
+	}
+
+	fmt.Fprintf(out, `<text x="%g" y="%g" fill="white" font-family="monospace" font-size="16">`+
+		"Generation: %d  Population: %d  Time: %.1fs</text>\n",
+		bounds.X+10, bounds.Y+24, generation, len(snapshot.Organisms), s.Time)
+	fmt.Fprintln(out, "</svg>")
+
+	return out.Flush()
+}
@@ -0,0 +1,54 @@
+package simulation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+func TestTimelapseWritesRequestedFrames(t *testing.T) {
+	cfg := createTestConfig()
+	sim := NewSimulator(world.NewWorld(cfg), cfg)
+
+	const steps, frames = 120, 5
+	dir := filepath.Join(t.TempDir(), "frames")
+	timelapse, err := NewTimelapse(dir, steps, frames)
+	if err != nil {
+		t.Fatalf("NewTimelapse() error = %v", err)
+	}
+
+	for sim.StepCount < steps {
+		sim.Step()
+		if err := timelapse.Capture(sim); err != nil {
+			t.Fatalf("Capture() error = %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != frames || timelapse.Captured() != frames {
+		t.Fatalf("Frames written = %d (Captured() = %d); want %d", len(entries), timelapse.Captured(), frames)
+	}
+
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		frame := string(data)
+		if !strings.HasPrefix(frame, "<svg") || !strings.Contains(frame, "Generation: ") || !strings.Contains(frame, "Population: ") {
+			t.Errorf("Frame %s is not an annotated SVG image:\n%.200s", entry.Name(), frame)
+		}
+	}
+}
+
+func TestNewTimelapseRejectsNoFrames(t *testing.T) {
+	if _, err := NewTimelapse(t.TempDir(), 100, 0); err == nil {
+		t.Error("NewTimelapse() with 0 frames: want an error")
+	}
+}
@@ -0,0 +1,91 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+// Scripted event actions
+const (
+	TimelineAddSource        = "addSource"        // Add a source at Position with Strength and DecayFactor
+	TimelineRemoveAllSources = "removeAllSources" // Remove every chemical source
+	TimelineSetStrength      = "setStrength"      // Set every source's strength to Strength
+)
+
+// ScriptedEvent is an environmental change applied once simulation time reaches Time
+type ScriptedEvent struct {
+	Time        float64     `json:"time"`
+	Action      string      `json:"action"`
+	Position    types.Point `json:"position"`
+	Strength    float64     `json:"strength"`
+	DecayFactor float64     `json:"decayFactor"`
+}
+
+// EventTimeline is a schedule of scripted environmental changes, for reproducible
+// perturbation experiments. Each event fires once, in time order.
+type EventTimeline struct {
+	Events []ScriptedEvent `json:"events"`
+	next   int             // Index of the first event that hasn't fired yet
+}
+
+// NewEventTimeline creates a timeline from events, sorting them by time
+func NewEventTimeline(events []ScriptedEvent) (*EventTimeline, error) {
+	for _, event := range events {
+		switch event.Action {
+		case TimelineAddSource, TimelineRemoveAllSources, TimelineSetStrength:
+		default:
+			return nil, fmt.Errorf("unknown timeline action %q at time %v", event.Action, event.Time)
+		}
+	}
+
+	sorted := make([]ScriptedEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time < sorted[j].Time })
+	return &EventTimeline{Events: sorted}, nil
+}
+
+// LoadEventTimeline reads a timeline from a JSON file
+func LoadEventTimeline(filename string) (*EventTimeline, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeline EventTimeline
+	if err := json.Unmarshal(data, &timeline); err != nil {
+		return nil, err
+	}
+	return NewEventTimeline(timeline.Events)
+}
+
+// Rewind makes every event pending again
+func (t *EventTimeline) Rewind() {
+	t.next = 0
+}
+
+// Due returns the events that have not fired yet and are scheduled at or before
+// currentTime, marking them as fired
+func (t *EventTimeline) Due(currentTime float64) []ScriptedEvent {
+	start := t.next
+	for t.next < len(t.Events) && t.Events[t.next].Time <= currentTime {
+		t.next++
+	}
+	return t.Events[start:t.next]
+}
+
+// apply performs the event's action on the world
+func (e ScriptedEvent) apply(w *world.World) {
+	switch e.Action {
+	case TimelineAddSource:
+		w.AddChemicalSource(types.NewChemicalSource(e.Position, e.Strength, e.DecayFactor))
+	case TimelineRemoveAllSources:
+		w.RemoveAllChemicalSources()
+	case TimelineSetStrength:
+		w.SetChemicalSourceStrength(e.Strength)
+	}
+}
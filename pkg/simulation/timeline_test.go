@@ -0,0 +1,53 @@
+package simulation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+func TestTimelineEventFiresOnce(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Organism.Count = 0
+	cfg.Chemical.Count = 0
+	cfg.Chemical.RegenerationEnabled = false
+	sim := NewSimulator(world.NewWorld(cfg), cfg)
+
+	path := filepath.Join(t.TempDir(), "timeline.json")
+	script := `{"events": [{"time": 10, "action": "addSource", "position": {"X": 50, "Y": 50}, "strength": 100, "decayFactor": 0.01}]}`
+	if err := os.WriteFile(path, []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	timeline, err := LoadEventTimeline(path)
+	if err != nil {
+		t.Fatalf("LoadEventTimeline() error = %v", err)
+	}
+	sim.Timeline = timeline
+
+	// Step the simulator well past t=10, tracking when the source appears
+	firedAt := -1.0
+	for sim.Time < 20 {
+		startTime := sim.Time
+		sim.Step()
+		sources := len(sim.World.GetChemicalSources())
+		if sources > 1 {
+			t.Fatalf("Got %d sources at t=%v; want the event to fire once", sources, sim.Time)
+		}
+		if sources == 1 && firedAt < 0 {
+			firedAt = startTime
+		}
+	}
+
+	if firedAt < 10 || firedAt >= 10+sim.TimeStep*sim.SimulationSpeed {
+		t.Errorf("Event fired in the step starting at t=%v; want the first step at or after t=10", firedAt)
+	}
+}
+
+func TestNewEventTimelineRejectsUnknownAction(t *testing.T) {
+	if _, err := NewEventTimeline([]ScriptedEvent{{Time: 1, Action: "flood"}}); err == nil {
+		t.Error("Expected an error for an unknown action")
+	}
+}
@@ -0,0 +1,141 @@
+package simulation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+// TournamentGroup is one side of a strategy tournament: the founders given a
+// foraging strategy, identified by lineage so their descendants count too
+type TournamentGroup struct {
+	Strategy string
+	Roots    map[int64]bool // Root IDs of the group's founders
+}
+
+// TournamentSample is each group's share of the population at a moment of the run
+type TournamentSample struct {
+	Time       float64
+	Population int
+	Shares     [2]float64
+}
+
+// Tournament pits two equal groups of founders with different foraging strategies
+// against each other, tracking which group's lineages take over the population
+type Tournament struct {
+	Groups  [2]TournamentGroup
+	Samples []TournamentSample
+}
+
+// ParseTournament reads a "strategyA,strategyB" pair of foraging strategy names
+func ParseTournament(spec string) (string, string, error) {
+	names := strings.Split(spec, ",")
+	if len(names) != 2 {
+		return "", "", fmt.Errorf("tournament needs two comma-separated strategies, got %q", spec)
+	}
+
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+		switch names[i] {
+		case config.ForagingStrategyClosestPreference, config.ForagingStrategyLockOn:
+		default:
+			return "", "", fmt.Errorf("unknown foraging strategy %q, want %q or %q",
+				names[i], config.ForagingStrategyClosestPreference, config.ForagingStrategyLockOn)
+		}
+	}
+	return names[0], names[1], nil
+}
+
+// NewTournament splits the world's organisms into two equal groups, alternating by
+// index, and gives the first group strategyA and the second strategyB
+func NewTournament(w *world.World, strategyA, strategyB string) *Tournament {
+	t := &Tournament{Groups: [2]TournamentGroup{
+		{Strategy: strategyA, Roots: make(map[int64]bool)},
+		{Strategy: strategyB, Roots: make(map[int64]bool)},
+	}}
+
+	organisms := w.GetOrganisms()
+	for i := range organisms {
+		group := &t.Groups[i%2]
+		organisms[i].ForagingStrategy = group.Strategy
+		group.Roots[organisms[i].RootAncestor()] = true
+	}
+	w.UpdateOrganisms(organisms)
+
+	return t
+}
+
+// Shares returns each group's share of the organisms. Organisms from neither group,
+// such as ones added after the tournament started, count toward neither share.
+func (t *Tournament) Shares(organisms []types.Organism) [2]float64 {
+	var shares [2]float64
+	if len(organisms) == 0 {
+		return shares
+	}
+
+	for _, org := range organisms {
+		root := org.RootAncestor()
+		for g, group := range t.Groups {
+			if group.Roots[root] {
+				shares[g]++
+				break
+			}
+		}
+	}
+	for g := range shares {
+		shares[g] /= float64(len(organisms))
+	}
+	return shares
+}
+
+// Record samples each group's current share of the simulator's population
+func (t *Tournament) Record(s *Simulator) TournamentSample {
+	organisms := s.World.GetOrganisms()
+	sample := TournamentSample{
+		Time:       s.Time,
+		Population: len(organisms),
+		Shares:     t.Shares(organisms),
+	}
+	t.Samples = append(t.Samples, sample)
+	return sample
+}
+
+// Leader returns the index of the group with the larger share in the last sample,
+// or -1 if there are no samples or the shares are tied
+func (t *Tournament) Leader() int {
+	if len(t.Samples) == 0 {
+		return -1
+	}
+
+	shares := t.Samples[len(t.Samples)-1].Shares
+	switch {
+	case shares[0] > shares[1]:
+		return 0
+	case shares[1] > shares[0]:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// RunTournament runs a seeded headless tournament between strategyA and strategyB
+// for steps steps, sampling the groups' shares at the start, every sampleInterval
+// steps, and at the end
+func RunTournament(cfg config.SimulationConfig, strategyA, strategyB string, steps, sampleInterval int64) *Tournament {
+	simulator := NewSimulator(world.NewWorld(cfg), cfg)
+	tournament := NewTournament(simulator.World, strategyA, strategyB)
+
+	tournament.Record(simulator)
+	for simulator.StepCount < steps {
+		simulator.Step()
+		if sampleInterval > 0 && simulator.StepCount%sampleInterval == 0 && simulator.StepCount < steps {
+			tournament.Record(simulator)
+		}
+	}
+	tournament.Record(simulator)
+
+	return tournament
+}
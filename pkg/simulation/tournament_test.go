@@ -0,0 +1,88 @@
+package simulation
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/world"
+)
+
+func TestParseTournament(t *testing.T) {
+	tests := []struct {
+		spec    string
+		a, b    string
+		wantErr bool
+	}{
+		{"lockOn,closestPreference", config.ForagingStrategyLockOn, config.ForagingStrategyClosestPreference, false},
+		{" lockOn , lockOn ", config.ForagingStrategyLockOn, config.ForagingStrategyLockOn, false},
+		{"lockOn", "", "", true},
+		{"lockOn,wander", "", "", true},
+		{"lockOn,lockOn,lockOn", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			a, b, err := ParseTournament(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTournament(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if a != tt.a || b != tt.b {
+				t.Errorf("ParseTournament(%q) = %q, %q; want %q, %q", tt.spec, a, b, tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestTournamentIdenticalStrategiesDoNotDominate(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	const seeds = 6
+	totalShareA := 0.0
+	for seed := int64(1); seed <= seeds; seed++ {
+		cfg.RandomSeed = seed
+		tournament := RunTournament(cfg, config.ForagingStrategyLockOn, config.ForagingStrategyLockOn, 1800, 300)
+
+		start := tournament.Samples[0]
+		if start.Shares != [2]float64{0.5, 0.5} {
+			t.Fatalf("Seed %d: starting shares = %v; want two equal groups", seed, start.Shares)
+		}
+
+		final := tournament.Samples[len(tournament.Samples)-1]
+		if final.Population > 0 && math.Abs(final.Shares[0]+final.Shares[1]-1) > 1e-9 {
+			t.Errorf("Seed %d: final shares %v do not cover the population", seed, final.Shares)
+		}
+		t.Logf("Seed %d: %d samples, final population %d, shares %v", seed, len(tournament.Samples), final.Population, final.Shares)
+		totalShareA += final.Shares[0]
+	}
+
+	// Equal strategies should split the population about evenly across seeds
+	if meanShareA := totalShareA / seeds; meanShareA < 0.3 || meanShareA > 0.7 {
+		t.Errorf("Mean final share of group A = %.2f; identical strategies should not systematically dominate", meanShareA)
+	}
+}
+
+func TestTournamentIsReproducible(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RandomSeed = 7
+
+	first := RunTournament(cfg, config.ForagingStrategyLockOn, config.ForagingStrategyClosestPreference, 600, 200)
+	second := RunTournament(cfg, config.ForagingStrategyLockOn, config.ForagingStrategyClosestPreference, 600, 200)
+	if !reflect.DeepEqual(first.Samples, second.Samples) {
+		t.Errorf("Seeded tournaments differ:\n%v\n%v", first.Samples, second.Samples)
+	}
+}
+
+func TestStatsStrategyShares(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RandomSeed = 1
+	sim := NewSimulator(world.NewWorld(cfg), cfg)
+	NewTournament(sim.World, config.ForagingStrategyLockOn, config.ForagingStrategyClosestPreference)
+
+	shares := sim.CollectStats().Organisms.StrategyShares
+	want := map[string]float64{config.ForagingStrategyLockOn: 0.5, config.ForagingStrategyClosestPreference: 0.5}
+	if !reflect.DeepEqual(shares, want) {
+		t.Errorf("StrategyShares = %v; want %v", shares, want)
+	}
+}
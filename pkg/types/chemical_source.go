@@ -8,15 +8,20 @@ import (
 type ChemicalSource struct {
 	Position    Point   // The position of the chemical source
 	Strength    float64 // The strength/concentration at the source
-	DecayFactor float64 // How quickly the concentration decays with distance
+	DecayFactor float64 // How quickly the concentration decays with distance (0 gives a uniform field; negative is treated as 0)
 
 	// New fields for energy balance
 	Energy        float64 // Current energy level of the source
 	MaxEnergy     float64 // Maximum energy capacity
 	DepletionRate float64 // Base rate at which the source depletes (per second)
 	IsActive      bool    // Whether the source is currently active
+
+	Type int // Chemical type the source emits; every source is currently DefaultChemicalType
 }
 
+// DefaultChemicalType is the type of chemical sources that don't set one
+const DefaultChemicalType = 0
+
 // NewChemicalSource creates a new chemical source with the given parameters
 func NewChemicalSource(position Point, strength, decayFactor float64) ChemicalSource {
 	maxEnergy := strength * 1000 // Scale max energy with strength
@@ -32,7 +37,8 @@ func NewChemicalSource(position Point, strength, decayFactor float64) ChemicalSo
 	}
 }
 
-// GetConcentrationAt calculates the chemical concentration at a given point
+// GetConcentrationAt calculates the chemical concentration at a given point.
+// A source with no decay produces its full strength everywhere.
 func (cs ChemicalSource) GetConcentrationAt(point Point) float64 {
 	// If source is inactive, it produces no concentration
 	if !cs.IsActive {
@@ -49,9 +55,7 @@ func (cs ChemicalSource) GetConcentrationAt(point Point) float64 {
 
 	// Early exit for distant points (optimization)
 	// If distance is too great, concentration will be negligible
-	// This threshold is based on decay factor and source strength
-	maxEffectiveDistance := math.Sqrt(cs.Strength / (0.001 * cs.DecayFactor))
-	if dist > maxEffectiveDistance {
+	if dist > cs.EffectiveRadius() {
 		return 0
 	}
 
@@ -61,7 +65,7 @@ func (cs ChemicalSource) GetConcentrationAt(point Point) float64 {
 	}
 
 	// Calculate concentration using inverse square law with decay factor
-	concentration := cs.Strength / (1.0 + dist*dist*cs.DecayFactor)
+	concentration := cs.Strength / (1.0 + dist*dist*cs.decay())
 
 	// Scale by energy percentage
 	energyRatio := cs.Energy / cs.MaxEnergy
@@ -69,6 +73,40 @@ func (cs ChemicalSource) GetConcentrationAt(point Point) float64 {
 	return concentration * energyRatio
 }
 
+// GetGradientAt returns the analytic gradient of the source's concentration at a
+// point, pointing uphill toward the source. It is zero where GetConcentrationAt
+// treats the concentration as flat: beyond the effective radius and at the source.
+func (cs ChemicalSource) GetGradientAt(point Point) Point {
+	if !cs.IsActive || cs.Strength <= 0 {
+		return Point{}
+	}
+
+	dist := cs.Position.DistanceTo(point)
+	if dist > cs.EffectiveRadius() || dist < 1e-9 {
+		return Point{}
+	}
+
+	// d/dp of Strength*energyRatio/(1 + |p-source|^2*decay)
+	denominator := 1.0 + dist*dist*cs.decay()
+	scale := -2 * cs.Strength * (cs.Energy / cs.MaxEnergy) * cs.decay() / (denominator * denominator)
+	return Point{
+		X: scale * (point.X - cs.Position.X),
+		Y: scale * (point.Y - cs.Position.Y),
+	}
+}
+
+// EffectiveRadius returns the distance beyond which the source's concentration is
+// treated as negligible. This threshold is based on decay factor and source strength.
+func (cs ChemicalSource) EffectiveRadius() float64 {
+	return math.Sqrt(cs.Strength / (0.001 * cs.decay()))
+}
+
+// decay returns the decay factor clamped to be non-negative, since negative decay
+// would make the concentration grow with distance and blow up
+func (cs ChemicalSource) decay() float64 {
+	return math.Max(0, cs.DecayFactor)
+}
+
 // Update updates the energy level of the chemical source
 func (cs *ChemicalSource) Update(deltaTime float64, worldEnergy *float64) {
 	// Skip inactive sources
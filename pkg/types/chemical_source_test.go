@@ -101,6 +101,51 @@ func TestChemicalSourceEdgeCases(t *testing.T) {
 		t.Errorf("Concentration with zero decay factor = %v; want %v",
 			actualConcentration, expectedConcentration)
 	}
+
+	// Negative decay is clamped to zero rather than growing with distance
+	csNegativeDecay := NewChemicalSource(NewPoint(0, 0), 100.0, -0.5)
+	for _, point := range []Point{NewPoint(1, 1), NewPoint(100, 100), NewPoint(1000, 0)} {
+		concentration := csNegativeDecay.GetConcentrationAt(point)
+		if math.Abs(concentration-expectedConcentration) > 1e-9 {
+			t.Errorf("Concentration at %v with negative decay factor = %v; want %v",
+				point, concentration, expectedConcentration)
+		}
+	}
+}
+
+func TestChemicalSourceGetGradientAt(t *testing.T) {
+	cs := NewChemicalSource(NewPoint(0, 0), 100.0, 0.1)
+	cs.Energy = cs.MaxEnergy / 2
+
+	// Matches a central finite difference of the concentration and points uphill
+	const h = 1e-4
+	for _, point := range []Point{NewPoint(3, 0), NewPoint(-2, 5), NewPoint(10, 10)} {
+		got := cs.GetGradientAt(point)
+		want := Point{
+			X: (cs.GetConcentrationAt(NewPoint(point.X+h, point.Y)) - cs.GetConcentrationAt(NewPoint(point.X-h, point.Y))) / (2 * h),
+			Y: (cs.GetConcentrationAt(NewPoint(point.X, point.Y+h)) - cs.GetConcentrationAt(NewPoint(point.X, point.Y-h))) / (2 * h),
+		}
+		if math.Abs(got.X-want.X) > 1e-6 || math.Abs(got.Y-want.Y) > 1e-6 {
+			t.Errorf("Gradient at %v = %v; want about %v", point, got, want)
+		}
+		if got.X*point.X+got.Y*point.Y >= 0 {
+			t.Errorf("Gradient at %v = %v; want it pointing toward the source", point, got)
+		}
+	}
+
+	// Flat at the source, beyond its reach and once inactive
+	beyond := NewPoint(cs.EffectiveRadius()+1, 0)
+	inactive := cs
+	inactive.IsActive = false
+	for name, got := range map[string]Point{
+		"at the source":    cs.GetGradientAt(cs.Position),
+		"beyond its reach": cs.GetGradientAt(beyond),
+		"inactive source":  inactive.GetGradientAt(NewPoint(3, 0)),
+	} {
+		if got != (Point{}) {
+			t.Errorf("Gradient %s = %v; want zero", name, got)
+		}
+	}
 }
 
 func TestChemicalSourceDepletion(t *testing.T) {
@@ -8,29 +8,71 @@ import (
 // MaxTrailLength defines the maximum number of positions to store in the trail
 const MaxTrailLength = 30
 
+// TrailPoint is a recorded trail position along with the organism's state there
+type TrailPoint struct {
+	Position Point   // Where the organism was
+	Energy   float64 // Energy as a fraction of capacity at the time (0-1)
+}
+
 // Constants for reproduction
 const (
 	ReproductionThreshold = 0.75 // Percentage of max energy required to reproduce
 	ReproductionCooldown  = 5.0  // Seconds between reproduction attempts
-	OffspringEnergyRatio  = 0.3  // Portion of parent's energy given to offspring
+	OffspringEnergyRatio  = 0.3  // Portion of parent's energy given to offspring by founders
 	MutationFactorSmall   = 0.05 // For small mutations (like preferences)
 	MutationFactorMedium  = 0.1  // For medium mutations (like speed)
 	MutationFactorLarge   = 0.2  // For large mutations (like sensor distance)
+	MaxTurnBias           = 1.0  // Maximum magnitude of the turn bias trait
+)
+
+// Bounds for the reproduction investment trait
+const (
+	MinReproductionInvestment = 0.1 // Smallest fraction of energy given to each offspring
+	MaxReproductionInvestment = 0.6 // Largest fraction of energy given to each offspring
+)
+
+// Bounds and scale of the body size trait
+const (
+	DefaultSize          = 1.0  // Body size of organisms without the trait set
+	MinSize              = 0.25 // Smallest body size mutation can reach
+	MaxSize              = 4.0  // Largest body size mutation can reach
+	FeedingRadiusPerSize = 5.0  // Feeding radius in world units of an organism of size 1
+)
+
+// Fallbacks used when sanitizing traits that have become NaN, infinite or unusable
+const (
+	MinChemPreference  = 0.01  // Smallest usable preference; energy gain divides by it
+	MinSpeed           = 0.1   // Slowest an organism can be, matching reproduction's floor
+	FallbackCapacity   = 100.0 // Energy capacity of an organism with no usable capacity
+	FallbackEfficiency = 1.0   // Neutral energy efficiency multiplier
+)
+
+// FeedingMemorySpacing is the minimum distance between remembered feeding positions,
+// so an organism grazing in one spot fills a single memory slot
+const FeedingMemorySpacing = 10.0
+
+// Constants for the energy reserve
+const (
+	ReserveDepositThreshold  = 0.9 // Active energy ratio above which surplus is stored in the reserve
+	ReserveWithdrawThreshold = 0.5 // Active energy ratio below which the reserve is drawn on
 )
 
 // Organism represents a single-cell organism in the simulation
 type Organism struct {
-	Position              Point      // Current position in the world
-	Heading               float64    // Direction the organism is facing (in radians)
-	PreviousHeading       float64    // Previous heading for smooth rotation animation
-	ChemPreference        float64    // Preferred chemical concentration
-	Speed                 float64    // Movement speed (units per step)
-	SensorAngles          [3]float64 // Angles of sensors relative to heading (front, left, right)
-	PositionHistory       []Point    // History of positions for drawing trails
-	UpdateCounter         int        // Counter to control how often we record position
-	Energy                float64    // Current energy level
-	EnergyCapacity        float64    // Maximum energy capacity
-	TimeSinceReproduction float64    // Time elapsed since last reproduction
+	Position               Point        // Current position in the world
+	Heading                float64      // Direction the organism is facing (in radians)
+	PreviousHeading        float64      // Previous heading for smooth rotation animation
+	ChemPreference         float64      // Preferred chemical concentration
+	Speed                  float64      // Movement speed (units per step)
+	Size                   float64      // Body size; scales feeding radius, movement cost and drawn size (0 means DefaultSize)
+	SensorAngles           [3]float64   // Angles of sensors relative to heading (front, left, right)
+	TurnBias               float64      // Preferred turning side when sensors are ambiguous (-1 left to 1 right)
+	ReproductionInvestment float64      // Fraction of energy given to each offspring (0 uses OffspringEnergyRatio)
+	PositionHistory        []TrailPoint // History of positions and state for drawing trails
+	UpdateCounter          int          // Counter to control how often we record position
+	Energy                 float64      // Current energy level
+	EnergyCapacity         float64      // Maximum energy capacity
+	TimeSinceReproduction  float64      // Time elapsed since last reproduction
 
 	// New energy-related fields
 	MetabolicRate    float64 // Base energy consumption per time unit
@@ -38,23 +80,103 @@ type Organism struct {
 	SensingCost      float64 // Energy cost for sensing operations
 	OptimalGain      float64 // Maximum energy gain in optimal conditions
 	EnergyEfficiency float64 // Multiplier affecting energy consumption
+	HungerSpeedBoost float64 // Extra speed fraction when hungry (0 disables hunger-driven speed)
+	BoundaryCost     float64 // Extra energy lost per wall collision (0 disables)
+
+	// Optional flow-through feeding, where food is only taken in while moving
+	FlowThroughFeeding bool    // Whether energy gain scales with the distance moved each step
+	DistanceMoved      float64 // Distance covered in the last step
+
+	// Optional slow energy reserve that buffers the active pool
+	Reserve             float64 // Energy held in the reserve
+	ReserveCapacity     float64 // Maximum reserve energy (0 disables the reserve)
+	ReserveTransferRate float64 // Maximum energy moved between the pools per second
+
+	// Optional memory of recently fed positions, used to avoid regrazing drained patches
+	FeedingMemory     []Point // Recently fed positions, oldest first
+	FeedingMemorySize int     // Number of positions to remember (0 disables the memory)
+
+	// Optional daily activity cycle, keyed to the world's seasonal food cycle
+	CircadianPhase    float64 // Phase offset in radians; 0 is most active when food peaks
+	CircadianStrength float64 // Fraction by which activity swings around its mean (0 disables)
+
+	// Optional lower bound on sensor spread, so mutation can't leave the organism gradient-blind
+	MinSensorSpread float64 // Minimum angle between the front and each side sensor (0 disables)
+
+	// Optional field of view; sensors angled further from the heading can't read
+	SensorFieldOfView float64 // Largest angle in radians from the heading a sensor reads at (0 disables)
+
+	// Foraging strategy and the state it keeps between steps
+	ForagingStrategy string  // Name of the strategy used to steer (empty uses the default)
+	LockedOn         bool    // Whether the lock-on strategy is following a gradient
+	LockHeading      float64 // Heading the lock-on strategy turns toward and holds while locked on
+	LockBestMatch    float64 // Closest the front sensor has come to the preference while locked on
+
+	// Recent wall collisions, for spotting organisms stuck bouncing at the edge
+	WallHitStreak    int     // Collisions in a row, each soon after the last
+	TimeSinceWallHit float64 // Seconds since the last wall collision
+
+	// Density-dependent dispersal; offspring start calm
+	DispersalTime float64 // Seconds of crowding-triggered scattering remaining
+
+	// Optional within-lifetime learning; the shift is not inherited, but offspring
+	// may be imprinted with the concentration their parent lived in
+	PreferenceShift    float64 // Learned offset added to ChemPreference
+	PlasticityRate     float64 // Fraction per second the preference moves toward fed-on concentrations (0 disables)
+	ImprintStrength    float64 // Fraction of the gap to the parent's local concentration an offspring starts shifted by (0 disables)
+	LocalConcentration float64 // Concentration at the organism's position as of its last energy update
+
+	// Optional tradeoff between gain and cost, so evolution can't improve both at once
+	GainEfficiencyBudget float64 // Fixed ratio of OptimalGain to EnergyEfficiency (0 disables)
+
+	// Whether the cooldown between births grows with the energy invested in each
+	ScaleCooldown bool
+
+	// Optional dormancy to ride out lean periods; offspring start active
+	DormancyThreshold      float64 // Energy fraction of capacity below which the organism may go dormant (0 disables)
+	DormantMetabolicFactor float64 // Fraction of the normal energy drain paid while dormant
+	Dormant                bool    // Whether the organism is currently dormant
+
+	// Optional lifespan; offspring start at age 0
+	Age    float64 // Seconds since birth
+	MaxAge float64 // Age at which the organism dies of old age (0 disables)
 
 	// State flags
-	MarkForRemoval bool  // Flag to mark organism for removal (e.g., when energy depleted)
-	Generation     int   // Generation counter for tracking lineage
-	ID             int64 // Unique identifier
-	ParentID       int64 // ID of parent organism (for tracking lineage)
+	MarkForRemoval bool       // Flag to mark organism for removal (e.g., when energy depleted)
+	DeathCause     DeathCause // Why the organism was marked for removal, empty while alive
+	Generation     int        // Generation counter for tracking lineage
+	ID             int64      // Unique identifier
+	ParentID       int64      // ID of parent organism (for tracking lineage)
+	RootID         int64      // ID of the founding ancestor of this organism's lineage
 }
 
 // OrganismConfig contains all the parameters needed to create a new organism
 type OrganismConfig struct {
-	InitialEnergy         float64    // Starting energy percentage (0.0-1.0 of max capacity)
-	MaximumEnergy         float64    // Base maximum energy capacity
-	BaseMetabolicRate     float64    // Energy consumed per second just existing
-	MovementCostFactor    float64    // Energy cost per unit of movement
-	SensingCostBase       float64    // Energy cost for sensor operations
-	OptimalEnergyGainRate float64    // Maximum energy gain per second
-	EnergyEfficiencyRange [2]float64 // Min/max for random initialization
+	InitialEnergy          float64    // Starting energy as a fraction of max capacity (0.0-1.0)
+	MaximumEnergy          float64    // Base maximum energy capacity
+	BaseMetabolicRate      float64    // Energy consumed per second just existing
+	MovementCostFactor     float64    // Energy cost per unit of movement
+	SensingCostBase        float64    // Energy cost for sensor operations
+	OptimalEnergyGainRate  float64    // Maximum energy gain per second
+	EnergyEfficiencyRange  [2]float64 // Min/max for random initialization
+	HungerSpeedBoost       float64    // Extra speed fraction when hungry (0 disables)
+	BoundaryCollisionCost  float64    // Extra energy lost per wall collision (0 disables)
+	ReserveCapacityRatio   float64    // Reserve capacity as a fraction of energy capacity (0 disables)
+	ReserveTransferRate    float64    // Maximum energy moved between active and reserve pools per second
+	FeedingMemorySize      int        // Number of recently fed positions to remember (0 disables)
+	MinSensorSpread        float64    // Minimum front-to-side sensor angle kept through mutation (0 disables)
+	SensorFieldOfView      float64    // Largest angle from the heading a sensor reads at (0 disables)
+	CircadianStrength      float64    // Fraction by which activity swings with the seasonal cycle (0 disables)
+	GainEfficiencyBudget   float64    // Fixed ratio of optimal gain to efficiency multiplier (0 disables)
+	PlasticityRate         float64    // Fraction per second the preference drifts toward fed-on concentrations (0 disables)
+	ImprintStrength        float64    // Fraction of the gap to the parent's local concentration offspring start shifted by (0 disables)
+	ForagingStrategy       string     // Name of the strategy used to steer (empty uses the default)
+	ScaleCooldown          bool       // Whether the cooldown between births grows with the energy invested in each
+	DormancyThreshold      float64    // Energy fraction of capacity below which the organism may go dormant (0 disables)
+	DormantMetabolicFactor float64    // Fraction of the normal energy drain paid while dormant
+	MaxAge                 float64    // Age in seconds at which organisms die of old age (0 disables)
+	FlowThroughFeeding     bool       // Whether energy gain scales with the distance moved each step
+	InitialSize            float64    // Body size of new organisms (0 uses DefaultSize)
 }
 
 // NewOrganismWithConfig creates a new organism with the given parameters and energy configuration
@@ -65,39 +187,88 @@ func NewOrganismWithConfig(
 	speed float64,
 	sensorAngles [3]float64,
 	config OrganismConfig,
+) Organism {
+	return NewOrganismWithRand(GlobalRand, position, heading, chemPreference, speed, sensorAngles, config)
+}
+
+// NewOrganismWithRand creates a new organism like NewOrganismWithConfig, drawing
+// its efficiency and ID from rng so seeded runs create identical organisms
+func NewOrganismWithRand(
+	rng *rand.Rand,
+	position Point,
+	heading,
+	chemPreference,
+	speed float64,
+	sensorAngles [3]float64,
+	config OrganismConfig,
 ) Organism {
 	// Calculate energy capacity based on base value and speed
 	energyCapacity := config.MaximumEnergy + speed*10.0
 
 	// Randomize energy efficiency within the configured range
 	efficiencyRange := config.EnergyEfficiencyRange
-	efficiency := efficiencyRange[0] + rand.Float64()*(efficiencyRange[1]-efficiencyRange[0])
+	efficiency := efficiencyRange[0] + rng.Float64()*(efficiencyRange[1]-efficiencyRange[0])
+	gain, efficiency := BalanceGainEfficiency(config.OptimalEnergyGainRate, efficiency, config.GainEfficiencyBudget)
+
+	// Size is independent of speed, which alone sets the capacity
+	size := config.InitialSize
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	id := rng.Int63()
 
 	return Organism{
-		Position:              position,
-		Heading:               heading,
-		PreviousHeading:       heading, // Initialize previous heading to current heading
-		ChemPreference:        chemPreference,
-		Speed:                 speed,
-		SensorAngles:          sensorAngles,
-		PositionHistory:       make([]Point, 0, MaxTrailLength),
-		UpdateCounter:         0,
-		Energy:                energyCapacity * config.InitialEnergy, // Set based on config
-		EnergyCapacity:        energyCapacity,
-		TimeSinceReproduction: 0,
+		Position:               position,
+		Heading:                heading,
+		PreviousHeading:        heading, // Initialize previous heading to current heading
+		ChemPreference:         chemPreference,
+		Speed:                  speed,
+		Size:                   size,
+		SensorAngles:           sensorAngles,
+		ReproductionInvestment: OffspringEnergyRatio,
+		PositionHistory:        make([]TrailPoint, 0, MaxTrailLength),
+		UpdateCounter:          0,
+		Energy:                 energyCapacity * config.InitialEnergy, // Set based on config
+		EnergyCapacity:         energyCapacity,
+		TimeSinceReproduction:  0,
 
 		// Initialize energy fields from config
 		MetabolicRate:    config.BaseMetabolicRate,
 		MovementCost:     config.MovementCostFactor,
 		SensingCost:      config.SensingCostBase,
-		OptimalGain:      config.OptimalEnergyGainRate,
+		OptimalGain:      gain,
 		EnergyEfficiency: efficiency, // Randomized efficiency
+		HungerSpeedBoost: config.HungerSpeedBoost,
+		BoundaryCost:     config.BoundaryCollisionCost,
+
+		FlowThroughFeeding: config.FlowThroughFeeding,
+
+		// Reserve starts empty and fills from surplus energy
+		ReserveCapacity:     energyCapacity * config.ReserveCapacityRatio,
+		ReserveTransferRate: config.ReserveTransferRate,
+
+		FeedingMemorySize: config.FeedingMemorySize,
+		MinSensorSpread:   config.MinSensorSpread,
+		SensorFieldOfView: config.SensorFieldOfView,
+		CircadianStrength: config.CircadianStrength,
+
+		GainEfficiencyBudget: config.GainEfficiencyBudget,
+		PlasticityRate:       config.PlasticityRate,
+		ImprintStrength:      config.ImprintStrength,
+		ForagingStrategy:     config.ForagingStrategy,
+
+		ScaleCooldown:          config.ScaleCooldown,
+		DormancyThreshold:      config.DormancyThreshold,
+		DormantMetabolicFactor: config.DormantMetabolicFactor,
+		MaxAge:                 config.MaxAge,
 
 		// Initialize state flags
 		MarkForRemoval: false,
-		Generation:     1,            // First generation
-		ID:             rand.Int63(), // Random ID
-		ParentID:       0,            // No parent (0 = original organism)
+		Generation:     1,  // First generation
+		ID:             id, // Random ID
+		ParentID:       0,  // No parent (0 = original organism)
+		RootID:         id, // Founder of its own lineage
 	}
 }
 
@@ -124,8 +295,46 @@ func DefaultSensorAngles() [3]float64 {
 	return [3]float64{0, -math.Pi / 4, math.Pi / 4}
 }
 
+// SpreadSensorAngles pushes the left and right sensors (indices 1 and 2) out so each
+// sits at least minSpread radians from the front sensor on its own side. Clustered
+// sensors read nearly the same concentration and can't detect gradients.
+func SpreadSensorAngles(angles [3]float64, minSpread float64) [3]float64 {
+	if minSpread <= 0 {
+		return angles
+	}
+
+	front := angles[0]
+	angles[1] = math.Min(angles[1], front-minSpread)
+	angles[2] = math.Max(angles[2], front+minSpread)
+	return angles
+}
+
+// BalanceGainEfficiency moves gain and efficiency onto the curve where gain divided
+// by efficiency equals budget, scaling both by the same factor in opposite directions.
+// Since efficiency multiplies costs, a higher gain then always comes with higher costs.
+func BalanceGainEfficiency(gain, efficiency, budget float64) (float64, float64) {
+	if budget <= 0 || gain <= 0 || efficiency <= 0 {
+		return gain, efficiency
+	}
+
+	scale := math.Sqrt(budget * efficiency / gain)
+	return gain * scale, efficiency / scale
+}
+
+// SensorBlind reports whether sensor i is angled further from the heading than the
+// organism's field of view, so it can't read anything
+func (o Organism) SensorBlind(i int) bool {
+	if o.SensorFieldOfView <= 0 {
+		return false
+	}
+	return math.Abs(math.Remainder(o.SensorAngles[i], 2*math.Pi)) > o.SensorFieldOfView
+}
+
 // GetSensorPositions calculates the positions of the organism's sensors
-// based on its current position, heading, and sensor configuration
+// based on its current position, heading, and sensor configuration.
+// Blind sensors are placed on the front sensor, or on the organism itself if the
+// front sensor is blind too, so they report nothing the organism can't already
+// sense and never give it a reason to turn.
 func (o Organism) GetSensorPositions(sensorDistance float64) [3]Point {
 	var positions [3]Point
 
@@ -144,6 +353,18 @@ func (o Organism) GetSensorPositions(sensorDistance float64) [3]Point {
 		}
 	}
 
+	if o.SensorFieldOfView > 0 {
+		fallback := positions[0]
+		if o.SensorBlind(0) {
+			fallback = o.Position
+		}
+		for i := range positions {
+			if o.SensorBlind(i) {
+				positions[i] = fallback
+			}
+		}
+	}
+
 	return positions
 }
 
@@ -167,7 +388,7 @@ func (o *Organism) Turn(angle float64) {
 	}
 }
 
-// UpdateTrail adds the current position to the position history
+// UpdateTrail adds the current position and energy level to the position history
 // if enough movement has occurred since the last update
 func (o *Organism) UpdateTrail() {
 	// Only update every few frames to avoid too many points
@@ -175,8 +396,12 @@ func (o *Organism) UpdateTrail() {
 	if o.UpdateCounter >= 5 { // Record every 5th update
 		o.UpdateCounter = 0
 
-		// Add current position to history
-		o.PositionHistory = append(o.PositionHistory, o.Position)
+		// Add current position and energy level to history
+		energy := 0.0
+		if o.EnergyCapacity > 0 {
+			energy = o.Energy / o.EnergyCapacity
+		}
+		o.PositionHistory = append(o.PositionHistory, TrailPoint{Position: o.Position, Energy: energy})
 
 		// Trim history if it exceeds max length
 		if len(o.PositionHistory) > MaxTrailLength {
@@ -188,14 +413,30 @@ func (o *Organism) UpdateTrail() {
 // CanReproduce checks if the organism has enough energy and has waited the cooldown period
 func (o *Organism) CanReproduce() bool {
 	return o.Energy >= o.EnergyCapacity*ReproductionThreshold &&
-		o.TimeSinceReproduction >= ReproductionCooldown
+		o.TimeSinceReproduction >= o.Cooldown()
+}
+
+// Cooldown returns how long the organism must wait between reproductions. With
+// ScaleCooldown it grows in proportion to the share of energy invested in each
+// offspring, so an organism investing OffspringEnergyRatio waits ReproductionCooldown.
+func (o *Organism) Cooldown() float64 {
+	if !o.ScaleCooldown {
+		return ReproductionCooldown
+	}
+	return ReproductionCooldown * o.InvestmentRatio() / OffspringEnergyRatio
 }
 
 // Reproduce creates a new organism with slight mutations
 // The parent loses some energy in the process
 func (o *Organism) Reproduce() Organism {
+	return o.ReproduceWithRand(GlobalRand)
+}
+
+// ReproduceWithRand creates an offspring like Reproduce, drawing its placement,
+// mutations and ID from rng so seeded runs breed identical offspring
+func (o *Organism) ReproduceWithRand(rng *rand.Rand) Organism {
 	// Calculate how much energy to give the offspring
-	offspringEnergy := o.Energy * OffspringEnergyRatio
+	offspringEnergy := o.Energy * o.InvestmentRatio()
 
 	// Reduce parent's energy
 	o.Energy -= offspringEnergy
@@ -205,8 +446,8 @@ func (o *Organism) Reproduce() Organism {
 
 	// Create offspring with mutations
 	// Position is set to be slightly offset from parent
-	offsetDistance := 5.0 + rand.Float64()*5.0  // 5-10 units away
-	offsetAngle := rand.Float64() * 2 * math.Pi // Random angle
+	offsetDistance := 5.0 + rng.Float64()*5.0  // 5-10 units away
+	offsetAngle := rng.Float64() * 2 * math.Pi // Random angle
 
 	positionOffset := Point{
 		X: math.Cos(offsetAngle) * offsetDistance,
@@ -220,45 +461,76 @@ func (o *Organism) Reproduce() Organism {
 
 	// Apply small mutations to preferences and attributes
 	// Using normal distribution for more realistic mutations
-	prefMutation := rand.NormFloat64() * o.ChemPreference * MutationFactorSmall
-	speedMutation := rand.NormFloat64() * o.Speed * MutationFactorMedium
+	prefMutation := rng.NormFloat64() * o.ChemPreference * MutationFactorSmall
+	speedMutation := rng.NormFloat64() * o.Speed * MutationFactorMedium
 
 	// Don't allow negative speed
 	newSpeed := math.Max(0.1, o.Speed+speedMutation)
 
 	// Random heading for the offspring
-	newHeading := rand.Float64() * 2 * math.Pi
+	newHeading := rng.Float64() * 2 * math.Pi
+
+	// Turn bias mutates additively so unbiased lineages can still drift
+	newTurnBias := o.TurnBias + rng.NormFloat64()*MutationFactorMedium
+	newTurnBias = math.Max(-MaxTurnBias, math.Min(MaxTurnBias, newTurnBias))
+
+	// Investment also mutates additively, within its bounds
+	newInvestment := o.InvestmentRatio() + rng.NormFloat64()*MutationFactorSmall
+	newInvestment = math.Max(MinReproductionInvestment, math.Min(MaxReproductionInvestment, newInvestment))
+
+	// Circadian phase drifts around the cycle
+	newCircadianPhase := math.Mod(o.CircadianPhase+rng.NormFloat64()*MutationFactorMedium, 2*math.Pi)
+	if newCircadianPhase < 0 {
+		newCircadianPhase += 2 * math.Pi
+	}
 
-	// Slightly mutate sensor angles
+	// Slightly mutate sensor angles, keeping them from collapsing together
 	var newSensorAngles [3]float64
 	for i, angle := range o.SensorAngles {
-		mutation := rand.NormFloat64() * MutationFactorSmall
+		mutation := rng.NormFloat64() * MutationFactorSmall
 		newSensorAngles[i] = angle + mutation
 	}
+	newSensorAngles = SpreadSensorAngles(newSensorAngles, o.MinSensorSpread)
+
+	// Size mutates in proportion, like speed, within its bounds
+	newSize := o.BodySize() * (1 + rng.NormFloat64()*MutationFactorMedium)
+	newSize = math.Max(MinSize, math.Min(MaxSize, newSize))
 
 	// Calculate new energy capacity based on speed
 	newEnergyCapacity := 100.0 + newSpeed*10.0
 
+	// Keep the parent's reserve proportions; the offspring's reserve starts empty
+	newReserveCapacity := 0.0
+	if o.EnergyCapacity > 0 {
+		newReserveCapacity = newEnergyCapacity * o.ReserveCapacity / o.EnergyCapacity
+	}
+
 	// Mutate energy-related attributes
-	metabolicRateMutation := o.mutateValue(o.MetabolicRate, MutationFactorSmall)
-	movementCostMutation := o.mutateValue(o.MovementCost, MutationFactorSmall)
-	sensingCostMutation := o.mutateValue(o.SensingCost, MutationFactorSmall)
-	optimalGainMutation := o.mutateValue(o.OptimalGain, MutationFactorMedium)
-	efficiencyMutation := o.mutateValue(o.EnergyEfficiency, MutationFactorMedium)
+	metabolicRateMutation := o.mutateValue(rng, o.MetabolicRate, MutationFactorSmall)
+	movementCostMutation := o.mutateValue(rng, o.MovementCost, MutationFactorSmall)
+	sensingCostMutation := o.mutateValue(rng, o.SensingCost, MutationFactorSmall)
+	optimalGainMutation := o.mutateValue(rng, o.OptimalGain, MutationFactorMedium)
+	efficiencyMutation := o.mutateValue(rng, o.EnergyEfficiency, MutationFactorMedium)
+
+	// Under a gain/efficiency budget, better gain is paid for with higher costs
+	optimalGainMutation, efficiencyMutation = BalanceGainEfficiency(optimalGainMutation, efficiencyMutation, o.GainEfficiencyBudget)
 
 	// Create the offspring
-	return Organism{
-		Position:              offspringPosition,
-		Heading:               newHeading,
-		PreviousHeading:       newHeading,
-		ChemPreference:        o.ChemPreference + prefMutation,
-		Speed:                 newSpeed,
-		SensorAngles:          newSensorAngles,
-		PositionHistory:       make([]Point, 0, MaxTrailLength),
-		UpdateCounter:         0,
-		Energy:                offspringEnergy,
-		EnergyCapacity:        newEnergyCapacity,
-		TimeSinceReproduction: 0,
+	offspring := Organism{
+		Position:               offspringPosition,
+		Heading:                newHeading,
+		PreviousHeading:        newHeading,
+		ChemPreference:         o.ChemPreference + prefMutation,
+		Speed:                  newSpeed,
+		Size:                   newSize,
+		SensorAngles:           newSensorAngles,
+		TurnBias:               newTurnBias,
+		ReproductionInvestment: newInvestment,
+		PositionHistory:        make([]TrailPoint, 0, MaxTrailLength),
+		UpdateCounter:          0,
+		Energy:                 offspringEnergy,
+		EnergyCapacity:         newEnergyCapacity,
+		TimeSinceReproduction:  0,
 
 		// Mutated energy attributes
 		MetabolicRate:    metabolicRateMutation,
@@ -266,48 +538,353 @@ func (o *Organism) Reproduce() Organism {
 		SensingCost:      sensingCostMutation,
 		OptimalGain:      optimalGainMutation,
 		EnergyEfficiency: efficiencyMutation,
+		HungerSpeedBoost: o.HungerSpeedBoost,
+		BoundaryCost:     o.BoundaryCost,
+
+		FlowThroughFeeding: o.FlowThroughFeeding,
+
+		ReserveCapacity:     newReserveCapacity,
+		ReserveTransferRate: o.ReserveTransferRate,
+
+		// Offspring inherit the memory capacity but not the memories
+		FeedingMemorySize: o.FeedingMemorySize,
+		MinSensorSpread:   o.MinSensorSpread,
+		SensorFieldOfView: o.SensorFieldOfView,
+		CircadianPhase:    newCircadianPhase,
+		CircadianStrength: o.CircadianStrength,
+
+		GainEfficiencyBudget: o.GainEfficiencyBudget,
+		PlasticityRate:       o.PlasticityRate, // The learned shift itself starts over, unless imprinted below
+		ImprintStrength:      o.ImprintStrength,
+		ForagingStrategy:     o.ForagingStrategy,
+
+		ScaleCooldown:          o.ScaleCooldown,
+		DormancyThreshold:      o.DormancyThreshold,
+		DormantMetabolicFactor: o.DormantMetabolicFactor,
+		MaxAge:                 o.MaxAge,
 
 		// State flags and lineage
 		MarkForRemoval: false,
 		Generation:     o.Generation + 1, // Increment generation
-		ID:             rand.Int63(),     // New random ID
+		ID:             rng.Int63(),      // New random ID
 		ParentID:       o.ID,             // Set parent ID for lineage tracking
+		RootID:         o.RootAncestor(), // Inherit the lineage's founder
+	}
+
+	// Plastic lineages pass on a head start toward the conditions the parent was living in
+	offspring.imprint(o.LocalConcentration)
+
+	// Mutation can't be allowed to pass a broken trait down the lineage
+	offspring.Sanitize()
+	return offspring
+}
+
+// imprint starts the learned preference ImprintStrength of the way from the
+// inherited preference toward concentration. Does nothing unless both plasticity
+// and imprinting are enabled.
+func (o *Organism) imprint(concentration float64) {
+	if o.PlasticityRate <= 0 || o.ImprintStrength <= 0 {
+		return
+	}
+
+	o.PreferenceShift = (concentration - o.ChemPreference) * o.ImprintStrength
+}
+
+// Sanitize replaces NaN or infinite traits and state with safe values, and raises
+// the preference, speed and capacity to their minimums, so one corrupted value
+// can't spread into energy calculations or statistics. Non-finite energy is
+// treated as none left.
+func (o *Organism) Sanitize() {
+	if !isFinite(o.ChemPreference) || o.ChemPreference < MinChemPreference {
+		o.ChemPreference = MinChemPreference
+	}
+	if !isFinite(o.Speed) || o.Speed < MinSpeed {
+		o.Speed = MinSpeed
+	}
+	if !isFinite(o.EnergyCapacity) || o.EnergyCapacity <= 0 {
+		o.EnergyCapacity = FallbackCapacity
+	}
+	if !isFinite(o.EnergyEfficiency) {
+		o.EnergyEfficiency = FallbackEfficiency
+	}
+	if !isFinite(o.Size) || o.Size < 0 {
+		o.Size = DefaultSize
+	}
+
+	for _, value := range []*float64{
+		&o.Energy, &o.Reserve, &o.PreferenceShift, &o.Heading, &o.TurnBias,
+		&o.MetabolicRate, &o.MovementCost, &o.SensingCost, &o.OptimalGain,
+	} {
+		if !isFinite(*value) {
+			*value = 0
+		}
 	}
 }
 
-// mutateValue applies a random mutation to a value
-func (o *Organism) mutateValue(value float64, mutationFactor float64) float64 {
+// isFinite reports whether value is neither NaN nor infinite
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+// BodySize returns the organism's size, treating an unset size as DefaultSize
+func (o *Organism) BodySize() float64 {
+	if o.Size <= 0 {
+		return DefaultSize
+	}
+	return o.Size
+}
+
+// FeedingRadius returns how far from its position the organism draws on chemical sources
+func (o *Organism) FeedingRadius() float64 {
+	return o.BodySize() * FeedingRadiusPerSize
+}
+
+// RootAncestor returns the ID of the founder of the organism's lineage.
+// Organisms created without a root (e.g. from older scenario files) are their own founder.
+func (o *Organism) RootAncestor() int64 {
+	if o.RootID == 0 {
+		return o.ID
+	}
+	return o.RootID
+}
+
+// InvestmentRatio returns the fraction of energy the organism gives each offspring,
+// falling back to OffspringEnergyRatio for organisms without the trait
+func (o *Organism) InvestmentRatio() float64 {
+	if o.ReproductionInvestment <= 0 {
+		return OffspringEnergyRatio
+	}
+	return o.ReproductionInvestment
+}
+
+// mutateValue applies a random mutation, drawn from rng, to a value
+func (o *Organism) mutateValue(rng *rand.Rand, value float64, mutationFactor float64) float64 {
 	// Add a normally distributed mutation
-	mutation := rand.NormFloat64() * value * mutationFactor
+	mutation := rng.NormFloat64() * value * mutationFactor
 
 	// Apply mutation, ensuring the result is positive
 	return math.Max(0.001, value+mutation)
 }
 
-// UpdateEnergy updates the organism's energy based on metabolism, movement, and environment
+// OptimalPreferenceAt returns the chemical preference that gains the most energy at
+// point. Gain peaks when the preference matches the concentration exactly, so this
+// is the local concentration.
+func OptimalPreferenceAt(world interface {
+	GetConcentrationAt(Point) float64
+}, point Point) float64 {
+	return world.GetConcentrationAt(point)
+}
+
+// seasonalWorld is implemented by worlds whose food availability follows a seasonal cycle
+type seasonalWorld interface {
+	Season() (SeasonalCycle, float64)
+}
+
+// ActivityFactor returns the organism's activity multiplier at time t in the cycle,
+// 1 + CircadianStrength*sin(2πt/period + CircadianPhase)
+func (o *Organism) ActivityFactor(cycle SeasonalCycle, t float64) float64 {
+	return 1 + o.CircadianStrength*cycle.Wave(t, o.CircadianPhase)
+}
+
+// EffectivePreference returns the preference the organism acts on: its inherited
+// ChemPreference plus whatever it has learned while feeding
+func (o *Organism) EffectivePreference() float64 {
+	return o.ChemPreference + o.PreferenceShift
+}
+
+// adaptPreference moves the learned preference toward a concentration the organism
+// just fed on, by PlasticityRate of the gap per second
+func (o *Organism) adaptPreference(concentration, deltaTime float64) {
+	if o.PlasticityRate <= 0 {
+		return
+	}
+
+	step := math.Min(o.PlasticityRate*deltaTime, 1.0)
+	o.PreferenceShift += (concentration - o.EffectivePreference()) * step
+}
+
+// UpdateEnergy updates the organism's energy based on metabolism, movement, and environment,
+// returning the energy it consumed from the environment
 func (o *Organism) UpdateEnergy(world interface {
 	GetConcentrationAt(Point) float64
-}, deltaTime float64) {
-	// Base metabolic cost (just existing)
-	o.Energy -= o.MetabolicRate * o.EnergyEfficiency * deltaTime
+}, deltaTime float64) (consumed float64) {
+	// Repair any non-finite state before it spreads into the energy balance
+	o.Sanitize()
+
+	// Seasonal food availability and the organism's activity at this point in the cycle
+	foodFactor, activity := 1.0, 1.0
+	if seasonal, ok := world.(seasonalWorld); ok {
+		cycle, t := seasonal.Season()
+		foodFactor = cycle.FoodFactor(t)
+		activity = o.ActivityFactor(cycle, t)
+	}
+
+	// Base metabolic cost (just existing), higher while active and much lower while dormant
+	metabolism := o.MetabolicRate * o.EnergyEfficiency * activity * deltaTime
+	if o.Dormant {
+		metabolism *= o.DormantMetabolicFactor
+	}
+	o.Energy -= metabolism
 
 	// Energy gain from environment if in preferred concentration
 	concentration := world.GetConcentrationAt(o.Position)
-	similarityFactor := 1.0 - math.Min(math.Abs(concentration-o.ChemPreference)/o.ChemPreference, 1.0)
+	o.LocalConcentration = concentration
+	preference := math.Max(o.EffectivePreference(), MinChemPreference)
+	similarityFactor := 1.0 - math.Min(math.Abs(concentration-preference)/preference, 1.0)
+
+	// Flow-through feeders only take in food as they move through it
+	intake := 1.0
+	if o.FlowThroughFeeding {
+		intake = o.flowThroughIntake(deltaTime)
+	}
 
 	// Only gain energy if similarity is high enough (above 70% match)
-	if similarityFactor > 0.7 {
+	if similarityFactor > 0.7 && intake > 0 {
 		// Scale gain by how close we are to perfect match
 		gainFactor := (similarityFactor - 0.7) / 0.3 // Normalize to 0-1 range
-		energyGain := o.OptimalGain * gainFactor * deltaTime
+		energyGain := o.OptimalGain * gainFactor * foodFactor * activity * intake * deltaTime
 
 		// Add energy, capped at max capacity
-		o.Energy = math.Min(o.Energy+energyGain, o.EnergyCapacity)
+		fed := math.Min(o.Energy+energyGain, o.EnergyCapacity)
+		consumed = math.Max(fed-o.Energy, 0)
+		o.Energy = fed
+
+		// Remember where we fed so we can avoid returning to a drained patch
+		o.RememberFeeding(o.Position)
+
+		// Learn to prefer what we've been eating
+		o.adaptPreference(concentration, deltaTime)
 	}
 
+	// Move energy between the active pool and the reserve
+	o.balanceReserve(deltaTime)
+
 	// Check for death condition
 	if o.Energy <= 0 {
 		o.Energy = 0
-		o.MarkForRemoval = true
+		o.MarkDead(DeathCauseStarvation)
+	}
+	return consumed
+}
+
+// flowThroughIntake returns the share of a step's food a flow-through feeder takes
+// in: the distance it moved over the distance its base speed covers in deltaTime
+func (o *Organism) flowThroughIntake(deltaTime float64) float64 {
+	fullDistance := o.Speed * deltaTime
+	if fullDistance <= 0 {
+		return 0
+	}
+	return o.DistanceMoved / fullDistance
+}
+
+// DeathCause records why an organism was marked for removal
+type DeathCause string
+
+const (
+	DeathCauseStarvation DeathCause = "starvation"
+	DeathCauseOldAge     DeathCause = "old-age"
+)
+
+// MarkDead marks the organism for removal. The first cause recorded is kept.
+func (o *Organism) MarkDead(cause DeathCause) {
+	o.MarkForRemoval = true
+	if o.DeathCause == "" {
+		o.DeathCause = cause
+	}
+}
+
+// ClearStarvation undoes a starvation mark, for when the energy loss behind it
+// has been reversed. Other causes of death stand.
+func (o *Organism) ClearStarvation() {
+	if o.DeathCause == DeathCauseStarvation || o.DeathCause == "" {
+		o.MarkForRemoval = false
+		o.DeathCause = ""
+	}
+}
+
+// CauseOfDeath returns why the organism should be removed, or "" if it is alive.
+// Organisms drained of energy without a recorded cause have starved.
+func (o Organism) CauseOfDeath() DeathCause {
+	if o.DeathCause != "" {
+		return o.DeathCause
+	}
+	if o.Energy <= 0 {
+		return DeathCauseStarvation
+	}
+	return ""
+}
+
+// RememberFeeding records a feeding position in the organism's memory. Positions close
+// to the most recent memory are skipped, and the oldest memories are forgotten once
+// FeedingMemorySize is reached. Does nothing if the memory is disabled.
+func (o *Organism) RememberFeeding(position Point) {
+	if o.FeedingMemorySize <= 0 {
+		return
+	}
+
+	if n := len(o.FeedingMemory); n > 0 && o.FeedingMemory[n-1].DistanceTo(position) < FeedingMemorySpacing {
+		return
+	}
+
+	o.FeedingMemory = append(o.FeedingMemory, position)
+	if excess := len(o.FeedingMemory) - o.FeedingMemorySize; excess > 0 {
+		o.FeedingMemory = o.FeedingMemory[excess:]
+	}
+}
+
+// TotalEnergy returns the organism's active energy plus its reserve
+func (o *Organism) TotalEnergy() float64 {
+	return o.Energy + o.Reserve
+}
+
+// balanceReserve stores surplus active energy in the reserve and draws on the reserve
+// when active energy runs low, moving at most ReserveTransferRate per second.
+// Transfers only move energy between the pools, so the total is conserved.
+func (o *Organism) balanceReserve(deltaTime float64) {
+	if o.ReserveCapacity <= 0 || o.ReserveTransferRate <= 0 {
+		return
+	}
+
+	maxTransfer := o.ReserveTransferRate * deltaTime
+
+	switch {
+	case o.Energy > o.EnergyCapacity*ReserveDepositThreshold:
+		// Store the surplus above the deposit threshold
+		amount := math.Min(o.Energy-o.EnergyCapacity*ReserveDepositThreshold, maxTransfer)
+		amount = math.Min(amount, o.ReserveCapacity-o.Reserve)
+		if amount > 0 {
+			o.Energy -= amount
+			o.Reserve += amount
+		}
+	case o.Energy < o.EnergyCapacity*ReserveWithdrawThreshold:
+		// Top the active pool back up toward the withdraw threshold
+		amount := math.Min(o.EnergyCapacity*ReserveWithdrawThreshold-o.Energy, maxTransfer)
+		amount = math.Min(amount, o.Reserve)
+		if amount > 0 {
+			o.Energy += amount
+			o.Reserve -= amount
+		}
+	}
+}
+
+// LimitEnergyChange caps how far the organism's energy has moved from previousEnergy
+// to maxFraction of its capacity, so a single large time step can't swing energy
+// wildly. A maxFraction of zero or less disables the cap.
+func (o *Organism) LimitEnergyChange(previousEnergy, maxFraction float64) {
+	if maxFraction <= 0 {
+		return
+	}
+
+	maxChange := o.EnergyCapacity * maxFraction
+	switch {
+	case o.Energy > previousEnergy+maxChange:
+		o.Energy = previousEnergy + maxChange
+	case o.Energy < previousEnergy-maxChange:
+		o.Energy = previousEnergy - maxChange
+
+		// The capped loss may no longer be fatal
+		if o.Energy > 0 {
+			o.ClearStarvation()
+		}
 	}
 }
@@ -140,3 +140,522 @@ func TestTurn(t *testing.T) {
 		t.Errorf("After turning below 0, heading = %v; want %v", org4.Heading, expected4)
 	}
 }
+
+// uniformWorld reports the same concentration everywhere
+type uniformWorld float64
+
+func (w uniformWorld) GetConcentrationAt(Point) float64 { return float64(w) }
+
+func TestLimitEnergyChange(t *testing.T) {
+	const maxFraction = 0.05
+
+	tests := []struct {
+		name          string
+		concentration float64
+		metabolicRate float64
+	}{
+		{"gain", 50.0, 0.0},     // Perfect match, so energy rises
+		{"loss", 1000.0, 100.0}, // No gain and heavy metabolism, so energy falls
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			org := NewOrganism(NewPoint(0, 0), 0, 50.0, 1.0, DefaultSensorAngles())
+			org.EnergyCapacity = 100.0
+			org.Energy = 50.0
+			org.OptimalGain = 10.0
+			org.MetabolicRate = tt.metabolicRate
+			org.EnergyEfficiency = 1.0
+
+			// A huge time step would move energy all the way to a bound
+			previous := org.Energy
+			org.UpdateEnergy(uniformWorld(tt.concentration), 1000.0)
+			org.LimitEnergyChange(previous, maxFraction)
+
+			change := math.Abs(org.Energy - previous)
+			if change > org.EnergyCapacity*maxFraction+1e-9 {
+				t.Errorf("Energy changed by %v in one step; want at most %v", change, org.EnergyCapacity*maxFraction)
+			}
+			if change == 0 {
+				t.Error("Expected energy to change")
+			}
+			if org.MarkForRemoval {
+				t.Error("Expected a capped loss not to kill the organism")
+			}
+		})
+	}
+}
+
+func TestEnergyReserve(t *testing.T) {
+	newOrg := func() Organism {
+		org := NewOrganism(NewPoint(0, 0), 0, 50.0, 1.0, DefaultSensorAngles())
+		org.EnergyCapacity = 100.0
+		org.EnergyEfficiency = 1.0
+		org.ReserveCapacity = 50.0
+		org.ReserveTransferRate = 10.0
+		return org
+	}
+
+	t.Run("Transfers conserve total energy", func(t *testing.T) {
+		org := newOrg()
+		org.MetabolicRate = 0
+		org.OptimalGain = 0
+
+		// Surplus flows into the reserve
+		org.Energy = 100.0
+		org.UpdateEnergy(uniformWorld(0), 1.0)
+		if org.Reserve != 10.0 || org.TotalEnergy() != 100.0 {
+			t.Errorf("After deposit: energy %v, reserve %v; want 90 and 10", org.Energy, org.Reserve)
+		}
+
+		// A low active pool draws on the reserve
+		org.Energy = 20.0
+		before := org.TotalEnergy()
+		org.UpdateEnergy(uniformWorld(0), 0.5)
+		if org.Energy != 25.0 || math.Abs(org.TotalEnergy()-before) > 1e-9 {
+			t.Errorf("After withdrawal: energy %v, total %v; want 25 and %v", org.Energy, org.TotalEnergy(), before)
+		}
+	})
+
+	t.Run("Reserve buffers a transient deficit", func(t *testing.T) {
+		withReserve := newOrg()
+		withoutReserve := newOrg()
+		withoutReserve.ReserveCapacity = 0
+
+		for _, org := range []*Organism{&withReserve, &withoutReserve} {
+			org.OptimalGain = 0
+			org.MetabolicRate = 8.0
+			org.Energy = 5.0
+		}
+		withReserve.Reserve = 40.0
+
+		// One second of heavy metabolism is more than the active pool holds
+		withReserve.UpdateEnergy(uniformWorld(0), 1.0)
+		withoutReserve.UpdateEnergy(uniformWorld(0), 1.0)
+
+		if !withoutReserve.MarkForRemoval {
+			t.Error("Expected the organism without a reserve to starve")
+		}
+		if withReserve.MarkForRemoval || withReserve.Energy <= 0 {
+			t.Errorf("Expected the reserve to keep the organism alive, energy %v", withReserve.Energy)
+		}
+	})
+}
+
+func TestRememberFeeding(t *testing.T) {
+	org := NewOrganism(NewPoint(0, 0), 0, 50.0, 1.0, DefaultSensorAngles())
+
+	// Disabled by default
+	org.RememberFeeding(NewPoint(10, 10))
+	if len(org.FeedingMemory) != 0 {
+		t.Fatalf("Expected no memories when the memory is disabled, got %v", org.FeedingMemory)
+	}
+
+	org.FeedingMemorySize = 2
+	org.RememberFeeding(NewPoint(0, 0))
+	org.RememberFeeding(NewPoint(1, 1)) // Too close to the last memory
+	org.RememberFeeding(NewPoint(50, 0))
+	org.RememberFeeding(NewPoint(100, 0)) // Pushes out the oldest
+
+	want := []Point{NewPoint(50, 0), NewPoint(100, 0)}
+	if len(org.FeedingMemory) != len(want) || org.FeedingMemory[0] != want[0] || org.FeedingMemory[1] != want[1] {
+		t.Errorf("FeedingMemory = %v; want %v", org.FeedingMemory, want)
+	}
+}
+
+func TestReproductionInvestment(t *testing.T) {
+	testCases := []struct {
+		name       string
+		investment float64
+	}{
+		{"Cheap offspring", 0.12},
+		{"Default", OffspringEnergyRatio},
+		{"Expensive offspring", 0.58},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mutated := false
+			for i := 0; i < 50; i++ {
+				parent := NewOrganism(NewPoint(0, 0), 0, 5.0, 1.0, DefaultSensorAngles())
+				parent.ReproductionInvestment = tc.investment
+				parent.Energy = 100
+
+				child := parent.Reproduce()
+
+				// The parent's loss matches its own trait, not the child's
+				wantLoss := 100 * tc.investment
+				if math.Abs((100-parent.Energy)-wantLoss) > 1e-9 || math.Abs(child.Energy-wantLoss) > 1e-9 {
+					t.Fatalf("Parent lost %v and child got %v; want %v", 100-parent.Energy, child.Energy, wantLoss)
+				}
+
+				if child.ReproductionInvestment < MinReproductionInvestment ||
+					child.ReproductionInvestment > MaxReproductionInvestment {
+					t.Fatalf("Child investment %v outside [%v, %v]",
+						child.ReproductionInvestment, MinReproductionInvestment, MaxReproductionInvestment)
+				}
+				mutated = mutated || child.ReproductionInvestment != tc.investment
+			}
+			if !mutated {
+				t.Errorf("Investment %v was never mutated in 50 offspring", tc.investment)
+			}
+		})
+	}
+
+	// Organisms without the trait invest the default ratio
+	legacy := Organism{Energy: 100}
+	if got := legacy.InvestmentRatio(); got != OffspringEnergyRatio {
+		t.Errorf("InvestmentRatio() without the trait = %v; want %v", got, OffspringEnergyRatio)
+	}
+}
+
+func TestCooldownScalesWithInvestment(t *testing.T) {
+	newParent := func(investment float64) Organism {
+		org := NewOrganism(NewPoint(0, 0), 0, 5.0, 1.0, DefaultSensorAngles())
+		org.ReproductionInvestment = investment
+		org.ScaleCooldown = true
+		org.Energy = org.EnergyCapacity
+		return org
+	}
+
+	cheap := newParent(0.2)
+	costly := newParent(0.4)
+
+	// Twice the investment takes twice as long to recover from
+	if got, want := costly.Cooldown()/cheap.Cooldown(), 2.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Cooldown ratio = %v; want %v", got, want)
+	}
+
+	// Waiting out the cheap organism's cooldown is not enough for the costly one
+	cheap.TimeSinceReproduction = cheap.Cooldown()
+	costly.TimeSinceReproduction = cheap.Cooldown()
+	if !cheap.CanReproduce() {
+		t.Error("Expected the cheap organism to reproduce after its cooldown")
+	}
+	if costly.CanReproduce() {
+		t.Error("Expected the costly organism to still be recovering")
+	}
+
+	// Without scaling, and at the default investment, the cooldown is the fixed one
+	fixed := newParent(0.4)
+	fixed.ScaleCooldown = false
+	if got := fixed.Cooldown(); got != ReproductionCooldown {
+		t.Errorf("Unscaled Cooldown() = %v; want %v", got, ReproductionCooldown)
+	}
+	standard := newParent(OffspringEnergyRatio)
+	if got := standard.Cooldown(); math.Abs(got-ReproductionCooldown) > 1e-9 {
+		t.Errorf("Default-investment Cooldown() = %v; want %v", got, ReproductionCooldown)
+	}
+}
+
+func TestSanitizeRepairsNonFiniteTraits(t *testing.T) {
+	org := NewOrganism(NewPoint(10, 10), 0, 5.0, 1.0, DefaultSensorAngles())
+	org.ChemPreference = math.NaN()
+	org.Speed = math.Inf(1)
+	org.EnergyCapacity = 0
+	org.Energy = math.NaN()
+	org.OptimalGain = math.Inf(-1)
+
+	org.Sanitize()
+
+	if org.ChemPreference != MinChemPreference || org.Speed != MinSpeed || org.EnergyCapacity != FallbackCapacity {
+		t.Errorf("Sanitized preference, speed, capacity = %v, %v, %v; want %v, %v, %v",
+			org.ChemPreference, org.Speed, org.EnergyCapacity, MinChemPreference, MinSpeed, FallbackCapacity)
+	}
+	if org.Energy != 0 || org.OptimalGain != 0 {
+		t.Errorf("Sanitized energy, gain = %v, %v; want 0, 0", org.Energy, org.OptimalGain)
+	}
+
+	// Offspring of a corrupted parent come out usable
+	parent := NewOrganism(NewPoint(10, 10), 0, math.NaN(), 1.0, DefaultSensorAngles())
+	parent.Energy = parent.EnergyCapacity
+	child := parent.Reproduce()
+	if math.IsNaN(child.ChemPreference) {
+		t.Error("Offspring inherited a NaN preference")
+	}
+}
+
+func TestSensorSpreadThroughMutation(t *testing.T) {
+	minSpread := 0.3
+
+	// Start from sensors that have nearly collapsed onto the front sensor
+	parent := NewOrganism(NewPoint(0, 0), 0, 5.0, 1.0, [3]float64{0, -0.01, 0.02})
+	parent.MinSensorSpread = minSpread
+
+	for generation := 0; generation < 200; generation++ {
+		parent.Energy = parent.EnergyCapacity
+		child := parent.Reproduce()
+
+		front, left, right := child.SensorAngles[0], child.SensorAngles[1], child.SensorAngles[2]
+		if front-left < minSpread-1e-9 || right-front < minSpread-1e-9 {
+			t.Fatalf("Generation %d sensor angles %v closer than %v to the front sensor",
+				generation, child.SensorAngles, minSpread)
+		}
+		if child.MinSensorSpread != minSpread {
+			t.Fatalf("Child MinSensorSpread = %v; want %v", child.MinSensorSpread, minSpread)
+		}
+		parent = child
+	}
+
+	// Disabled, angles pass through untouched
+	clustered := [3]float64{0, 0.01, -0.01}
+	if got := SpreadSensorAngles(clustered, 0); got != clustered {
+		t.Errorf("SpreadSensorAngles(%v, 0) = %v; want unchanged", clustered, got)
+	}
+}
+
+// seasonalTestWorld has a uniform concentration and a seasonal food cycle
+// whose clock the test advances by hand
+type seasonalTestWorld struct {
+	concentration float64
+	cycle         SeasonalCycle
+	time          float64
+}
+
+func (w *seasonalTestWorld) GetConcentrationAt(Point) float64 { return w.concentration }
+
+func (w *seasonalTestWorld) Season() (SeasonalCycle, float64) { return w.cycle, w.time }
+
+func TestCircadianPhaseAlignment(t *testing.T) {
+	newOrganism := func(phase float64) Organism {
+		org := NewOrganism(NewPoint(0, 0), 0, 50.0, 1.0, DefaultSensorAngles())
+		org.EnergyCapacity = 10000 // Never cap gains
+		org.Energy = 100
+		org.MetabolicRate = 0.1
+		org.EnergyEfficiency = 1.0
+		org.OptimalGain = 1.0
+		org.CircadianStrength = 0.8
+		org.CircadianPhase = phase
+		return org
+	}
+
+	aligned := newOrganism(0)         // Most active when food peaks
+	antiPhase := newOrganism(math.Pi) // Most active when food is scarce
+
+	// Organisms sit at their preferred concentration for one full cycle
+	world := &seasonalTestWorld{
+		concentration: 50.0,
+		cycle:         SeasonalCycle{Period: 100, Amplitude: 0.5},
+	}
+	dt := 0.1
+	for world.time = 0; world.time < world.cycle.Period; world.time += dt {
+		aligned.UpdateEnergy(world, dt)
+		antiPhase.UpdateEnergy(world, dt)
+	}
+
+	if aligned.Energy <= antiPhase.Energy {
+		t.Errorf("Aligned organism ended with %.2f energy, anti-phase with %.2f; want aligned ahead",
+			aligned.Energy, antiPhase.Energy)
+	}
+
+	// In a world without seasons, the phase makes no difference
+	a, b := newOrganism(0), newOrganism(math.Pi)
+	a.UpdateEnergy(uniformWorld(50.0), 1.0)
+	b.UpdateEnergy(uniformWorld(50.0), 1.0)
+	if a.Energy != b.Energy {
+		t.Errorf("Without seasons energies differ: %v vs %v", a.Energy, b.Energy)
+	}
+}
+
+func TestUpdateTrailRecordsEnergy(t *testing.T) {
+	org := NewOrganism(NewPoint(10, 20), 0, 5.0, 1.0, DefaultSensorAngles())
+	org.EnergyCapacity = 100
+
+	// One point is recorded every fifth update
+	steps := []struct {
+		position Point
+		energy   float64
+	}{
+		{NewPoint(10, 20), 80},
+		{NewPoint(15, 20), 60},
+		{NewPoint(20, 25), 35},
+	}
+	for _, step := range steps {
+		org.Position = step.position
+		org.Energy = step.energy
+		for i := 0; i < 5; i++ {
+			org.UpdateTrail()
+		}
+	}
+
+	if len(org.PositionHistory) != len(steps) {
+		t.Fatalf("Recorded %d trail points; want %d", len(org.PositionHistory), len(steps))
+	}
+	for i, step := range steps {
+		got := org.PositionHistory[i]
+		if got.Position != step.position || math.Abs(got.Energy-step.energy/100) > 1e-9 {
+			t.Errorf("Trail point %d = %+v; want position %v with energy %v", i, got, step.position, step.energy/100)
+		}
+	}
+}
+
+func TestOptimalPreferenceAt(t *testing.T) {
+	world := uniformWorld(40.0)
+	point := NewPoint(10, 10)
+
+	preference := OptimalPreferenceAt(world, point)
+	if preference != 40.0 {
+		t.Fatalf("OptimalPreferenceAt = %v; want the local concentration 40", preference)
+	}
+
+	// An organism with the suggested preference should out-gain its neighbors
+	gain := func(pref float64) float64 {
+		org := NewOrganism(point, 0, pref, 1.0, DefaultSensorAngles())
+		org.EnergyCapacity = 1000.0
+		org.Energy = 100.0
+		org.OptimalGain = 10.0
+		org.MetabolicRate = 0.0
+		org.EnergyEfficiency = 1.0
+		org.UpdateEnergy(world, 1.0)
+		return org.Energy
+	}
+	best := gain(preference)
+	for _, pref := range []float64{preference * 0.9, preference * 1.1} {
+		if other := gain(pref); other >= best {
+			t.Errorf("Preference %v gained %v; want less than %v at the optimal preference", pref, other, best)
+		}
+	}
+}
+
+func TestGainEfficiencyTradeoff(t *testing.T) {
+	const budget = 0.5
+
+	// Start on the budget curve, then push gain up as a mutation might
+	gain, efficiency := BalanceGainEfficiency(0.5, 1.0, budget)
+	if gain != 0.5 || efficiency != 1.0 {
+		t.Fatalf("Balanced (0.5, 1.0) = (%v, %v); want it unchanged on the curve", gain, efficiency)
+	}
+
+	newGain, newEfficiency := BalanceGainEfficiency(gain*1.2, efficiency, budget)
+	if newGain <= gain {
+		t.Errorf("Gain = %v after an upward mutation from %v; want it to stay higher", newGain, gain)
+	}
+	if newEfficiency <= efficiency {
+		t.Errorf("Efficiency multiplier = %v; want costs above %v to pay for the higher gain", newEfficiency, efficiency)
+	}
+	if math.Abs(newGain/newEfficiency-budget) > 1e-9 {
+		t.Errorf("Gain/efficiency = %v; want the budget %v", newGain/newEfficiency, budget)
+	}
+
+	// Improving efficiency (lowering costs) must cost gain
+	cheapGain, cheapEfficiency := BalanceGainEfficiency(gain, efficiency*0.8, budget)
+	if cheapEfficiency >= efficiency || cheapGain >= gain {
+		t.Errorf("Balanced cheaper costs = (%v, %v); want both below (%v, %v)", cheapGain, cheapEfficiency, gain, efficiency)
+	}
+
+	// Offspring stay on the curve through mutation
+	config := OrganismConfig{
+		InitialEnergy:         1.0,
+		MaximumEnergy:         100.0,
+		OptimalEnergyGainRate: 0.5,
+		EnergyEfficiencyRange: [2]float64{0.8, 1.2},
+		GainEfficiencyBudget:  budget,
+	}
+	parent := NewOrganismWithConfig(NewPoint(0, 0), 0, 50.0, 1.0, DefaultSensorAngles(), config)
+	for i := 0; i < 100; i++ {
+		child := parent.Reproduce()
+		if math.Abs(child.OptimalGain/child.EnergyEfficiency-budget) > 1e-9 {
+			t.Fatalf("Offspring gain/efficiency = %v; want the budget %v", child.OptimalGain/child.EnergyEfficiency, budget)
+		}
+		parent.Energy = parent.EnergyCapacity
+	}
+}
+
+func TestPreferencePlasticity(t *testing.T) {
+	const concentration = 45.0
+
+	org := NewOrganism(NewPoint(0, 0), 0, 50.0, 1.0, DefaultSensorAngles())
+	org.EnergyCapacity = 1e6
+	org.MetabolicRate = 0
+	org.PlasticityRate = 0.1
+
+	for i := 0; i < 1000; i++ {
+		org.UpdateEnergy(uniformWorld(concentration), 0.1)
+	}
+
+	if math.Abs(org.EffectivePreference()-concentration) > 0.01 {
+		t.Errorf("Effective preference = %v after feeding at %v; want it to drift there", org.EffectivePreference(), concentration)
+	}
+	if org.ChemPreference != 50.0 {
+		t.Errorf("Inherited preference = %v; want it unchanged at 50", org.ChemPreference)
+	}
+
+	child := org.Reproduce()
+	if child.PreferenceShift != 0 {
+		t.Errorf("Offspring preference shift = %v; want learning not to be inherited", child.PreferenceShift)
+	}
+
+	// Without plasticity the preference stays put
+	fixed := NewOrganism(NewPoint(0, 0), 0, 50.0, 1.0, DefaultSensorAngles())
+	fixed.UpdateEnergy(uniformWorld(concentration), 10.0)
+	if fixed.EffectivePreference() != 50.0 {
+		t.Errorf("Effective preference = %v with plasticity off; want 50", fixed.EffectivePreference())
+	}
+}
+
+func TestImprintingShiftsOffspringTowardParentConcentration(t *testing.T) {
+	const concentration = 80.0
+
+	parent := NewOrganism(NewPoint(0, 0), 0, 50.0, 1.0, DefaultSensorAngles())
+	parent.PlasticityRate = 0.1
+	parent.ImprintStrength = 0.5
+	parent.UpdateEnergy(uniformWorld(concentration), 0.1)
+	if parent.LocalConcentration != concentration {
+		t.Fatalf("Parent local concentration = %v; want %v", parent.LocalConcentration, concentration)
+	}
+
+	for i := 0; i < 20; i++ {
+		parent.Energy = parent.EnergyCapacity
+		child := parent.Reproduce()
+
+		// The genes are inherited as usual; only the learned shift is imprinted
+		genetic := child.ChemPreference
+		want := genetic + (concentration-genetic)*0.5
+		if math.Abs(child.EffectivePreference()-want) > 1e-9 {
+			t.Errorf("Offspring effective preference = %v; want %v, halfway from its genetic %v to %v",
+				child.EffectivePreference(), want, genetic, concentration)
+		}
+		if math.Abs(child.EffectivePreference()-concentration) >= math.Abs(genetic-concentration) {
+			t.Errorf("Offspring effective preference %v is no closer to %v than its genetic preference %v",
+				child.EffectivePreference(), concentration, genetic)
+		}
+	}
+
+	// Imprinting rides on plasticity; without it the offspring start unshifted
+	parent.PlasticityRate = 0
+	if child := parent.Reproduce(); child.PreferenceShift != 0 {
+		t.Errorf("Offspring preference shift = %v with plasticity off; want 0", child.PreferenceShift)
+	}
+}
+
+func TestFlowThroughFeeding(t *testing.T) {
+	tests := []struct {
+		name          string
+		flowThrough   bool
+		distanceMoved float64
+		wantGain      float64
+	}{
+		{"Stationary flow-through feeder", true, 0, 0},
+		{"Flow-through feeder at full speed", true, 2, 10},
+		{"Flow-through feeder at half speed", true, 1, 5},
+		{"Stationary ordinary feeder", false, 0, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Perfect match with no costs, so any change is gain
+			org := NewOrganism(NewPoint(0, 0), 0, 50.0, 2.0, DefaultSensorAngles())
+			org.EnergyCapacity = 100.0
+			org.Energy = 50.0
+			org.OptimalGain = 10.0
+			org.MetabolicRate = 0
+			org.FlowThroughFeeding = tt.flowThrough
+			org.DistanceMoved = tt.distanceMoved
+
+			org.UpdateEnergy(uniformWorld(50.0), 1.0)
+			if gain := org.Energy - 50.0; math.Abs(gain-tt.wantGain) > 1e-9 {
+				t.Errorf("Gained %v energy; want %v", gain, tt.wantGain)
+			}
+		})
+	}
+}
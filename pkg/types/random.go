@@ -0,0 +1,129 @@
+package types
+
+import (
+	"math/rand"
+	randv2 "math/rand/v2"
+	"time"
+)
+
+// SourceFactory creates the random source for a seed. Passing one to the world and
+// simulator chooses the generator every random draw in a run comes from.
+type SourceFactory func(seed int64) rand.Source
+
+// MathRandSource creates math/rand's default source, used when no factory is given
+func MathRandSource(seed int64) rand.Source {
+	return rand.NewSource(seed)
+}
+
+// PCGSource creates a permuted congruential generator from math/rand/v2, which is
+// faster and statistically stronger than the default source
+func PCGSource(seed int64) rand.Source {
+	return &pcgSource{randv2.NewPCG(uint64(seed), 0)}
+}
+
+// pcgSource adapts a math/rand/v2 PCG to the math/rand source interface
+type pcgSource struct {
+	pcg *randv2.PCG
+}
+
+// Int63 returns a non-negative 63-bit value
+func (s *pcgSource) Int63() int64 { return int64(s.pcg.Uint64() >> 1) }
+
+// Uint64 returns a 64-bit value, letting rand.Rand use every bit
+func (s *pcgSource) Uint64() uint64 { return s.pcg.Uint64() }
+
+// Seed restarts the sequence from seed
+func (s *pcgSource) Seed(seed int64) { s.pcg.Seed(uint64(seed), 0) }
+
+// GlobalRand draws from math/rand's global generator, for callers that don't
+// bring a seeded generator of their own. Apart from Read, it's safe for
+// concurrent use.
+var GlobalRand = rand.New(globalSource{})
+
+// globalSource adapts math/rand's global generator to the source interface
+type globalSource struct{}
+
+// Int63 returns a non-negative 63-bit value
+func (globalSource) Int63() int64 { return rand.Int63() }
+
+// Uint64 returns a 64-bit value
+func (globalSource) Uint64() uint64 { return rand.Uint64() }
+
+// Seed does nothing; the global generator is seeded randomly
+func (globalSource) Seed(int64) {}
+
+// NewRand creates a generator from factory, seeded from the clock when seed is 0.
+// A nil factory uses MathRandSource.
+func NewRand(factory SourceFactory, seed int64) *rand.Rand {
+	r, _ := NewCountingRand(factory, seed)
+	return r
+}
+
+// RandState is how far a seeded generator has got through its sequence, enough
+// to recreate it at the same point with RestoreRand
+type RandState struct {
+	Seed  int64  `json:"seed"`
+	Draws uint64 `json:"draws"`
+}
+
+// CountingSource wraps a source, counting the values drawn from it so the
+// generator's position can be saved
+type CountingSource struct {
+	source rand.Source
+	seed   int64
+	draws  uint64
+}
+
+// Int63 returns a non-negative 63-bit value
+func (s *CountingSource) Int63() int64 {
+	s.draws++
+	return s.source.Int63()
+}
+
+// Uint64 returns a 64-bit value, built from two draws if the source can't
+// produce one directly
+func (s *CountingSource) Uint64() uint64 {
+	if source, ok := s.source.(rand.Source64); ok {
+		s.draws++
+		return source.Uint64()
+	}
+	return uint64(s.Int63())>>31 | uint64(s.Int63())<<32
+}
+
+// Seed restarts the sequence from seed
+func (s *CountingSource) Seed(seed int64) {
+	s.source.Seed(seed)
+	s.seed = seed
+	s.draws = 0
+}
+
+// State returns the seed and the number of values drawn since it was set
+func (s *CountingSource) State() RandState {
+	return RandState{Seed: s.seed, Draws: s.draws}
+}
+
+// NewCountingRand creates a generator like NewRand, also returning its source so
+// the generator's position can be read. The state records the seed actually
+// used, so a generator seeded from the clock can be restored too.
+func NewCountingRand(factory SourceFactory, seed int64) (*rand.Rand, *CountingSource) {
+	if factory == nil {
+		factory = MathRandSource
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	source := &CountingSource{source: factory(seed), seed: seed}
+	return rand.New(source), source
+}
+
+// RestoreRand recreates the generator a CountingSource from factory was at when
+// its state was taken, by reseeding it and skipping the values already drawn.
+// Both sources here advance one step per value, whichever method draws it.
+func RestoreRand(factory SourceFactory, state RandState) (*rand.Rand, *CountingSource) {
+	r, source := NewCountingRand(factory, state.Seed)
+	for source.draws < state.Draws {
+		source.source.Int63()
+		source.draws++
+	}
+	return r, source
+}
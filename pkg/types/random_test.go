@@ -0,0 +1,27 @@
+package types
+
+import "testing"
+
+func TestRestoreRand(t *testing.T) {
+	factories := map[string]SourceFactory{"math/rand": MathRandSource, "PCG": PCGSource}
+	for name, factory := range factories {
+		t.Run(name, func(t *testing.T) {
+			rng, source := NewCountingRand(factory, 42)
+			for i := 0; i < 25; i++ {
+				rng.Float64()
+				rng.NormFloat64()
+				rng.Intn(10)
+			}
+
+			restored, restoredSource := RestoreRand(factory, source.State())
+			if restoredSource.State() != source.State() {
+				t.Errorf("Restored generator at %+v; want %+v", restoredSource.State(), source.State())
+			}
+			for i := 0; i < 10; i++ {
+				if got, want := restored.Uint64(), rng.Uint64(); got != want {
+					t.Fatalf("Draw %d after restoring = %v; want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package types
+
+import "math"
+
+// SeasonalCycle describes a periodic swing in food availability
+type SeasonalCycle struct {
+	Period    float64 // Seconds per cycle (0 disables seasons)
+	Amplitude float64 // Fraction by which food availability swings around its mean (0-1)
+}
+
+// Wave returns sin(2πt/Period + phase), or 0 when seasons are disabled
+func (c SeasonalCycle) Wave(t, phase float64) float64 {
+	if c.Period <= 0 {
+		return 0
+	}
+	return math.Sin(2*math.Pi*t/c.Period + phase)
+}
+
+// FoodFactor returns the food availability multiplier at time t, which peaks a
+// quarter of the way through each cycle
+func (c SeasonalCycle) FoodFactor(t float64) float64 {
+	return 1 + c.Amplitude*c.Wave(t, 0)
+}
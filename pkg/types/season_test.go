@@ -0,0 +1,35 @@
+package types
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSeasonalCycleFoodFactor(t *testing.T) {
+	cycle := SeasonalCycle{Period: 100, Amplitude: 0.5}
+
+	testCases := []struct {
+		name     string
+		time     float64
+		expected float64
+	}{
+		{"Start", 0, 1.0},
+		{"Peak", 25, 1.5},
+		{"Midpoint", 50, 1.0},
+		{"Trough", 75, 0.5},
+		{"Next cycle", 125, 1.5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := cycle.FoodFactor(tc.time); math.Abs(got-tc.expected) > 1e-9 {
+				t.Errorf("FoodFactor(%v) = %v; want %v", tc.time, got, tc.expected)
+			}
+		})
+	}
+
+	disabled := SeasonalCycle{Amplitude: 0.5}
+	if got := disabled.FoodFactor(25); got != 1.0 {
+		t.Errorf("FoodFactor with seasons disabled = %v; want 1.0", got)
+	}
+}
@@ -2,11 +2,12 @@ package types
 
 // World represents the simulation environment containing organisms and chemical sources
 type World struct {
-	Width           float64          // Width of the world
-	Height          float64          // Height of the world
-	Organisms       []Organism       // Collection of organisms in the world
-	ChemicalSources []ChemicalSource // Collection of chemical sources in the world
-	Boundaries      Rect             // Rectangular boundary of the world
+	Width            float64          // Width of the world
+	Height           float64          // Height of the world
+	Organisms        []Organism       // Collection of organisms in the world
+	ChemicalSources  []ChemicalSource // Collection of chemical sources in the world
+	Boundaries       Rect             // Rectangular boundary of the world
+	MaxConcentration float64          // Level at which the summed concentration saturates (0 disables)
 }
 
 // NewWorld creates a new world with the specified dimensions
@@ -55,7 +56,32 @@ func (w *World) GetConcentrationAt(point Point) float64 {
 		totalConcentration += source.GetConcentrationAt(point)
 	}
 
-	return totalConcentration
+	return SaturateConcentration(totalConcentration, w.MaxConcentration)
+}
+
+// GetConcentrationGradientAt returns the analytic gradient of the summed
+// concentration at a point. Where the sum is saturated the field is flat.
+func (w *World) GetConcentrationGradientAt(point Point) Point {
+	var gradient Point
+	for _, source := range w.ChemicalSources {
+		g := source.GetGradientAt(point)
+		gradient.X += g.X
+		gradient.Y += g.Y
+	}
+
+	if w.MaxConcentration > 0 && w.GetConcentrationAt(point) >= w.MaxConcentration {
+		return Point{}
+	}
+	return gradient
+}
+
+// SaturateConcentration caps a summed concentration at max, so overlapping sources
+// can't push the field arbitrarily high. A max of 0 or less leaves it uncapped.
+func SaturateConcentration(total, max float64) float64 {
+	if max > 0 && total > max {
+		return max
+	}
+	return total
 }
 
 // OrganismCount returns the number of organisms in the world
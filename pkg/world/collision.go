@@ -0,0 +1,84 @@
+package world
+
+import (
+	"math"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// goldenAngle spreads the separation directions of organisms sitting on the same
+// spot evenly around the circle
+const goldenAngle = 2.399963229728653
+
+// ResolveCollisions pushes apart organisms that overlap, treating each as a disc
+// of radiusPerSize times its body size. Each overlapping pair moves apart along
+// the line between them, half the overlap each, in a single pass, so a dense crowd
+// may take a few steps to spread out fully. Organisms stay inside the world.
+// Returns the number of overlapping pairs found.
+func (w *World) ResolveCollisions(radiusPerSize float64) int {
+	if radiusPerSize <= 0 {
+		return 0
+	}
+
+	w.organismMutex.Lock()
+	defer w.organismMutex.Unlock()
+
+	// Cells as wide as the largest contact distance, so overlapping organisms are
+	// always in the same or adjacent cells
+	cellSize := 0.0
+	for i := range w.Organisms {
+		cellSize = math.Max(cellSize, 2*w.Organisms[i].BodySize()*radiusPerSize)
+	}
+	// Search around where each organism started, since separating moves them
+	index := w.organismIndex(cellSize)
+	homes := make([]types.Point, len(w.Organisms))
+	for i := range w.Organisms {
+		homes[i] = w.Organisms[i].Position
+	}
+
+	collisions := 0
+	for i := range w.Organisms {
+		index.forEachNear(homes[i], func(j int) bool {
+			// Visit each pair once
+			if j > i && w.separate(i, j, radiusPerSize) {
+				collisions++
+			}
+			return true
+		})
+	}
+
+	return collisions
+}
+
+// separate moves organisms i and j apart if they overlap, reporting whether they
+// did. Caller must hold organismMutex.
+func (w *World) separate(i, j int, radiusPerSize float64) bool {
+	a, b := &w.Organisms[i], &w.Organisms[j]
+	contact := (a.BodySize() + b.BodySize()) * radiusPerSize
+	offsetX := b.Position.X - a.Position.X
+	offsetY := b.Position.Y - a.Position.Y
+	distance := math.Hypot(offsetX, offsetY)
+	if distance >= contact {
+		return false
+	}
+
+	// Organisms on the same spot have no line between them; split them along a
+	// direction that depends only on their places in the list, so runs repeat
+	dirX, dirY := math.Cos(float64(i+j)*goldenAngle), math.Sin(float64(i+j)*goldenAngle)
+	if distance > 0 {
+		dirX, dirY = offsetX/distance, offsetY/distance
+	}
+
+	push := (contact - distance) / 2
+	a.Position = w.clampToBounds(types.Point{X: a.Position.X - dirX*push, Y: a.Position.Y - dirY*push})
+	b.Position = w.clampToBounds(types.Point{X: b.Position.X + dirX*push, Y: b.Position.Y + dirY*push})
+	return true
+}
+
+// clampToBounds returns the nearest point to p inside the world's boundaries
+func (w *World) clampToBounds(p types.Point) types.Point {
+	return types.Point{
+		X: math.Max(w.Boundaries.Min.X, math.Min(w.Boundaries.Max.X, p.X)),
+		Y: math.Max(w.Boundaries.Min.Y, math.Min(w.Boundaries.Max.Y, p.Y)),
+	}
+}
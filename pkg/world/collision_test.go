@@ -0,0 +1,73 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+func TestResolveCollisions(t *testing.T) {
+	const radius = 3.0
+
+	tests := []struct {
+		name       string
+		positions  []types.Point
+		sizes      []float64
+		collisions int
+	}{
+		{"On top of each other", []types.Point{{X: 50, Y: 50}, {X: 50, Y: 50}}, []float64{1, 1}, 1},
+		{"Partly overlapping", []types.Point{{X: 50, Y: 50}, {X: 53, Y: 50}}, []float64{1, 1}, 1},
+		{"Larger body reaches further", []types.Point{{X: 50, Y: 50}, {X: 57, Y: 50}}, []float64{2, 1}, 1},
+		{"Far apart", []types.Point{{X: 20, Y: 20}, {X: 80, Y: 80}}, []float64{1, 1}, 0},
+		{"Three in a heap", []types.Point{{X: 50, Y: 50}, {X: 50, Y: 50}, {X: 50, Y: 50}}, []float64{1, 1, 1}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWorld(config.SimulationConfig{World: config.WorldConfig{Width: 100, Height: 100}})
+			for i, pos := range tt.positions {
+				org := types.NewOrganism(pos, 0, 30, 1.0, types.DefaultSensorAngles())
+				org.Size = tt.sizes[i]
+				w.AddOrganism(org)
+			}
+
+			if got := w.ResolveCollisions(radius); got != tt.collisions {
+				t.Errorf("ResolveCollisions() = %d; want %d", got, tt.collisions)
+			}
+
+			// One pass fully separates a single pair
+			orgs := w.GetOrganisms()
+			if len(orgs) == 2 {
+				contact := (orgs[0].BodySize() + orgs[1].BodySize()) * radius
+				if distance := orgs[0].Position.DistanceTo(orgs[1].Position); distance < contact-1e-9 {
+					t.Errorf("Organisms %v apart after resolving; want at least %v", distance, contact)
+				}
+			}
+		})
+	}
+
+	t.Run("Stays inside the world", func(t *testing.T) {
+		w := NewWorld(config.SimulationConfig{World: config.WorldConfig{Width: 100, Height: 100}})
+		w.AddOrganism(types.NewOrganism(types.Point{X: 0, Y: 50}, 0, 30, 1.0, types.DefaultSensorAngles()))
+		w.AddOrganism(types.NewOrganism(types.Point{X: 1, Y: 50}, 0, 30, 1.0, types.DefaultSensorAngles()))
+		w.ResolveCollisions(radius)
+		for _, org := range w.GetOrganisms() {
+			if !w.Boundaries.Contains(org.Position) {
+				t.Errorf("Organism pushed out of the world to %v", org.Position)
+			}
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		w := NewWorld(config.SimulationConfig{World: config.WorldConfig{Width: 100, Height: 100}})
+		w.AddOrganism(types.NewOrganism(types.Point{X: 50, Y: 50}, 0, 30, 1.0, types.DefaultSensorAngles()))
+		w.AddOrganism(types.NewOrganism(types.Point{X: 50, Y: 50}, 0, 30, 1.0, types.DefaultSensorAngles()))
+		if got := w.ResolveCollisions(0); got != 0 {
+			t.Errorf("ResolveCollisions(0) = %d; want collisions off", got)
+		}
+		if orgs := w.GetOrganisms(); orgs[0].Position != orgs[1].Position {
+			t.Error("Expected organisms to stay put with collisions off")
+		}
+	})
+}
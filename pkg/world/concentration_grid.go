@@ -2,19 +2,45 @@ package world
 
 import (
 	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
 
 	"github.com/zachbeta/evolve_sim/pkg/types"
 )
 
-// ConcentrationGrid represents a simplified grid of chemical concentration values
-// This is a performance-optimized version that doesn't store full concentration data
+// SourceEnergyChangeThreshold is the relative change in a source's energy (compared to
+// the value the grid last saw) required before its region of the grid is recomputed
+const SourceEnergyChangeThreshold = 0.05
+
+// ConcentrationGrid caches chemical concentration values sampled at regular grid points.
+// Values are bilinearly interpolated between grid points. When a source changes, only
+// the grid points within its effective radius are marked dirty and they are recomputed
+// lazily the next time a query touches them. Optional finer overlays around each
+// active source resolve the sharp peaks there.
 type ConcentrationGrid struct {
 	Width     float64                // Width of the world
 	Height    float64                // Height of the world
 	CellSize  float64                // Size of each grid cell
 	NumCellsX int                    // Number of cells in X direction
 	NumCellsY int                    // Number of cells in Y direction
-	Sources   []types.ChemicalSource // References to chemical sources
+	Sources   []types.ChemicalSource // Copy of the chemical sources the grid was built from
+	Grid      [][]float64            // Cached concentration at each grid point, indexed [x][y]
+
+	MaxConcentration float64 // Level at which the summed concentration saturates (0 disables)
+
+	// Fine overlays around active sources, set with SetFineOverlays
+	FineFactor int            // Overlay resolution as a multiple of the base grid's (below 2 disables)
+	FineRadius float64        // Distance from each source the overlay covers
+	overlays   []*gridOverlay // One overlay per active source
+
+	dirty      [][]bool // Grid points that must be recomputed before use
+	dirtyCount int      // Number of dirty grid points
+	mu         sync.RWMutex
+
+	// Cache statistics
+	hits   int64 // Queries answered entirely from cached values
+	misses int64 // Queries that required recomputing at least one grid point
 }
 
 // NewConcentrationGrid creates a new concentration grid with the specified dimensions and resolution
@@ -22,6 +48,13 @@ func NewConcentrationGrid(width, height, cellSize float64) *ConcentrationGrid {
 	numCellsX := int(math.Ceil(width / cellSize))
 	numCellsY := int(math.Ceil(height / cellSize))
 
+	grid := make([][]float64, numCellsX)
+	dirty := make([][]bool, numCellsX)
+	for x := range grid {
+		grid[x] = make([]float64, numCellsY)
+		dirty[x] = make([]bool, numCellsY)
+	}
+
 	return &ConcentrationGrid{
 		Width:     width,
 		Height:    height,
@@ -29,86 +62,397 @@ func NewConcentrationGrid(width, height, cellSize float64) *ConcentrationGrid {
 		NumCellsX: numCellsX,
 		NumCellsY: numCellsY,
 		Sources:   make([]types.ChemicalSource, 0),
+		Grid:      grid,
+		dirty:     dirty,
 	}
 }
 
-// SetConcentration is a compatibility function that does nothing in the simplified implementation
+// SetConcentration sets the cached concentration value at the given grid point
 func (cg *ConcentrationGrid) SetConcentration(x, y int, value float64) {
-	// No-op in simplified implementation
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+
+	if x < 0 || x >= cg.NumCellsX || y < 0 || y >= cg.NumCellsY {
+		return
+	}
+
+	cg.Grid[x][y] = value
+	cg.clearDirty(x, y)
 }
 
-// SetSources updates the reference to chemical sources
+// SetSources replaces the chemical sources and marks the whole grid dirty
 func (cg *ConcentrationGrid) SetSources(sources []types.ChemicalSource) {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+
 	cg.Sources = make([]types.ChemicalSource, len(sources))
 	copy(cg.Sources, sources)
+	cg.buildOverlays()
+
+	for x := 0; x < cg.NumCellsX; x++ {
+		for y := 0; y < cg.NumCellsY; y++ {
+			cg.dirty[x][y] = true
+		}
+	}
+	cg.dirtyCount = cg.NumCellsX * cg.NumCellsY
 }
 
-// GetConcentrationAt returns the concentration value at the specified world coordinates
-// This simplified version calculates directly from sources without using a grid
-func (cg *ConcentrationGrid) GetConcentrationAt(point types.Point) float64 {
-	// Direct calculation from sources
-	var totalConcentration float64 = 0
+// Refresh recomputes every dirty grid point, spreading the work across all CPUs
+func (cg *ConcentrationGrid) Refresh() {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
 
-	// Find nearest source as a simple approximation
-	minDist := math.MaxFloat64
-	var nearestSource *types.ChemicalSource
+	if cg.dirtyCount == 0 {
+		return
+	}
 
-	for i := range cg.Sources {
-		source := &cg.Sources[i]
-		if !source.IsActive {
+	cg.refreshDirty(runtime.GOMAXPROCS(0))
+}
+
+// refreshDirty recomputes every dirty grid point using the given number of workers,
+// each filling a contiguous range of columns. Caller must hold the write lock.
+func (cg *ConcentrationGrid) refreshDirty(workers int) {
+	if workers > cg.NumCellsX {
+		workers = cg.NumCellsX
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	chunk := (cg.NumCellsX + workers - 1) / workers
+	cleared := make([]int, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * chunk
+		end := start + chunk
+		if end > cg.NumCellsX {
+			end = cg.NumCellsX
+		}
+		if start >= end {
 			continue
 		}
 
-		dist := source.Position.DistanceTo(point)
-		if dist < minDist {
-			minDist = dist
-			nearestSource = source
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+
+			// Workers only touch their own columns, so no further locking is needed
+			for x := start; x < end; x++ {
+				for y := 0; y < cg.NumCellsY; y++ {
+					if !cg.dirty[x][y] {
+						continue
+					}
+					point := types.Point{X: float64(x) * cg.CellSize, Y: float64(y) * cg.CellSize}
+					cg.Grid[x][y] = cg.directConcentration(point)
+					cg.dirty[x][y] = false
+					cleared[w]++
+				}
+			}
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	for _, n := range cleared {
+		cg.dirtyCount -= n
+	}
+}
+
+// UpdateSource updates the grid's copy of the source at the given index.
+// If the source's activity changed, or its energy drifted by more than
+// SourceEnergyChangeThreshold from the cached value, the grid points within the
+// source's effective radius are marked dirty. Returns true if any region was invalidated.
+func (cg *ConcentrationGrid) UpdateSource(index int, source types.ChemicalSource) bool {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+
+	if index < 0 || index >= len(cg.Sources) {
+		return false
+	}
+
+	cached := cg.Sources[index]
+	if cached.IsActive == source.IsActive &&
+		math.Abs(cached.Energy-source.Energy) <= cached.Energy*SourceEnergyChangeThreshold {
+		return false
+	}
+
+	cg.Sources[index] = source
+	cg.markDirtyAround(source)
+
+	// Overlays cover the sources that are active and sum every source's field,
+	// so any change here is rebuilt into them lazily
+	cg.buildOverlays()
+	return true
+}
+
+// DirtyCount returns the number of grid points waiting to be recomputed
+func (cg *ConcentrationGrid) DirtyCount() int {
+	cg.mu.RLock()
+	defer cg.mu.RUnlock()
+
+	return cg.dirtyCount
+}
+
+// GridCache is the cached contents of a concentration grid: the sources it last
+// saw, its values and which of them are waiting to be recomputed
+type GridCache struct {
+	Sources []types.ChemicalSource `json:"sources"`
+	Values  [][]float64            `json:"values"`
+	Dirty   [][]bool               `json:"dirty"`
+}
+
+// Cache returns a copy of the grid's cached contents
+func (cg *ConcentrationGrid) Cache() GridCache {
+	cg.mu.RLock()
+	defer cg.mu.RUnlock()
+
+	cache := GridCache{
+		Sources: append([]types.ChemicalSource(nil), cg.Sources...),
+		Values:  make([][]float64, cg.NumCellsX),
+		Dirty:   make([][]bool, cg.NumCellsX),
+	}
+	for x := range cg.Grid {
+		cache.Values[x] = append([]float64(nil), cg.Grid[x]...)
+		cache.Dirty[x] = append([]bool(nil), cg.dirty[x]...)
+	}
+	return cache
+}
+
+// RestoreCache replaces the grid's cached contents with a copy of cache, so it
+// answers queries exactly as the grid it was taken from did. Returns false, leaving
+// the grid unchanged, if the cache is for a grid of a different size.
+func (cg *ConcentrationGrid) RestoreCache(cache GridCache) bool {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+
+	if len(cache.Values) != cg.NumCellsX || len(cache.Dirty) != cg.NumCellsX {
+		return false
+	}
+	for x := range cache.Values {
+		if len(cache.Values[x]) != cg.NumCellsY || len(cache.Dirty[x]) != cg.NumCellsY {
+			return false
 		}
 	}
 
-	// If we found a nearby source, return its concentration
-	if nearestSource != nil && minDist < cg.Width/5 {
-		return nearestSource.GetConcentrationAt(point)
+	cg.Sources = append([]types.ChemicalSource(nil), cache.Sources...)
+	cg.buildOverlays()
+	cg.dirtyCount = 0
+	for x := range cache.Values {
+		copy(cg.Grid[x], cache.Values[x])
+		copy(cg.dirty[x], cache.Dirty[x])
+		for _, dirty := range cache.Dirty[x] {
+			if dirty {
+				cg.dirtyCount++
+			}
+		}
 	}
+	return true
+}
 
-	return totalConcentration
+// CacheStats returns the number of queries served from cached values (hits)
+// and the number that required recomputing grid points (misses)
+func (cg *ConcentrationGrid) CacheStats() (hits, misses int64) {
+	return atomic.LoadInt64(&cg.hits), atomic.LoadInt64(&cg.misses)
 }
 
-// GetGradientAt returns the gradient of the concentration field at the specified world coordinates
-// This simplified version calculates direction toward nearest chemical source
-func (cg *ConcentrationGrid) GetGradientAt(point types.Point) types.Point {
-	// Find the nearest active chemical source
-	minDist := math.MaxFloat64
-	var nearestSource *types.ChemicalSource
+// GetConcentrationAt returns the concentration value at the specified world coordinates,
+// interpolated from the surrounding grid points
+func (cg *ConcentrationGrid) GetConcentrationAt(point types.Point) float64 {
+	// Points outside the grid are calculated directly from the sources
+	if point.X < 0 || point.X >= cg.Width || point.Y < 0 || point.Y >= cg.Height {
+		cg.mu.RLock()
+		defer cg.mu.RUnlock()
+		return cg.directConcentration(point)
+	}
 
-	for i := range cg.Sources {
-		source := &cg.Sources[i]
-		if !source.IsActive {
+	// Near a source, the fine overlay is more accurate than the base grid
+	if value, ok := cg.overlayConcentration(point); ok {
+		return value
+	}
+
+	x0, y0, x1, y1, fx, fy := cg.cellCorners(point)
+
+	// Fast path: all four corners are cached
+	cg.mu.RLock()
+	if !cg.dirty[x0][y0] && !cg.dirty[x1][y0] && !cg.dirty[x0][y1] && !cg.dirty[x1][y1] {
+		value := cg.interpolate(x0, y0, x1, y1, fx, fy)
+		cg.mu.RUnlock()
+		atomic.AddInt64(&cg.hits, 1)
+		return value
+	}
+	cg.mu.RUnlock()
+
+	// Slow path: recompute the dirty corners under the write lock
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+
+	for _, corner := range [4][2]int{{x0, y0}, {x1, y0}, {x0, y1}, {x1, y1}} {
+		if cg.dirty[corner[0]][corner[1]] {
+			cg.recompute(corner[0], corner[1])
+		}
+	}
+	atomic.AddInt64(&cg.misses, 1)
+
+	return cg.interpolate(x0, y0, x1, y1, fx, fy)
+}
+
+// GetConcentrationsAt returns the concentration at each of the given points. Cached
+// values are read under a single lock acquisition for the whole batch; only points
+// touching dirty grid points fall back to individual lookups.
+func (cg *ConcentrationGrid) GetConcentrationsAt(points []types.Point) []float64 {
+	values := make([]float64, len(points))
+	var pending []int
+	var hits int64
+
+	cg.mu.RLock()
+	for i, point := range points {
+		// Points outside the grid are calculated directly from the sources
+		if point.X < 0 || point.X >= cg.Width || point.Y < 0 || point.Y >= cg.Height {
+			values[i] = cg.directConcentration(point)
 			continue
 		}
 
-		dist := source.Position.DistanceTo(point)
-		if dist < minDist {
-			minDist = dist
-			nearestSource = source
+		// Overlay lookups take their own path
+		if cg.overlayAt(point) != nil {
+			pending = append(pending, i)
+			continue
+		}
+
+		x0, y0, x1, y1, fx, fy := cg.cellCorners(point)
+		if cg.dirty[x0][y0] || cg.dirty[x1][y0] || cg.dirty[x0][y1] || cg.dirty[x1][y1] {
+			pending = append(pending, i)
+			continue
 		}
+		values[i] = cg.interpolate(x0, y0, x1, y1, fx, fy)
+		hits++
 	}
+	cg.mu.RUnlock()
+	atomic.AddInt64(&cg.hits, hits)
 
-	// If we found a source, return direction toward it
-	if nearestSource != nil {
-		// Vector from point to source
-		dx := nearestSource.Position.X - point.X
-		dy := nearestSource.Position.Y - point.Y
+	// Recompute the dirty corners for the remaining points
+	for _, i := range pending {
+		values[i] = cg.GetConcentrationAt(points[i])
+	}
 
-		// Normalize
-		length := math.Sqrt(dx*dx + dy*dy)
-		if length > 1e-9 {
-			return types.Point{X: dx / length, Y: dy / length}
-		}
+	return values
+}
+
+// GetGradientAt returns the normalized gradient of the concentration field at the
+// specified world coordinates
+func (cg *ConcentrationGrid) GetGradientAt(point types.Point) types.Point {
+	gradient := cg.GetGradientVectorAt(point)
+
+	// Normalize if not zero
+	length := math.Sqrt(gradient.X*gradient.X + gradient.Y*gradient.Y)
+	if length < 1e-9 {
+		return types.Point{X: 0, Y: 0}
+	}
+
+	return types.Point{X: gradient.X / length, Y: gradient.Y / length}
+}
+
+// GetGradientVectorAt returns the unnormalized gradient of the concentration field,
+// using central differences over one cell. Its length is the local steepness.
+func (cg *ConcentrationGrid) GetGradientVectorAt(point types.Point) types.Point {
+	h := cg.CellSize
+
+	dCdx := cg.GetConcentrationAt(types.Point{X: point.X + h, Y: point.Y}) -
+		cg.GetConcentrationAt(types.Point{X: point.X - h, Y: point.Y})
+	dCdy := cg.GetConcentrationAt(types.Point{X: point.X, Y: point.Y + h}) -
+		cg.GetConcentrationAt(types.Point{X: point.X, Y: point.Y - h})
+
+	return types.Point{X: dCdx / (2 * h), Y: dCdy / (2 * h)}
+}
+
+// cellCorners returns the indices of the grid points surrounding a point and the
+// fractional position of the point between them
+func (cg *ConcentrationGrid) cellCorners(point types.Point) (x0, y0, x1, y1 int, fx, fy float64) {
+	gx := point.X / cg.CellSize
+	gy := point.Y / cg.CellSize
+
+	x0 = int(math.Floor(gx))
+	y0 = int(math.Floor(gy))
+	fx = gx - float64(x0)
+	fy = gy - float64(y0)
+
+	// Clamp to the last grid point at the far edges
+	x0 = clampIndex(x0, cg.NumCellsX)
+	y0 = clampIndex(y0, cg.NumCellsY)
+	x1 = clampIndex(x0+1, cg.NumCellsX)
+	y1 = clampIndex(y0+1, cg.NumCellsY)
+
+	return x0, y0, x1, y1, fx, fy
+}
+
+// clampIndex keeps an index within [0, n)
+func clampIndex(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
 	}
+	return i
+}
+
+// interpolate performs bilinear interpolation between four grid points.
+// Caller must hold the lock.
+func (cg *ConcentrationGrid) interpolate(x0, y0, x1, y1 int, fx, fy float64) float64 {
+	bottom := cg.Grid[x0][y0]*(1-fx) + cg.Grid[x1][y0]*fx
+	top := cg.Grid[x0][y1]*(1-fx) + cg.Grid[x1][y1]*fx
+	return bottom*(1-fy) + top*fy
+}
 
-	// No nearby sources or at source position
-	return types.Point{X: 0, Y: 0}
+// recompute calculates the concentration at a grid point from the sources.
+// Caller must hold the write lock.
+func (cg *ConcentrationGrid) recompute(x, y int) {
+	point := types.Point{X: float64(x) * cg.CellSize, Y: float64(y) * cg.CellSize}
+	cg.Grid[x][y] = cg.directConcentration(point)
+	cg.clearDirty(x, y)
 }
 
-// Note: Contouring functionality has been removed to improve performance.
+// directConcentration sums the contribution of every source at a point.
+// Caller must hold the lock.
+func (cg *ConcentrationGrid) directConcentration(point types.Point) float64 {
+	var totalConcentration float64
+	for i := range cg.Sources {
+		totalConcentration += cg.Sources[i].GetConcentrationAt(point)
+	}
+	return types.SaturateConcentration(totalConcentration, cg.MaxConcentration)
+}
+
+// markDirtyAround marks all grid points within the source's effective radius as dirty.
+// Caller must hold the write lock.
+func (cg *ConcentrationGrid) markDirtyAround(source types.ChemicalSource) {
+	radius := source.EffectiveRadius()
+
+	minX, maxX := 0, cg.NumCellsX-1
+	minY, maxY := 0, cg.NumCellsY-1
+	if !math.IsInf(radius, 1) {
+		minX = clampIndex(int(math.Floor((source.Position.X-radius)/cg.CellSize)), cg.NumCellsX)
+		maxX = clampIndex(int(math.Ceil((source.Position.X+radius)/cg.CellSize)), cg.NumCellsX)
+		minY = clampIndex(int(math.Floor((source.Position.Y-radius)/cg.CellSize)), cg.NumCellsY)
+		maxY = clampIndex(int(math.Ceil((source.Position.Y+radius)/cg.CellSize)), cg.NumCellsY)
+	}
+
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			point := types.Point{X: float64(x) * cg.CellSize, Y: float64(y) * cg.CellSize}
+			if source.Position.DistanceTo(point) > radius {
+				continue
+			}
+			if !cg.dirty[x][y] {
+				cg.dirty[x][y] = true
+				cg.dirtyCount++
+			}
+		}
+	}
+}
+
+// clearDirty marks a grid point as up to date. Caller must hold the write lock.
+func (cg *ConcentrationGrid) clearDirty(x, y int) {
+	if cg.dirty[x][y] {
+		cg.dirty[x][y] = false
+		cg.dirtyCount--
+	}
+}
@@ -2,8 +2,11 @@ package world
 
 import (
 	"math"
+	"math/rand"
+	"runtime"
 	"testing"
 
+	"github.com/zachbeta/evolve_sim/pkg/config"
 	"github.com/zachbeta/evolve_sim/pkg/types"
 )
 
@@ -168,3 +171,334 @@ func TestGridInterpolation(t *testing.T) {
 		}
 	}
 }
+
+func TestGridDirtyRegionInvalidation(t *testing.T) {
+	grid := NewConcentrationGrid(1000.0, 1000.0, 10.0)
+
+	// A weak source in one corner with a small effective radius
+	source := types.NewChemicalSource(types.Point{X: 100, Y: 100}, 10.0, 1.0)
+	grid.SetSources([]types.ChemicalSource{source})
+	grid.Refresh()
+
+	// Overwrite a cached value far away from the source with a sentinel
+	grid.SetConcentration(80, 80, 42.0)
+
+	// Deplete the source enough to cross the change threshold
+	depleted := source
+	depleted.Energy = source.Energy * 0.5
+	if !grid.UpdateSource(0, depleted) {
+		t.Fatal("Expected UpdateSource to invalidate a region for a 50% energy change")
+	}
+
+	// Only the region around the source should be dirty
+	dirtyCount := grid.DirtyCount()
+	if dirtyCount == 0 || dirtyCount >= grid.NumCellsX*grid.NumCellsY/10 {
+		t.Errorf("Dirty count = %v; want a small region around the source", dirtyCount)
+	}
+
+	// A query outside the dirty region must use the cached (sentinel) value
+	if conc := grid.GetConcentrationAt(types.Point{X: 800, Y: 800}); conc != 42.0 {
+		t.Errorf("Concentration outside dirty region = %v; want cached value 42.0", conc)
+	}
+
+	// A query inside the dirty region must be recomputed from the updated source
+	conc := grid.GetConcentrationAt(types.Point{X: 100, Y: 100})
+	expected := depleted.GetConcentrationAt(types.Point{X: 100, Y: 100})
+	if math.Abs(conc-expected) > 1e-9 {
+		t.Errorf("Concentration inside dirty region = %v; want recomputed %v", conc, expected)
+	}
+
+	// Small changes below the threshold leave the grid untouched
+	nudged := depleted
+	nudged.Energy = depleted.Energy * 0.99
+	if grid.UpdateSource(0, nudged) {
+		t.Error("Expected UpdateSource to ignore a 1% energy change")
+	}
+}
+
+// BenchmarkGridHitRate runs a 1000-step depletion workload and reports the fraction
+// of concentration queries answered from cached grid values
+func BenchmarkGridHitRate(b *testing.B) {
+	cfg := config.SimulationConfig{
+		World: config.WorldConfig{Width: 1000, Height: 1000},
+		Chemical: config.ChemicalConfig{
+			Count:                   5,
+			MinStrength:             100,
+			MaxStrength:             500,
+			MinDecayFactor:          0.001,
+			MaxDecayFactor:          0.01,
+			RegenerationEnabled:     true,
+			RegenerationProbability: 0.2,
+			TargetSystemEnergy:      10000,
+		},
+		RandomSeed: 42,
+	}
+
+	var hits, misses int64
+	for i := 0; i < b.N; i++ {
+		w := NewWorld(cfg)
+		rng := rand.New(rand.NewSource(42))
+
+		for step := 0; step < 1000; step++ {
+			w.UpdateChemicalSources(1.0/6.0, rng)
+			for q := 0; q < 100; q++ {
+				p := types.Point{X: rng.Float64() * 1000, Y: rng.Float64() * 1000}
+				w.GetConcentrationAt(p)
+				w.DepleteEnergyFromSourcesAt(p, 0.01)
+			}
+		}
+
+		if grid := w.GetConcentrationGrid(); grid != nil {
+			h, m := grid.CacheStats()
+			hits += h
+			misses += m
+		}
+	}
+
+	if total := hits + misses; total > 0 {
+		b.ReportMetric(float64(hits)/float64(total), "hit-rate")
+	}
+}
+
+func TestGetConcentrationsAtMatchesIndividualCalls(t *testing.T) {
+	w := NewWorld(config.SimulationConfig{
+		World: config.WorldConfig{Width: 200, Height: 200},
+	})
+	w.AddChemicalSource(types.NewChemicalSource(types.Point{X: 50, Y: 60}, 100, 0.01))
+	w.AddChemicalSource(types.NewChemicalSource(types.Point{X: 150, Y: 120}, 200, 0.005))
+
+	points := []types.Point{
+		{X: 0, Y: 0},
+		{X: 50, Y: 60},
+		{X: 123.4, Y: 56.7},
+		{X: 199.9, Y: 199.9},
+		{X: -10, Y: 50},  // Outside the world
+		{X: 250, Y: 250}, // Outside the world
+	}
+
+	// Compare both without and with a concentration grid
+	for _, withGrid := range []bool{false, true} {
+		if withGrid {
+			w.InitializeConcentrationGrid(10.0)
+		}
+
+		batch := w.GetConcentrationsAt(points)
+		if len(batch) != len(points) {
+			t.Fatalf("GetConcentrationsAt returned %d values; want %d", len(batch), len(points))
+		}
+		for i, p := range points {
+			if want := w.GetConcentrationAt(p); batch[i] != want {
+				t.Errorf("withGrid=%v: batch value at %v = %v; want %v", withGrid, p, batch[i], want)
+			}
+		}
+	}
+}
+
+// sensorBatches returns groups of three nearby points, like one organism's sensors
+func sensorBatches(n int) [][]types.Point {
+	rng := rand.New(rand.NewSource(1))
+	batches := make([][]types.Point, n)
+	for i := range batches {
+		x, y := rng.Float64()*1000, rng.Float64()*1000
+		batches[i] = []types.Point{{X: x + 10, Y: y}, {X: x + 7, Y: y - 7}, {X: x + 7, Y: y + 7}}
+	}
+	return batches
+}
+
+func benchmarkWorld() *World {
+	return NewWorld(config.SimulationConfig{
+		World: config.WorldConfig{Width: 1000, Height: 1000},
+		Chemical: config.ChemicalConfig{
+			Count:          5,
+			MinStrength:    100,
+			MaxStrength:    500,
+			MinDecayFactor: 0.001,
+			MaxDecayFactor: 0.01,
+		},
+		RandomSeed: 42,
+	})
+}
+
+// BenchmarkSensorLookupIndividual reads three sensor concentrations per organism one at a time
+func BenchmarkSensorLookupIndividual(b *testing.B) {
+	w := benchmarkWorld()
+	batches := sensorBatches(1000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, points := range batches {
+			for _, p := range points {
+				w.GetConcentrationAt(p)
+			}
+		}
+	}
+}
+
+// BenchmarkSensorLookupBatch reads three sensor concentrations per organism in one batch
+func BenchmarkSensorLookupBatch(b *testing.B) {
+	w := benchmarkWorld()
+	batches := sensorBatches(1000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, points := range batches {
+			w.GetConcentrationsAt(points)
+		}
+	}
+}
+
+// gridWithRandomSources builds a grid over a width x height world with n random sources
+func gridWithRandomSources(width, height, resolution float64, n int) *ConcentrationGrid {
+	rng := rand.New(rand.NewSource(7))
+	sources := make([]types.ChemicalSource, n)
+	for i := range sources {
+		sources[i] = types.NewChemicalSource(
+			types.Point{X: rng.Float64() * width, Y: rng.Float64() * height},
+			100+rng.Float64()*400,
+			0.001+rng.Float64()*0.009,
+		)
+	}
+
+	grid := NewConcentrationGrid(width, height, resolution)
+	grid.SetSources(sources)
+	return grid
+}
+
+func TestParallelGridFillMatchesSerial(t *testing.T) {
+	serial := gridWithRandomSources(300, 200, 3, 40)
+	parallel := gridWithRandomSources(300, 200, 3, 40)
+
+	serial.refreshDirty(1)
+	parallel.refreshDirty(7) // Deliberately uneven split of columns
+
+	if serial.DirtyCount() != 0 || parallel.DirtyCount() != 0 {
+		t.Fatalf("Dirty counts after fill = %d serial, %d parallel; want 0", serial.DirtyCount(), parallel.DirtyCount())
+	}
+	for x := 0; x < serial.NumCellsX; x++ {
+		for y := 0; y < serial.NumCellsY; y++ {
+			if serial.Grid[x][y] != parallel.Grid[x][y] {
+				t.Fatalf("Grid[%d][%d] = %v parallel; want %v from serial fill", x, y, parallel.Grid[x][y], serial.Grid[x][y])
+			}
+		}
+	}
+}
+
+// BenchmarkGridFill measures filling the grid for a 1000x1000 world at resolution 2
+// with 500 sources, serially and across all CPUs
+func BenchmarkGridFill(b *testing.B) {
+	for _, bc := range []struct {
+		name    string
+		workers int
+	}{
+		{"serial", 1},
+		{"parallel", runtime.GOMAXPROCS(0)},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				grid := gridWithRandomSources(1000, 1000, 2, 500)
+				b.StartTimer()
+
+				grid.refreshDirty(bc.workers)
+			}
+		})
+	}
+}
+
+// meanLookupError returns the mean absolute difference between the grid's lookups
+// and the direct field calculation over points
+func meanLookupError(grid *ConcentrationGrid, points []types.Point) float64 {
+	total := 0.0
+	for _, p := range points {
+		total += math.Abs(grid.GetConcentrationAt(p) - grid.directConcentration(p))
+	}
+	return total / float64(len(points))
+}
+
+// pointsNearSources samples n points within radius of the grid's sources
+func pointsNearSources(grid *ConcentrationGrid, radius float64, n int, rng *rand.Rand) []types.Point {
+	points := make([]types.Point, n)
+	for i := range points {
+		source := grid.Sources[rng.Intn(len(grid.Sources))]
+		points[i] = types.Point{
+			X: source.Position.X + (rng.Float64()*2-1)*radius,
+			Y: source.Position.Y + (rng.Float64()*2-1)*radius,
+		}
+	}
+	return points
+}
+
+func TestFineOverlayImprovesAccuracyNearSources(t *testing.T) {
+	coarse := NewConcentrationGrid(1000, 1000, 20)
+	multiRes := NewConcentrationGrid(1000, 1000, 20)
+	multiRes.SetFineOverlays(5, 40)
+
+	source := types.NewChemicalSource(types.Point{X: 503, Y: 497}, 100, 0.01)
+	for _, grid := range []*ConcentrationGrid{coarse, multiRes} {
+		grid.SetSources([]types.ChemicalSource{source})
+		grid.Refresh()
+	}
+
+	points := pointsNearSources(coarse, 30, 500, rand.New(rand.NewSource(1)))
+	coarseErr := meanLookupError(coarse, points)
+	multiResErr := meanLookupError(multiRes, points)
+	if multiResErr >= coarseErr {
+		t.Errorf("Mean error near source = %v with overlays; want below %v from the coarse grid alone", multiResErr, coarseErr)
+	}
+
+	// Far from the source, lookups still come from the base grid
+	far := types.Point{X: 100, Y: 100}
+	if got, want := multiRes.GetConcentrationAt(far), coarse.GetConcentrationAt(far); got != want {
+		t.Errorf("Concentration far from source = %v; want base grid value %v", got, want)
+	}
+
+	// Deactivating the source removes its overlay
+	source.IsActive = false
+	multiRes.UpdateSource(0, source)
+	if len(multiRes.overlays) != 0 {
+		t.Errorf("Overlays after deactivating the only source = %d; want 0", len(multiRes.overlays))
+	}
+}
+
+// BenchmarkMultiResolutionAccuracy compares a coarse grid with fine overlays against
+// a uniform grid holding the same number of points, reporting each one's mean
+// error near the sources and across the whole world
+func BenchmarkMultiResolutionAccuracy(b *testing.B) {
+	const width, height = 1000.0, 1000.0
+
+	var nearMulti, nearUniform, allMulti, allUniform float64
+	for i := 0; i < b.N; i++ {
+		multiRes := gridWithRandomSources(width, height, 20, 10)
+		multiRes.SetFineOverlays(5, 40)
+		multiRes.Refresh()
+
+		memory := 0
+		for _, column := range multiRes.Grid {
+			memory += len(column)
+		}
+		for _, overlay := range multiRes.overlays {
+			memory += overlay.points * overlay.points
+		}
+
+		uniform := gridWithRandomSources(width, height, math.Sqrt(width*height/float64(memory)), 10)
+		uniform.Refresh()
+
+		rng := rand.New(rand.NewSource(3))
+		near := pointsNearSources(multiRes, 40, 2000, rng)
+		all := make([]types.Point, 2000)
+		for j := range all {
+			all[j] = types.Point{X: rng.Float64() * width, Y: rng.Float64() * height}
+		}
+
+		nearMulti += meanLookupError(multiRes, near)
+		nearUniform += meanLookupError(uniform, near)
+		allMulti += meanLookupError(multiRes, all)
+		allUniform += meanLookupError(uniform, all)
+	}
+
+	n := float64(b.N)
+	b.ReportMetric(nearMulti/n, "near-err-multires")
+	b.ReportMetric(nearUniform/n, "near-err-uniform")
+	b.ReportMetric(allMulti/n, "all-err-multires")
+	b.ReportMetric(allUniform/n, "all-err-uniform")
+}
@@ -0,0 +1,64 @@
+package world
+
+// TriggerCrowdedDispersal starts dispersal for every organism with at least threshold
+// other organisms within radius, so that it scatters for the given duration. Organisms
+// already scattering have their dispersal extended. Returns the number of organisms
+// found crowded.
+func (w *World) TriggerCrowdedDispersal(radius float64, threshold int, duration float64) int {
+	if radius <= 0 || threshold <= 0 || duration <= 0 {
+		return 0
+	}
+
+	w.organismMutex.Lock()
+	defer w.organismMutex.Unlock()
+
+	// Bucket organisms into radius-sized cells so only nearby cells are searched
+	index := w.organismIndex(radius)
+
+	crowded := 0
+	for i := range w.Organisms {
+		org := &w.Organisms[i]
+		neighbors := 0
+		index.forEachNear(org.Position, func(j int) bool {
+			if j != i && org.Position.DistanceTo(w.Organisms[j].Position) <= radius {
+				neighbors++
+			}
+			return true
+		})
+
+		if neighbors >= threshold {
+			org.DispersalTime = duration
+			crowded++
+		}
+	}
+
+	return crowded
+}
+
+// withNearbyMate filters the eligible organism indices in place, keeping those with
+// another eligible organism within radius. Caller must hold organismMutex.
+func (w *World) withNearbyMate(eligible []int, radius float64) []int {
+	if radius <= 0 {
+		return eligible[:0]
+	}
+
+	// Bucket the eligible organisms into radius-sized cells so only nearby cells are searched
+	index := newNeighborIndex(radius)
+	for _, i := range eligible {
+		index.add(i, w.Organisms[i].Position)
+	}
+
+	mated := eligible[:0]
+	for _, i := range eligible {
+		position := w.Organisms[i].Position
+		found := false
+		index.forEachNear(position, func(j int) bool {
+			found = j != i && position.DistanceTo(w.Organisms[j].Position) <= radius
+			return !found
+		})
+		if found {
+			mated = append(mated, i)
+		}
+	}
+	return mated
+}
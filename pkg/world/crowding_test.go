@@ -0,0 +1,58 @@
+package world
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/organism"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+func TestCrowdedOrganismsScatter(t *testing.T) {
+	world := NewWorld(config.SimulationConfig{
+		World: config.WorldConfig{Width: 400, Height: 400},
+	})
+
+	newOrg := func(x, y float64) types.Organism {
+		return types.NewOrganism(types.Point{X: x, Y: y}, 0, 50, 1.0, types.DefaultSensorAngles())
+	}
+
+	// A tight cluster around (100, 100) and a loner far away
+	world.AddOrganism(newOrg(100, 100))
+	for i := 0; i < 8; i++ {
+		angle := float64(i) * math.Pi / 4
+		world.AddOrganism(newOrg(100+5*math.Cos(angle), 100+5*math.Sin(angle)))
+	}
+	world.AddOrganism(newOrg(300, 300))
+
+	crowded := world.TriggerCrowdedDispersal(10.0, 5, 3.0)
+	if crowded == 0 {
+		t.Fatal("Expected the cluster to be found crowded")
+	}
+
+	orgs := world.GetOrganisms()
+	dense, sparse := orgs[0], orgs[len(orgs)-1]
+	if dense.DispersalTime <= 0 {
+		t.Errorf("Dense organism DispersalTime = %v; want positive", dense.DispersalTime)
+	}
+	if sparse.DispersalTime != 0 {
+		t.Errorf("Sparse organism DispersalTime = %v; want 0", sparse.DispersalTime)
+	}
+
+	// With no chemicals the sensors are all alike, so only dispersal turns the organisms
+	headingChange := func(org types.Organism) float64 {
+		total := 0.0
+		for step := 0; step < 10; step++ {
+			before := org.Heading
+			organism.Update(&org, world, world.GetBounds(), 5.0, 0.1, 0.1)
+			total += math.Abs(org.Heading - before)
+		}
+		return total
+	}
+
+	denseChange, sparseChange := headingChange(dense), headingChange(sparse)
+	if denseChange <= sparseChange {
+		t.Errorf("Dense organism heading change = %v; want more than sparse organism's %v", denseChange, sparseChange)
+	}
+}
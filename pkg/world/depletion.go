@@ -0,0 +1,135 @@
+package world
+
+import "github.com/zachbeta/evolve_sim/pkg/types"
+
+// DepletionScale converts the amount an organism consumes into the energy its
+// chemical sources lose
+const DepletionScale = 50.0 // Increased from 5.0 to 50.0 for faster depletion
+
+// depletionShares returns the energy each source should lose when amount is
+// consumed over the disc of radius around position, split in proportion to each
+// active source's strongest concentration within the disc. Returns nil if no active
+// source reaches the disc.
+func depletionShares(sources []types.ChemicalSource, position types.Point, radius, amount float64) []float64 {
+	// Calculate how much each source contributes to the concentration fed on
+	totalConcentration := 0.0
+	shares := make([]float64, len(sources))
+	for i, source := range sources {
+		if source.IsActive {
+			shares[i] = source.GetConcentrationAt(nearestPointWithin(position, radius, source.Position))
+			totalConcentration += shares[i]
+		}
+	}
+
+	// No concentration means no sources to deplete
+	if totalConcentration <= 0 {
+		return nil
+	}
+
+	for i := range shares {
+		shares[i] = amount * (shares[i] / totalConcentration) * DepletionScale
+	}
+	return shares
+}
+
+// nearestPointWithin returns the point within radius of center that is closest to target
+func nearestPointWithin(center types.Point, radius float64, target types.Point) types.Point {
+	dist := center.DistanceTo(target)
+	if dist <= radius {
+		return target
+	}
+	if radius <= 0 {
+		return center
+	}
+
+	t := radius / dist
+	return types.Point{
+		X: center.X + (target.X-center.X)*t,
+		Y: center.Y + (target.Y-center.Y)*t,
+	}
+}
+
+// depleteSource removes up to amount of energy from the source at index i,
+// deactivating it once drained, and takes what was removed out of the system
+// total. Caller must hold sourceMutex.
+func (w *World) depleteSource(i int, amount float64) {
+	// Don't deplete more than available
+	source := &w.ChemicalSources[i]
+	if amount > source.Energy {
+		amount = source.Energy
+	}
+
+	// Deplete the source and track the energy removed from the system
+	source.Energy -= amount
+	w.totalSystemEnergy -= amount
+
+	// Check for depletion
+	if source.Energy <= 0 {
+		source.Energy = 0
+		source.IsActive = false
+	}
+
+	// Refresh the grid region around this source if it changed significantly
+	w.syncGridSource(i)
+}
+
+// DepletionAccumulator records chemical depletion without touching the world, so
+// parallel workers can each feed from their own accumulator without contending
+// for the source lock. It works from a copy of the sources taken when it was
+// created; MergeDepletion applies the recorded totals to the world in one pass.
+type DepletionAccumulator struct {
+	sources []types.ChemicalSource
+	amounts map[int]float64 // Energy to remove from each source, by index
+}
+
+// NewDepletionAccumulator returns an empty accumulator over the world's current sources
+func (w *World) NewDepletionAccumulator() *DepletionAccumulator {
+	return &DepletionAccumulator{
+		sources: w.GetChemicalSources(),
+		amounts: make(map[int]float64),
+	}
+}
+
+// DepleteEnergyFromSourcesAt records the depletion DepleteEnergyFromSourcesAt on the
+// world would apply, with the sources as they were when the accumulator was created
+func (a *DepletionAccumulator) DepleteEnergyFromSourcesAt(position types.Point, amount float64) {
+	a.DepleteEnergyFromSourcesWithin(position, 0, amount)
+}
+
+// DepleteEnergyFromSourcesWithin is the accumulating form of the world's
+// DepleteEnergyFromSourcesWithin
+func (a *DepletionAccumulator) DepleteEnergyFromSourcesWithin(position types.Point, radius, amount float64) {
+	for i, share := range depletionShares(a.sources, position, radius, amount) {
+		if share > 0 {
+			a.amounts[i] += share
+		}
+	}
+}
+
+// Amount returns the energy recorded for removal from the source at index i
+func (a *DepletionAccumulator) Amount(i int) float64 {
+	return a.amounts[i]
+}
+
+// MergeDepletion applies the depletion recorded by each accumulator to the world's
+// sources under a single lock, then empties the accumulators. A source is drained
+// at most to zero, however much was recorded against it. Recordings for sources
+// removed since the accumulator was created are dropped.
+func (w *World) MergeDepletion(accumulators ...*DepletionAccumulator) {
+	totals := make(map[int]float64)
+	for _, accumulator := range accumulators {
+		for i, amount := range accumulator.amounts {
+			totals[i] += amount
+		}
+		clear(accumulator.amounts)
+	}
+
+	w.sourceMutex.Lock()
+	defer w.sourceMutex.Unlock()
+
+	for i, amount := range totals {
+		if i < len(w.ChemicalSources) && w.ChemicalSources[i].IsActive {
+			w.depleteSource(i, amount)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package world
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// depletionWorld returns a world with a few large sources and no organisms
+func depletionWorld() *World {
+	return NewWorld(config.SimulationConfig{
+		World: config.WorldConfig{Width: 1000, Height: 1000},
+		Chemical: config.ChemicalConfig{
+			Count:          5,
+			MinStrength:    100,
+			MaxStrength:    500,
+			MinDecayFactor: 0.001,
+			MaxDecayFactor: 0.01,
+		},
+		RandomSeed: 3,
+	})
+}
+
+// feedingPoints returns n random positions with a small consumption at each
+func feedingPoints(n int) ([]types.Point, []float64) {
+	rng := rand.New(rand.NewSource(9))
+	positions := make([]types.Point, n)
+	amounts := make([]float64, n)
+	for i := range positions {
+		positions[i] = types.Point{X: rng.Float64() * 1000, Y: rng.Float64() * 1000}
+		amounts[i] = rng.Float64() * 0.01
+	}
+	return positions, amounts
+}
+
+func TestMergedDepletionMatchesSumOfIndividualDepletions(t *testing.T) {
+	const workers = 4
+	positions, amounts := feedingPoints(500)
+
+	merged := depletionWorld()
+	initial := merged.GetChemicalSources()
+	initialTotal, _ := merged.GetSystemEnergyInfo()
+
+	// What each organism's feeding takes from each source, summed
+	want := make([]float64, len(initial))
+	for i, position := range positions {
+		for j, share := range depletionShares(initial, position, 0, amounts[i]) {
+			want[j] += share
+		}
+	}
+
+	accumulators := make([]*DepletionAccumulator, workers)
+	for w := range accumulators {
+		accumulators[w] = merged.NewDepletionAccumulator()
+	}
+	for i, position := range positions {
+		accumulators[i%workers].DepleteEnergyFromSourcesAt(position, amounts[i])
+	}
+	merged.MergeDepletion(accumulators...)
+
+	wantTotal := 0.0
+	for i, source := range merged.GetChemicalSources() {
+		depleted := initial[i].Energy - source.Energy
+		if math.Abs(depleted-want[i]) > 1e-9*want[i]+1e-12 {
+			t.Errorf("Source %d lost %v after merge; want %v, the sum of individual depletions", i, depleted, want[i])
+		}
+		wantTotal += want[i]
+	}
+	if total, _ := merged.GetSystemEnergyInfo(); math.Abs(initialTotal-total-wantTotal) > 1e-6 {
+		t.Errorf("System energy fell by %v after merge; want %v", initialTotal-total, wantTotal)
+	}
+	for w, accumulator := range accumulators {
+		if len(accumulator.amounts) != 0 {
+			t.Errorf("Accumulator %d still holds %d amounts after merging; want it emptied", w, len(accumulator.amounts))
+		}
+	}
+
+	// Depleting one organism at a time sees the sources shrink as it goes, so it
+	// differs from the merge only by that drift
+	direct := depletionWorld()
+	for i, position := range positions {
+		direct.DepleteEnergyFromSourcesAt(position, amounts[i])
+	}
+	for i, source := range direct.GetChemicalSources() {
+		if depleted := initial[i].Energy - source.Energy; math.Abs(depleted-want[i]) > 1e-3*want[i]+1e-12 {
+			t.Errorf("Source %d lost %v depleted one at a time; want about %v", i, depleted, want[i])
+		}
+	}
+}
+
+func TestMergeDepletionDrainsSourceAtMostToZero(t *testing.T) {
+	w := depletionWorld()
+	source := w.GetChemicalSources()[0]
+
+	accumulator := w.NewDepletionAccumulator()
+	accumulator.DepleteEnergyFromSourcesAt(source.Position, source.MaxEnergy)
+	w.MergeDepletion(accumulator)
+
+	if drained := w.GetChemicalSources()[0]; drained.Energy != 0 || drained.IsActive {
+		t.Errorf("Source after over-depletion: energy %v, active %v; want 0 and inactive", drained.Energy, drained.IsActive)
+	}
+}
+
+// BenchmarkDepletionContention feeds 2000 organisms from parallel workers, either
+// depleting the world directly under its lock for every organism or accumulating
+// per worker and merging once
+func BenchmarkDepletionContention(b *testing.B) {
+	const organisms = 2000
+	positions, amounts := feedingPoints(organisms)
+	workers := runtime.GOMAXPROCS(0)
+
+	// runWorkers splits the organisms between the workers and waits for them all
+	runWorkers := func(feed func(worker, i int)) {
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for worker := 0; worker < workers; worker++ {
+			go func(worker int) {
+				defer wg.Done()
+				for i := worker; i < organisms; i += workers {
+					feed(worker, i)
+				}
+			}(worker)
+		}
+		wg.Wait()
+	}
+
+	b.Run("locked", func(b *testing.B) {
+		w := depletionWorld()
+		for n := 0; n < b.N; n++ {
+			runWorkers(func(_, i int) {
+				w.DepleteEnergyFromSourcesAt(positions[i], amounts[i]*1e-6)
+			})
+		}
+	})
+
+	b.Run("accumulated", func(b *testing.B) {
+		w := depletionWorld()
+		for n := 0; n < b.N; n++ {
+			accumulators := make([]*DepletionAccumulator, workers)
+			for worker := range accumulators {
+				accumulators[worker] = w.NewDepletionAccumulator()
+			}
+			runWorkers(func(worker, i int) {
+				accumulators[worker].DepleteEnergyFromSourcesAt(positions[i], amounts[i]*1e-6)
+			})
+			w.MergeDepletion(accumulators...)
+		}
+	})
+}
+
+func TestLargerOrganismDepletesOverWiderRadius(t *testing.T) {
+	w := depletionWorld()
+	source := w.GetChemicalSources()[0]
+
+	// Just beyond the source's reach, by more than a small organism's feeding
+	// radius but less than a large one's
+	small, large := types.Organism{Size: 0.5}, types.Organism{Size: 2}
+	gap := (small.FeedingRadius() + large.FeedingRadius()) / 2
+	position := types.Point{X: source.Position.X + source.EffectiveRadius() + gap, Y: source.Position.Y}
+	if source.GetConcentrationAt(position) != 0 {
+		t.Fatalf("Concentration at test position = %v; want it outside the source's reach", source.GetConcentrationAt(position))
+	}
+
+	accumulators := map[string]*DepletionAccumulator{}
+	for name, org := range map[string]types.Organism{"small": small, "large": large} {
+		accumulators[name] = w.NewDepletionAccumulator()
+		accumulators[name].DepleteEnergyFromSourcesWithin(position, org.FeedingRadius(), 1.0)
+	}
+
+	if got := accumulators["small"].Amount(0); got != 0 {
+		t.Errorf("Small organism depleted %v from a source beyond its feeding radius; want 0", got)
+	}
+	if got := accumulators["large"].Amount(0); got <= 0 {
+		t.Errorf("Large organism depleted %v from a source within its feeding radius; want a positive amount", got)
+	}
+
+	// The world applies the same reach
+	before := w.GetChemicalSources()[0].Energy
+	w.DepleteEnergyFromSourcesWithin(position, small.FeedingRadius(), 1.0)
+	if after := w.GetChemicalSources()[0].Energy; after != before {
+		t.Errorf("Source energy changed from %v to %v after small organism fed; want unchanged", before, after)
+	}
+	w.DepleteEnergyFromSourcesWithin(position, large.FeedingRadius(), 1.0)
+	if after := w.GetChemicalSources()[0].Energy; after >= before {
+		t.Errorf("Source energy = %v after large organism fed; want below %v", after, before)
+	}
+}
@@ -0,0 +1,76 @@
+package world
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// DiffWorlds describes the first differences between two worlds' organisms and
+// chemical sources, for tracking down where supposedly identical runs diverge.
+// It returns nil if the worlds are in the same state.
+func DiffWorlds(a, b *World) []string {
+	var diffs []string
+
+	organismsA, organismsB := a.GetOrganisms(), b.GetOrganisms()
+	if len(organismsA) != len(organismsB) {
+		diffs = append(diffs, fmt.Sprintf("organism count: %d vs %d", len(organismsA), len(organismsB)))
+	}
+	for i := range min(len(organismsA), len(organismsB)) {
+		if diff := diffOrganisms(&organismsA[i], &organismsB[i]); diff != "" {
+			diffs = append(diffs, fmt.Sprintf("organism %d (ID %d): %s", i, organismsA[i].ID, diff))
+			break
+		}
+	}
+
+	sourcesA, sourcesB := a.GetChemicalSources(), b.GetChemicalSources()
+	if len(sourcesA) != len(sourcesB) {
+		diffs = append(diffs, fmt.Sprintf("chemical source count: %d vs %d", len(sourcesA), len(sourcesB)))
+	}
+	for i := range min(len(sourcesA), len(sourcesB)) {
+		if diff := diffSources(sourcesA[i], sourcesB[i]); diff != "" {
+			diffs = append(diffs, fmt.Sprintf("chemical source %d: %s", i, diff))
+			break
+		}
+	}
+
+	return diffs
+}
+
+// diffOrganisms describes how two organisms differ, checking the fields most
+// likely to drift first, or returns "" if they're identical
+func diffOrganisms(a, b *types.Organism) string {
+	switch {
+	case a.ID != b.ID:
+		return fmt.Sprintf("ID %d vs %d", a.ID, b.ID)
+	case a.Position != b.Position:
+		return fmt.Sprintf("position (%v, %v) vs (%v, %v)", a.Position.X, a.Position.Y, b.Position.X, b.Position.Y)
+	case a.Energy != b.Energy:
+		return fmt.Sprintf("energy %v vs %v", a.Energy, b.Energy)
+	case a.Heading != b.Heading:
+		return fmt.Sprintf("heading %v vs %v", a.Heading, b.Heading)
+	case a.ChemPreference != b.ChemPreference:
+		return fmt.Sprintf("preference %v vs %v", a.ChemPreference, b.ChemPreference)
+	case !reflect.DeepEqual(*a, *b):
+		return "other state differs"
+	default:
+		return ""
+	}
+}
+
+// diffSources describes how two chemical sources differ, or returns "" if they're identical
+func diffSources(a, b types.ChemicalSource) string {
+	switch {
+	case a.Position != b.Position:
+		return fmt.Sprintf("position (%v, %v) vs (%v, %v)", a.Position.X, a.Position.Y, b.Position.X, b.Position.Y)
+	case a.Energy != b.Energy:
+		return fmt.Sprintf("energy %v vs %v", a.Energy, b.Energy)
+	case a.IsActive != b.IsActive:
+		return fmt.Sprintf("active %v vs %v", a.IsActive, b.IsActive)
+	case a != b:
+		return "other state differs"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,51 @@
+package world
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+)
+
+func TestDiffWorlds(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Organism.Count = 20
+
+	// newWorldPair returns two worlds in the same state
+	newWorldPair := func() (*World, *World) {
+		a, b := NewWorld(cfg), NewWorld(cfg)
+		b.ApplyScenario(a.CurrentScenario())
+		return a, b
+	}
+
+	t.Run("Identical worlds", func(t *testing.T) {
+		if diffs := DiffWorlds(newWorldPair()); len(diffs) != 0 {
+			t.Errorf("DiffWorlds() = %v; want no differences", diffs)
+		}
+	})
+
+	t.Run("Altered energy", func(t *testing.T) {
+		a, b := newWorldPair()
+		organisms := b.GetOrganisms()
+		organisms[5].Energy += 1
+		b.UpdateOrganisms(organisms)
+
+		diffs := DiffWorlds(a, b)
+		if len(diffs) != 1 {
+			t.Fatalf("DiffWorlds() = %v; want exactly one difference", diffs)
+		}
+		if !strings.HasPrefix(diffs[0], "organism 5 ") || !strings.Contains(diffs[0], "energy") {
+			t.Errorf("DiffWorlds() = %q; want the energy of organism 5", diffs[0])
+		}
+	})
+
+	t.Run("Extra organism", func(t *testing.T) {
+		a, b := newWorldPair()
+		b.AddOrganism(b.GetOrganisms()[0])
+
+		diffs := DiffWorlds(a, b)
+		if len(diffs) != 1 || diffs[0] != "organism count: 20 vs 21" {
+			t.Errorf("DiffWorlds() = %v; want only the organism count", diffs)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package world
+
+import (
+	"math"
+	"sync/atomic"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// gridOverlay is a finer grid laid over the square around one source, where the
+// field peaks too sharply for the base grid to resolve
+type gridOverlay struct {
+	origin   types.Point // World position of the overlay's first grid point
+	cellSize float64     // Spacing between the overlay's grid points
+	points   int         // Grid points along each side
+	values   [][]float64 // Cached concentration at each grid point, indexed [x][y]
+	stale    bool        // Whether values must be recomputed before use
+}
+
+// contains reports whether point lies within the overlay
+func (o *gridOverlay) contains(point types.Point) bool {
+	extent := float64(o.points-1) * o.cellSize
+	return point.X >= o.origin.X && point.X <= o.origin.X+extent &&
+		point.Y >= o.origin.Y && point.Y <= o.origin.Y+extent
+}
+
+// interpolate returns the bilinearly interpolated concentration at a point inside the overlay
+func (o *gridOverlay) interpolate(point types.Point) float64 {
+	gx := (point.X - o.origin.X) / o.cellSize
+	gy := (point.Y - o.origin.Y) / o.cellSize
+	x0 := clampIndex(int(math.Floor(gx)), o.points)
+	y0 := clampIndex(int(math.Floor(gy)), o.points)
+	x1 := clampIndex(x0+1, o.points)
+	y1 := clampIndex(y0+1, o.points)
+	fx, fy := gx-float64(x0), gy-float64(y0)
+
+	bottom := o.values[x0][y0]*(1-fx) + o.values[x1][y0]*fx
+	top := o.values[x0][y1]*(1-fx) + o.values[x1][y1]*fx
+	return bottom*(1-fy) + top*fy
+}
+
+// SetFineOverlays gives every active source a finer grid covering the square within
+// radius of it, with factor times the base grid's resolution. Lookups inside an
+// overlay use it instead of the base grid. A factor below 2 or a non-positive
+// radius removes the overlays.
+func (cg *ConcentrationGrid) SetFineOverlays(factor int, radius float64) {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+
+	cg.FineFactor = factor
+	cg.FineRadius = radius
+	cg.buildOverlays()
+}
+
+// buildOverlays lays a stale overlay over each active source, replacing any
+// existing ones. Caller must hold the write lock.
+func (cg *ConcentrationGrid) buildOverlays() {
+	cg.overlays = nil
+	if cg.FineFactor < 2 || cg.FineRadius <= 0 {
+		return
+	}
+
+	cellSize := cg.CellSize / float64(cg.FineFactor)
+	points := int(math.Ceil(2*cg.FineRadius/cellSize)) + 1
+	for _, source := range cg.Sources {
+		if !source.IsActive {
+			continue
+		}
+
+		values := make([][]float64, points)
+		for x := range values {
+			values[x] = make([]float64, points)
+		}
+		cg.overlays = append(cg.overlays, &gridOverlay{
+			origin:   types.Point{X: source.Position.X - cg.FineRadius, Y: source.Position.Y - cg.FineRadius},
+			cellSize: cellSize,
+			points:   points,
+			values:   values,
+			stale:    true,
+		})
+	}
+}
+
+// overlayAt returns the overlay covering point, or nil. Caller must hold the lock.
+func (cg *ConcentrationGrid) overlayAt(point types.Point) *gridOverlay {
+	for _, overlay := range cg.overlays {
+		if overlay.contains(point) {
+			return overlay
+		}
+	}
+	return nil
+}
+
+// overlayConcentration returns the concentration at point from the overlay covering
+// it, recomputing the overlay first if it is stale. The second result is false if
+// no overlay covers the point.
+func (cg *ConcentrationGrid) overlayConcentration(point types.Point) (float64, bool) {
+	cg.mu.RLock()
+	overlay := cg.overlayAt(point)
+	if overlay == nil {
+		cg.mu.RUnlock()
+		return 0, false
+	}
+	if !overlay.stale {
+		value := overlay.interpolate(point)
+		cg.mu.RUnlock()
+		atomic.AddInt64(&cg.hits, 1)
+		return value, true
+	}
+	cg.mu.RUnlock()
+
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+
+	// The overlays may have been rebuilt while the lock was released
+	overlay = cg.overlayAt(point)
+	if overlay == nil {
+		return cg.directConcentration(point), true
+	}
+	if overlay.stale {
+		for x := range overlay.values {
+			for y := range overlay.values[x] {
+				overlay.values[x][y] = cg.directConcentration(types.Point{
+					X: overlay.origin.X + float64(x)*overlay.cellSize,
+					Y: overlay.origin.Y + float64(y)*overlay.cellSize,
+				})
+			}
+		}
+		overlay.stale = false
+	}
+	atomic.AddInt64(&cg.misses, 1)
+
+	return overlay.interpolate(point), true
+}
@@ -0,0 +1,63 @@
+package world
+
+import (
+	"math"
+)
+
+// Kin energy sharing thresholds, as fractions of energy capacity
+const (
+	KinShareDonorThreshold     = 0.6 // Organisms above this share their surplus
+	KinShareRecipientThreshold = 0.3 // Organisms below this receive from kin
+)
+
+// ShareEnergyAmongKin lets organisms with surplus energy give some of it to needy
+// members of the same lineage within radius. Each donor gives at most rate*deltaTime
+// per call, never dropping below KinShareDonorThreshold, and each recipient is topped
+// up to at most KinShareRecipientThreshold. Energy is moved, never created, so the
+// population's total energy is conserved. Returns the total amount transferred.
+func (w *World) ShareEnergyAmongKin(radius, rate, deltaTime float64) float64 {
+	if radius <= 0 || rate <= 0 || deltaTime <= 0 {
+		return 0
+	}
+
+	w.organismMutex.Lock()
+	defer w.organismMutex.Unlock()
+
+	// Bucket organisms into radius-sized cells so only nearby cells are searched
+	index := w.organismIndex(radius)
+
+	transferred := 0.0
+	for i := range w.Organisms {
+		donor := &w.Organisms[i]
+		budget := math.Min(rate*deltaTime, donor.Energy-donor.EnergyCapacity*KinShareDonorThreshold)
+		if budget <= 0 {
+			continue
+		}
+
+		lineage := donor.RootAncestor()
+		index.forEachNear(donor.Position, func(j int) bool {
+			if j == i {
+				return true
+			}
+
+			kin := &w.Organisms[j]
+			if kin.RootAncestor() != lineage || donor.Position.DistanceTo(kin.Position) > radius {
+				return true
+			}
+
+			need := kin.EnergyCapacity*KinShareRecipientThreshold - kin.Energy
+			if need <= 0 {
+				return true
+			}
+
+			amount := math.Min(need, budget)
+			donor.Energy -= amount
+			kin.Energy += amount
+			budget -= amount
+			transferred += amount
+			return budget > 0
+		})
+	}
+
+	return transferred
+}
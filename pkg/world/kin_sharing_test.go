@@ -0,0 +1,69 @@
+package world
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+func TestShareEnergyAmongKin(t *testing.T) {
+	world := NewWorld(config.SimulationConfig{
+		World: config.WorldConfig{Width: 200, Height: 200},
+	})
+
+	newOrg := func(x, y, energyRatio float64) types.Organism {
+		org := types.NewOrganism(types.Point{X: x, Y: y}, 0, 50, 1.0, types.DefaultSensorAngles())
+		org.Energy = org.EnergyCapacity * energyRatio
+		return org
+	}
+
+	donor := newOrg(100, 100, 1.0)
+	kin := donor.Reproduce()
+	kin.Position = types.Point{X: 105, Y: 100}
+	kin.Energy = kin.EnergyCapacity * 0.1
+
+	stranger := newOrg(95, 100, 0.1) // Just as close and needy, but unrelated
+	distantKin := donor.Reproduce()  // Related, needy, but out of range
+	distantKin.Position = types.Point{X: 180, Y: 180}
+	distantKin.Energy = distantKin.EnergyCapacity * 0.1
+
+	donor.Energy = donor.EnergyCapacity // Reproducing cost the donor energy; refill it
+
+	for _, org := range []types.Organism{donor, kin, stranger, distantKin} {
+		world.AddOrganism(org)
+	}
+
+	totalBefore := 0.0
+	for _, org := range world.GetOrganisms() {
+		totalBefore += org.Energy
+	}
+
+	transferred := world.ShareEnergyAmongKin(20.0, 5.0, 1.0)
+	if transferred <= 0 {
+		t.Fatal("Expected some energy to be shared")
+	}
+
+	orgs := world.GetOrganisms()
+	if orgs[0].Energy >= donor.Energy {
+		t.Errorf("Donor energy = %v; want less than %v", orgs[0].Energy, donor.Energy)
+	}
+	if orgs[1].Energy <= kin.Energy {
+		t.Errorf("Kin energy = %v; want more than %v", orgs[1].Energy, kin.Energy)
+	}
+	if orgs[2].Energy != stranger.Energy {
+		t.Errorf("Unrelated neighbor energy = %v; want unchanged %v", orgs[2].Energy, stranger.Energy)
+	}
+	if orgs[3].Energy != distantKin.Energy {
+		t.Errorf("Distant kin energy = %v; want unchanged %v", orgs[3].Energy, distantKin.Energy)
+	}
+
+	totalAfter := 0.0
+	for _, org := range orgs {
+		totalAfter += org.Energy
+	}
+	if math.Abs(totalAfter-totalBefore) > 1e-9 {
+		t.Errorf("Total energy changed from %v to %v; sharing must conserve energy", totalBefore, totalAfter)
+	}
+}
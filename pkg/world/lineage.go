@@ -0,0 +1,117 @@
+package world
+
+import (
+	"sort"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// LineageShare is the current size of a lineage and its share of the population
+type LineageShare struct {
+	RootID int64   // ID of the lineage's founding ancestor
+	Count  int     // Number of living members
+	Share  float64 // Fraction of the population in this lineage (0-1)
+}
+
+// CountLineages returns the number of organisms descended from each root ancestor
+func CountLineages(organisms []types.Organism) map[int64]int {
+	sizes := make(map[int64]int)
+	for i := range organisms {
+		sizes[organisms[i].RootAncestor()]++
+	}
+	return sizes
+}
+
+// TopLineages returns up to n lineages with the most members among the organisms,
+// largest first. Ties go to the lower root ID so the order is stable.
+func TopLineages(organisms []types.Organism, n int) []LineageShare {
+	shares := make([]LineageShare, 0)
+	for root, count := range CountLineages(organisms) {
+		shares = append(shares, LineageShare{
+			RootID: root,
+			Count:  count,
+			Share:  float64(count) / float64(len(organisms)),
+		})
+	}
+
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Count != shares[j].Count {
+			return shares[i].Count > shares[j].Count
+		}
+		return shares[i].RootID < shares[j].RootID
+	})
+
+	if n >= 0 && len(shares) > n {
+		shares = shares[:n]
+	}
+	return shares
+}
+
+// LineageSizes returns the number of living organisms descended from each root ancestor
+func (w *World) LineageSizes() map[int64]int {
+	w.organismMutex.RLock()
+	defer w.organismMutex.RUnlock()
+
+	return CountLineages(w.Organisms)
+}
+
+// LargestLineage returns the root ancestor with the most living descendants and
+// the number of descendants. Ties go to the lower root ID so the result is stable.
+func (w *World) LargestLineage() (int64, int) {
+	w.organismMutex.RLock()
+	defer w.organismMutex.RUnlock()
+
+	top := TopLineages(w.Organisms, 1)
+	if len(top) == 0 {
+		return 0, 0
+	}
+	return top[0].RootID, top[0].Count
+}
+
+// ExtractLineage returns copies of all living organisms descended from the given root ancestor
+func (w *World) ExtractLineage(rootID int64) []types.Organism {
+	w.organismMutex.RLock()
+	defer w.organismMutex.RUnlock()
+
+	members := make([]types.Organism, 0)
+	for i := range w.Organisms {
+		if w.Organisms[i].RootAncestor() == rootID {
+			members = append(members, w.Organisms[i])
+		}
+	}
+	return members
+}
+
+// ExportLineage writes all living members of a lineage to a scenario file that
+// contains no chemical sources, for transplanting into another world
+func (w *World) ExportLineage(rootID int64, path string) error {
+	return SaveScenarioToFile(Scenario{Organisms: w.ExtractLineage(rootID)}, path)
+}
+
+// TransplantLineage adds the given organisms to the world alongside its current
+// residents. Organisms outside the world boundaries are dropped.
+// Returns the number of organisms added.
+func (w *World) TransplantLineage(organisms []types.Organism) int {
+	w.organismMutex.Lock()
+	defer w.organismMutex.Unlock()
+
+	added := 0
+	for _, org := range organisms {
+		if w.World.AddOrganism(org) {
+			added++
+		}
+	}
+	return added
+}
+
+// LoadLineage reads the organisms from a lineage or scenario file and transplants
+// them into the world. Any chemical sources in the file are ignored.
+// Returns the number of organisms added.
+func (w *World) LoadLineage(path string) (int, error) {
+	scenario, err := LoadScenarioFromFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return w.TransplantLineage(scenario.Organisms), nil
+}
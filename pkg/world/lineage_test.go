@@ -0,0 +1,120 @@
+package world
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+func TestTransplantLineage(t *testing.T) {
+	cfg := config.SimulationConfig{
+		World: config.WorldConfig{Width: 500, Height: 500},
+		Organism: config.OrganismConfig{
+			Count:                        10,
+			Speed:                        2.0,
+			PreferenceDistributionMean:   50.0,
+			PreferenceDistributionStdDev: 10.0,
+		},
+		RandomSeed: 3,
+	}
+	donor := NewWorld(cfg)
+
+	// Grow a three-member lineage from a distinctive founder
+	founder := types.NewOrganism(types.Point{X: 250, Y: 250}, 0, 123.0, 3.5, types.DefaultSensorAngles())
+	founder.TurnBias = -0.6
+	child := founder.Reproduce()
+	grandchild := child.Reproduce()
+	for _, org := range []types.Organism{founder, child, grandchild} {
+		if !donor.AddOrganism(org) {
+			t.Fatalf("Failed to add lineage member at %v", org.Position)
+		}
+	}
+
+	if grandchild.RootAncestor() != founder.ID {
+		t.Fatalf("Grandchild root = %d; want founder %d", grandchild.RootAncestor(), founder.ID)
+	}
+	if root, size := donor.LargestLineage(); root != founder.ID || size != 3 {
+		t.Errorf("LargestLineage() = (%d, %d); want (%d, 3)", root, size, founder.ID)
+	}
+
+	tempDir, err := os.MkdirTemp("", "lineage_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "lineage.json")
+	if err := donor.ExportLineage(founder.ID, path); err != nil {
+		t.Fatalf("Failed to export lineage: %v", err)
+	}
+
+	// Transplant into a fresh world with its own random residents
+	cfg.RandomSeed = 4
+	recipient := NewWorld(cfg)
+	added, err := recipient.LoadLineage(path)
+	if err != nil {
+		t.Fatalf("Failed to load lineage: %v", err)
+	}
+	if added != 3 {
+		t.Errorf("Transplanted %d organisms; want 3", added)
+	}
+	if got := len(recipient.GetOrganisms()); got != cfg.Organism.Count+3 {
+		t.Errorf("Recipient has %d organisms; want %d residents plus 3 transplants", got, cfg.Organism.Count)
+	}
+
+	want := donor.ExtractLineage(founder.ID)
+	got := recipient.ExtractLineage(founder.ID)
+	if len(got) != len(want) {
+		t.Fatalf("Recipient lineage has %d members; want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID || got[i].ChemPreference != want[i].ChemPreference ||
+			got[i].Speed != want[i].Speed || got[i].TurnBias != want[i].TurnBias ||
+			got[i].SensorAngles != want[i].SensorAngles || got[i].Generation != want[i].Generation ||
+			got[i].EnergyEfficiency != want[i].EnergyEfficiency {
+			t.Errorf("Transplanted member %d = %+v; want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTopLineages(t *testing.T) {
+	member := func(id, root int64) types.Organism {
+		return types.Organism{ID: id, RootID: root}
+	}
+
+	// Lineage 7 has three members, 3 and 9 two each, and 5 one. The last organism
+	// predates root tracking and counts as the founder of its own lineage.
+	population := []types.Organism{
+		member(1, 7), member(2, 7), member(3, 7),
+		member(4, 9), member(5, 9),
+		member(6, 3), member(3, 3),
+		member(8, 5),
+		{ID: 11},
+	}
+
+	got := TopLineages(population, 3)
+	want := []LineageShare{
+		{RootID: 7, Count: 3, Share: 3.0 / 9},
+		{RootID: 3, Count: 2, Share: 2.0 / 9},
+		{RootID: 9, Count: 2, Share: 2.0 / 9},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("TopLineages returned %d lineages; want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("TopLineages()[%d] = %+v; want %+v", i, got[i], want[i])
+		}
+	}
+
+	if counts := CountLineages(population); counts[11] != 1 || counts[5] != 1 || len(counts) != 5 {
+		t.Errorf("CountLineages() = %v; want 5 lineages including singletons 5 and 11", counts)
+	}
+
+	if empty := TopLineages(nil, 5); len(empty) != 0 {
+		t.Errorf("TopLineages(nil) = %v; want none", empty)
+	}
+}
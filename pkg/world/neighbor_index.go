@@ -0,0 +1,65 @@
+package world
+
+import (
+	"math"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// cellKey identifies a cell of the spatial hash used to find neighbors
+type cellKey struct {
+	x, y int
+}
+
+// neighborIndex is a spatial hash that buckets indices into square cells by
+// position, so a search for neighbors only visits the cells around a point
+type neighborIndex struct {
+	cellSize float64
+	cells    map[cellKey][]int
+}
+
+// newNeighborIndex returns an empty index with cells of the given size. Neighbors
+// found by forEachNear are only complete up to cellSize away.
+func newNeighborIndex(cellSize float64) *neighborIndex {
+	return &neighborIndex{
+		cellSize: cellSize,
+		cells:    make(map[cellKey][]int),
+	}
+}
+
+// cellOf returns the cell containing position
+func (n *neighborIndex) cellOf(position types.Point) cellKey {
+	return cellKey{int(math.Floor(position.X / n.cellSize)), int(math.Floor(position.Y / n.cellSize))}
+}
+
+// add buckets index i at position
+func (n *neighborIndex) add(i int, position types.Point) {
+	key := n.cellOf(position)
+	n.cells[key] = append(n.cells[key], i)
+}
+
+// forEachNear calls visit with every index bucketed in or around the cell
+// containing position, in a fixed order, until visit returns false. Callers check
+// the actual distance themselves.
+func (n *neighborIndex) forEachNear(position types.Point, visit func(j int) bool) {
+	home := n.cellOf(position)
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			for _, j := range n.cells[cellKey{home.x + dx, home.y + dy}] {
+				if !visit(j) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// organismIndex returns a neighbor index over all of the world's organisms.
+// Caller must hold organismMutex.
+func (w *World) organismIndex(cellSize float64) *neighborIndex {
+	index := newNeighborIndex(cellSize)
+	for i := range w.Organisms {
+		index.add(i, w.Organisms[i].Position)
+	}
+	return index
+}
@@ -0,0 +1,40 @@
+package world
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+func TestNeighborIndexForEachNear(t *testing.T) {
+	index := newNeighborIndex(10)
+	positions := []types.Point{
+		{X: 5, Y: 5},   // Home cell
+		{X: 15, Y: 5},  // Adjacent cell
+		{X: -5, Y: -5}, // Diagonal cell across the origin
+		{X: 25, Y: 5},  // Two cells away
+		{X: 6, Y: 6},   // Home cell again
+	}
+	for i, pos := range positions {
+		index.add(i, pos)
+	}
+
+	var visited []int
+	index.forEachNear(types.Point{X: 5, Y: 5}, func(j int) bool {
+		visited = append(visited, j)
+		return true
+	})
+	if want := []int{2, 0, 4, 1}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("Expected to visit %v in order, got %v", want, visited)
+	}
+
+	visited = nil
+	index.forEachNear(types.Point{X: 5, Y: 5}, func(j int) bool {
+		visited = append(visited, j)
+		return len(visited) < 2
+	})
+	if len(visited) != 2 {
+		t.Errorf("Expected the search to stop after 2 visits, got %v", visited)
+	}
+}
@@ -0,0 +1,35 @@
+package world
+
+import (
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+// PredatorsNear returns, by organism ID, the positions of the organisms within radius
+// whose body is at least sizeRatio times larger, which count as its predators.
+// Organisms with no predators in reach are left out.
+func (w *World) PredatorsNear(sizeRatio, radius float64) map[int64][]types.Point {
+	predators := make(map[int64][]types.Point)
+	if sizeRatio <= 0 || radius <= 0 {
+		return predators
+	}
+
+	w.organismMutex.RLock()
+	defer w.organismMutex.RUnlock()
+
+	// Bucket organisms into radius-sized cells so only nearby cells are searched
+	index := w.organismIndex(radius)
+
+	for i := range w.Organisms {
+		prey := &w.Organisms[i]
+		index.forEachNear(prey.Position, func(j int) bool {
+			predator := &w.Organisms[j]
+			if j != i && predator.BodySize() >= sizeRatio*prey.BodySize() &&
+				prey.Position.DistanceTo(predator.Position) <= radius {
+				predators[prey.ID] = append(predators[prey.ID], predator.Position)
+			}
+			return true
+		})
+	}
+
+	return predators
+}
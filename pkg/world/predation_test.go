@@ -0,0 +1,41 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+func TestPredatorsNear(t *testing.T) {
+	world := NewWorld(config.SimulationConfig{
+		World: config.WorldConfig{Width: 400, Height: 400},
+	})
+
+	newOrg := func(x, y, size float64) types.Organism {
+		org := types.NewOrganism(types.Point{X: x, Y: y}, 0, 50, 1.0, types.DefaultSensorAngles())
+		org.Size = size
+		return org
+	}
+
+	world.AddOrganism(newOrg(100, 100, 1))   // Prey
+	world.AddOrganism(newOrg(110, 100, 2))   // Predator in reach
+	world.AddOrganism(newOrg(100, 110, 1.5)) // Larger, but not enough to hunt
+	world.AddOrganism(newOrg(300, 300, 4))   // Predator out of reach
+
+	orgs := world.GetOrganisms()
+	predators := world.PredatorsNear(2, 25)
+
+	if got := predators[orgs[0].ID]; len(got) != 1 || got[0] != orgs[1].Position {
+		t.Errorf("Prey predators = %v; want only %v", got, orgs[1].Position)
+	}
+	for _, org := range orgs[1:] {
+		if got, ok := predators[org.ID]; ok {
+			t.Errorf("Organism %d predators = %v; want none", org.ID, got)
+		}
+	}
+
+	if got := world.PredatorsNear(0, 25); len(got) != 0 {
+		t.Errorf("PredatorsNear with ratio 0 = %v; want none", got)
+	}
+}
@@ -0,0 +1,90 @@
+package world
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+func TestExportAndLoadScenario(t *testing.T) {
+	cfg := config.SimulationConfig{
+		World: config.WorldConfig{Width: 500, Height: 500},
+		Organism: config.OrganismConfig{
+			Count:                        20,
+			Speed:                        2.0,
+			PreferenceDistributionMean:   50.0,
+			PreferenceDistributionStdDev: 10.0,
+		},
+		Chemical: config.ChemicalConfig{
+			Count:          3,
+			MinStrength:    100,
+			MaxStrength:    200,
+			MinDecayFactor: 0.001,
+			MaxDecayFactor: 0.01,
+		},
+		RandomSeed: 7,
+	}
+
+	original := NewWorld(cfg)
+
+	// Give one organism distinctive evolved traits
+	org, _ := original.GetOrganismAt(0)
+	org.TurnBias = 0.42
+	org.Generation = 9
+	original.UpdateOrganism(0, org)
+
+	tempDir, err := os.MkdirTemp("", "scenario_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "scenario.json")
+	if err := original.ExportScenario(path); err != nil {
+		t.Fatalf("Failed to export scenario: %v", err)
+	}
+
+	// Load into a different, empty world
+	loaded := NewWorld(config.SimulationConfig{
+		World: config.WorldConfig{Width: 500, Height: 500},
+	})
+	if err := loaded.LoadScenario(path); err != nil {
+		t.Fatalf("Failed to load scenario: %v", err)
+	}
+
+	wantOrgs := original.GetOrganisms()
+	gotOrgs := loaded.GetOrganisms()
+	if len(gotOrgs) != len(wantOrgs) {
+		t.Fatalf("Loaded %d organisms; want %d", len(gotOrgs), len(wantOrgs))
+	}
+	for i := range wantOrgs {
+		want, got := wantOrgs[i], gotOrgs[i]
+		if got.Position != want.Position || got.ID != want.ID ||
+			got.ChemPreference != want.ChemPreference || got.Speed != want.Speed ||
+			got.Energy != want.Energy || got.EnergyEfficiency != want.EnergyEfficiency ||
+			got.TurnBias != want.TurnBias || got.Generation != want.Generation {
+			t.Errorf("Organism %d = %+v; want %+v", i, got, want)
+		}
+	}
+
+	wantSources := original.GetChemicalSources()
+	gotSources := loaded.GetChemicalSources()
+	if len(gotSources) != len(wantSources) {
+		t.Fatalf("Loaded %d sources; want %d", len(gotSources), len(wantSources))
+	}
+	for i := range wantSources {
+		if gotSources[i] != wantSources[i] {
+			t.Errorf("Source %d = %+v; want %+v", i, gotSources[i], wantSources[i])
+		}
+	}
+
+	// The loaded world should use the scenario's sources for concentration
+	point := types.Point{X: 250, Y: 250}
+	if !approximatelyEqual(loaded.GetConcentrationAt(point), original.GetConcentrationAt(point), 1e-6) {
+		t.Errorf("Concentration at %v = %v; want %v", point,
+			loaded.GetConcentrationAt(point), original.GetConcentrationAt(point))
+	}
+}
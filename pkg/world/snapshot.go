@@ -0,0 +1,73 @@
+package world
+
+import "github.com/zachbeta/evolve_sim/pkg/types"
+
+// Snapshot is a consistent copy of the world's organisms and chemical sources,
+// taken at a single instant so a frame can be drawn without tearing
+type Snapshot struct {
+	Organisms       []types.Organism
+	ChemicalSources []types.ChemicalSource
+	Grid            *ConcentrationGrid // Concentration grid current at the snapshot (may be nil)
+
+	maxConcentration float64 // The world's concentration cap (0 disables)
+}
+
+// Snapshot copies the organisms and chemical sources while holding every lock
+// that guards them, so no update can land between the two copies. Organism
+// trails and feeding memories are copied too, since the simulator appends to them.
+func (w *World) Snapshot() Snapshot {
+	// Locks are taken in the same order as the source and grid update paths
+	w.sourceMutex.RLock()
+	defer w.sourceMutex.RUnlock()
+	w.organismMutex.RLock()
+	defer w.organismMutex.RUnlock()
+	w.gridMutex.RLock()
+	defer w.gridMutex.RUnlock()
+
+	organisms := make([]types.Organism, len(w.Organisms))
+	for i, org := range w.Organisms {
+		org.PositionHistory = append([]types.TrailPoint(nil), org.PositionHistory...)
+		org.FeedingMemory = append([]types.Point(nil), org.FeedingMemory...)
+		organisms[i] = org
+	}
+
+	sources := make([]types.ChemicalSource, len(w.ChemicalSources))
+	copy(sources, w.ChemicalSources)
+
+	return Snapshot{
+		Organisms:       organisms,
+		ChemicalSources: sources,
+		Grid:            w.concentrationGrid,
+
+		maxConcentration: w.MaxConcentration,
+	}
+}
+
+// GetConcentrationAt returns the concentration at a point, from the snapshot's
+// grid if it has one and directly from its sources otherwise
+func (s Snapshot) GetConcentrationAt(point types.Point) float64 {
+	if s.Grid != nil {
+		return s.Grid.GetConcentrationAt(point)
+	}
+
+	total := 0.0
+	for _, source := range s.ChemicalSources {
+		total += source.GetConcentrationAt(point)
+	}
+	return types.SaturateConcentration(total, s.maxConcentration)
+}
+
+// GetConcentrationGradientVectorAt returns the unnormalized concentration gradient
+// at a point, from the snapshot's grid if it has one and by finite differences otherwise
+func (s Snapshot) GetConcentrationGradientVectorAt(point types.Point) types.Point {
+	if s.Grid != nil {
+		return s.Grid.GetGradientVectorAt(point)
+	}
+
+	const delta = 0.5 // Small distance for finite difference
+	center := s.GetConcentrationAt(point)
+	return types.Point{
+		X: (s.GetConcentrationAt(types.Point{X: point.X + delta, Y: point.Y}) - center) / delta,
+		Y: (s.GetConcentrationAt(types.Point{X: point.X, Y: point.Y + delta}) - center) / delta,
+	}
+}
@@ -0,0 +1,87 @@
+package world
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+	"github.com/zachbeta/evolve_sim/pkg/types"
+)
+
+func TestSnapshotDuringConcurrentUpdates(t *testing.T) {
+	world := NewWorld(config.SimulationConfig{
+		World: config.WorldConfig{Width: 500, Height: 500},
+		Organism: config.OrganismConfig{
+			Count:                        50,
+			Speed:                        1.0,
+			PreferenceDistributionMean:   50.0,
+			PreferenceDistributionStdDev: 10.0,
+		},
+		Chemical: config.ChemicalConfig{
+			Count:          3,
+			MinStrength:    100,
+			MaxStrength:    200,
+			MinDecayFactor: 0.001,
+			MaxDecayFactor: 0.01,
+		},
+		RandomSeed: 11,
+	})
+
+	// The writer stamps every organism and source with the same generation number,
+	// appending to trails in place the way organism updates do
+	const generations = 500
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for gen := 1; gen <= generations; gen++ {
+			organisms := world.GetOrganisms()
+			for i := range organisms {
+				organisms[i].Generation = gen
+				organisms[i].PositionHistory = append(organisms[i].PositionHistory,
+					types.TrailPoint{Position: types.Point{X: float64(gen)}})
+				if len(organisms[i].PositionHistory) > types.MaxTrailLength {
+					organisms[i].PositionHistory = organisms[i].PositionHistory[1:]
+				}
+			}
+
+			// Sources and organisms change together, under their own locks
+			world.sourceMutex.Lock()
+			world.organismMutex.Lock()
+			for i := range world.ChemicalSources {
+				world.ChemicalSources[i].Energy = float64(gen)
+			}
+			world.Organisms = organisms
+			world.organismMutex.Unlock()
+			world.sourceMutex.Unlock()
+		}
+	}()
+
+	for i := 0; i < generations; i++ {
+		snapshot := world.Snapshot()
+		if len(snapshot.Organisms) == 0 || len(snapshot.ChemicalSources) == 0 {
+			t.Fatal("Snapshot missing organisms or sources")
+		}
+
+		gen := snapshot.Organisms[0].Generation
+		for j, org := range snapshot.Organisms {
+			if org.Generation != gen {
+				t.Fatalf("Snapshot %d mixes generations: organism 0 at %d, organism %d at %d", i, gen, j, org.Generation)
+			}
+		}
+		for j, source := range snapshot.ChemicalSources {
+			if gen > 1 && source.Energy != float64(gen) {
+				t.Fatalf("Snapshot %d has organisms at generation %d but source %d at %v", i, gen, j, source.Energy)
+			}
+		}
+
+		// Reading trails must not race with the writer's appends
+		for _, org := range snapshot.Organisms {
+			for _, point := range org.PositionHistory {
+				_ = point.Position.X
+			}
+		}
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,77 @@
+package world
+
+import "github.com/zachbeta/evolve_sim/pkg/types"
+
+// State is everything about a world that changes as it runs, enough to resume it
+// exactly where it left off. The rest comes from the world's config.
+type State struct {
+	Scenario
+	TotalSystemEnergy  float64                  `json:"totalSystemEnergy"`
+	TargetSystemEnergy float64                  `json:"targetSystemEnergy"`
+	SeasonTime         float64                  `json:"seasonTime"`
+	DeathsByCause      map[types.DeathCause]int `json:"deathsByCause"`
+	ReproductionRng    types.RandState          `json:"reproductionRng"`
+	Grid               *GridCache               `json:"grid,omitempty"` // Nil if the grid was invalidated
+}
+
+// SaveState captures the world's current state
+func (w *World) SaveState() State {
+	state := State{
+		Scenario:      w.CurrentScenario(),
+		DeathsByCause: w.DeathsByCause(),
+	}
+
+	w.sourceMutex.RLock()
+	state.SeasonTime = w.seasonTime
+	w.sourceMutex.RUnlock()
+
+	w.organismMutex.RLock()
+	state.ReproductionRng = w.reproductionSource.State()
+	w.organismMutex.RUnlock()
+
+	// The grid lags its sources by up to SourceEnergyChangeThreshold, so its cached
+	// values are saved rather than rebuilt on restore
+	w.gridMutex.RLock()
+	if w.concentrationGrid != nil {
+		cache := w.concentrationGrid.Cache()
+		state.Grid = &cache
+	}
+	w.gridMutex.RUnlock()
+
+	state.TotalSystemEnergy, state.TargetSystemEnergy = w.GetSystemEnergyInfo()
+	return state
+}
+
+// RestoreState replaces the world's organisms, chemical sources, concentration
+// grid, energy accounting and random position with those saved in state. A saved
+// grid that doesn't fit this world's grid is dropped, leaving the grid invalidated
+// as if none had been saved.
+func (w *World) RestoreState(state State) {
+	w.ApplyScenario(state.Scenario)
+	restored := false
+	if state.Grid != nil {
+		w.gridMutex.RLock()
+		restored = w.concentrationGrid.RestoreCache(*state.Grid)
+		w.gridMutex.RUnlock()
+	}
+	if !restored {
+		w.invalidateConcentrationGrid()
+	}
+
+	w.sourceMutex.Lock()
+	w.seasonTime = state.SeasonTime
+	w.sourceMutex.Unlock()
+
+	w.organismMutex.Lock()
+	w.deathsByCause = make(map[types.DeathCause]int, len(state.DeathsByCause))
+	for cause, count := range state.DeathsByCause {
+		w.deathsByCause[cause] = count
+	}
+	w.reproductionRng, w.reproductionSource = types.RestoreRand(w.sourceFactory, state.ReproductionRng)
+	w.organismMutex.Unlock()
+
+	w.energyMutex.Lock()
+	w.totalSystemEnergy = state.TotalSystemEnergy
+	w.targetSystemEnergy = state.TargetSystemEnergy
+	w.energyMutex.Unlock()
+}
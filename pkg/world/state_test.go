@@ -0,0 +1,47 @@
+package world
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zachbeta/evolve_sim/pkg/config"
+)
+
+func TestSaveAndRestoreState(t *testing.T) {
+	cfg := config.SimulationConfig{
+		World:      config.WorldConfig{Width: 300, Height: 300},
+		Organism:   config.OrganismConfig{Count: 20, Speed: 1, PreferenceDistributionMean: 50},
+		Chemical:   config.ChemicalConfig{Count: 3, MinStrength: 100, MaxStrength: 200, MinDecayFactor: 0.001, MaxDecayFactor: 0.01},
+		RandomSeed: 11,
+	}
+	w := NewWorld(cfg)
+
+	// Give the world some history: a death, a reproduction draw and a depleted source
+	organisms := w.GetOrganisms()
+	organisms[0].Energy = 0
+	w.UpdateOrganisms(organisms)
+	w.RemoveDeadOrganisms()
+	w.ProcessReproduction()
+	w.DepleteEnergyFromSourcesAt(w.GetChemicalSources()[0].Position, 5)
+	state := w.SaveState()
+
+	other := cfg
+	other.RandomSeed = 99
+	restored := NewWorld(other)
+	restored.RestoreState(state)
+
+	if got := restored.SaveState(); !reflect.DeepEqual(got, state) {
+		t.Errorf("Restored world saves as %+v; want %+v", got, state)
+	}
+
+	// A grid saved at another size can't be restored, so the grid is invalidated
+	// rather than left out of step with the saved one
+	mismatched := state
+	grid := *state.Grid
+	grid.Values, grid.Dirty = grid.Values[1:], grid.Dirty[1:]
+	mismatched.Grid = &grid
+	restored.RestoreState(mismatched)
+	if got := restored.SaveState().Grid; got != nil {
+		t.Errorf("Restoring a mismatched grid left a %dx%d grid; want it invalidated", len(got.Values), len(got.Values[0]))
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"math"
 	"math/rand"
 	"sync"
-	"time"
 
 	"github.com/zachbeta/evolve_sim/pkg/config"
 	"github.com/zachbeta/evolve_sim/pkg/types"
@@ -24,42 +23,90 @@ type World struct {
 
 	concentrationGrid *ConcentrationGrid
 
+	// Seeded source of randomness for the order in which organisms reproduce,
+	// and its source, which tracks its position. Guarded by organismMutex.
+	reproductionRng    *rand.Rand
+	reproductionSource *types.CountingSource
+
+	// Creates every generator the world uses (nil uses math/rand's default)
+	sourceFactory types.SourceFactory
+
+	// Scratch buffers reused by every reproduction pass to avoid reallocating.
+	// Guarded by organismMutex.
+	eligibleScratch  []int
+	offspringScratch []types.Organism
+
+	// Organisms removed so far, by cause of death. Guarded by organismMutex.
+	deathsByCause map[types.DeathCause]int
+
 	// New fields for energy balance
 	totalSystemEnergy  float64
 	targetSystemEnergy float64
+
+	// Seasonal food cycle and time elapsed in it. Guarded by sourceMutex.
+	season     types.SeasonalCycle
+	seasonTime float64
 }
 
 // NewWorld creates a new world with the specified configuration
 func NewWorld(cfg config.SimulationConfig) *World {
+	return NewWorldWithSource(cfg, nil)
+}
+
+// NewWorldWithSource creates a new world like NewWorld, drawing all of its
+// randomness from generators made by factory
+func NewWorldWithSource(cfg config.SimulationConfig, factory types.SourceFactory) *World {
 	baseWorld := types.NewWorld(cfg.World.Width, cfg.World.Height)
+	baseWorld.MaxConcentration = cfg.Chemical.MaxConcentration
 	world := &World{
 		World:          baseWorld,
 		config:         cfg.World,
 		chemicalConfig: cfg.Chemical, // Store chemical config
+		season: types.SeasonalCycle{
+			Period:    cfg.Chemical.SeasonPeriod,
+			Amplitude: cfg.Chemical.SeasonAmplitude,
+		},
+		sourceFactory: factory,
 	}
 
+	// Seed the reproduction order so runs with the same seed are repeatable
+	world.reproductionRng, world.reproductionSource = types.NewCountingRand(factory, cfg.RandomSeed)
+
 	// Populate the world with organisms and chemical sources
 	world.PopulateWorld(cfg)
 
 	// Calculate initial system energy
-	// Use configured targetSystemEnergy if available, otherwise calculate based on sources
-	if cfg.Chemical.TargetSystemEnergy > 0 {
-		world.targetSystemEnergy = cfg.Chemical.TargetSystemEnergy
-	} else {
-		for _, source := range world.ChemicalSources {
-			world.targetSystemEnergy += source.MaxEnergy
-		}
-	}
-
-	// Initialize total energy to match target
-	world.totalSystemEnergy = world.targetSystemEnergy
+	world.initializeSystemEnergy(cfg.Chemical)
 
 	// Initialize the concentration grid for faster lookups with larger cell size for better performance
-	world.InitializeConcentrationGrid(10.0)
+	world.InitializeConcentrationGrid(DefaultGridResolution)
 
 	return world
 }
 
+// initializeSystemEnergy starts the tracked system energy at what the sources hold.
+// The target is the configured targetSystemEnergy if there is one, otherwise the
+// sources' combined capacity.
+func (w *World) initializeSystemEnergy(cfg config.ChemicalConfig) {
+	w.sourceMutex.RLock()
+	total, capacity := 0.0, 0.0
+	for _, source := range w.ChemicalSources {
+		if source.IsActive {
+			total += source.Energy
+		}
+		capacity += source.MaxEnergy
+	}
+	w.sourceMutex.RUnlock()
+
+	w.energyMutex.Lock()
+	defer w.energyMutex.Unlock()
+	w.totalSystemEnergy = total
+	w.targetSystemEnergy = capacity
+	if cfg.TargetSystemEnergy > 0 {
+		w.targetSystemEnergy = cfg.TargetSystemEnergy
+	}
+}
+
 // GetConfig returns the world configuration
 func (w *World) GetConfig() config.WorldConfig {
 	return w.config
@@ -81,12 +128,43 @@ func (w *World) AddChemicalSource(source types.ChemicalSource) bool {
 
 	success := w.World.AddChemicalSource(source)
 	if success {
-		// Invalidate the concentration grid
-		w.concentrationGrid = nil
+		w.invalidateConcentrationGrid()
 	}
 	return success
 }
 
+// RemoveAllChemicalSources removes every chemical source, taking their energy out
+// of the system total, and invalidates the concentration grid
+func (w *World) RemoveAllChemicalSources() {
+	w.sourceMutex.Lock()
+	defer w.sourceMutex.Unlock()
+
+	removed := 0.0
+	for _, source := range w.ChemicalSources {
+		if source.IsActive {
+			removed += source.Energy
+		}
+	}
+	w.ChemicalSources = []types.ChemicalSource{}
+
+	w.energyMutex.Lock()
+	w.totalSystemEnergy = math.Max(w.totalSystemEnergy-removed, 0)
+	w.energyMutex.Unlock()
+
+	w.invalidateConcentrationGrid()
+}
+
+// SetChemicalSourceStrength sets the strength of every chemical source
+func (w *World) SetChemicalSourceStrength(strength float64) {
+	w.sourceMutex.Lock()
+	defer w.sourceMutex.Unlock()
+
+	for i := range w.ChemicalSources {
+		w.ChemicalSources[i].Strength = strength
+		w.syncGridSource(i)
+	}
+}
+
 // GetOrganisms returns a copy of the organisms slice to avoid concurrent modification
 func (w *World) GetOrganisms() []types.Organism {
 	w.organismMutex.RLock()
@@ -158,18 +236,81 @@ func (w *World) GetConcentrationAt(point types.Point) float64 {
 	return w.World.GetConcentrationAt(point)
 }
 
+// GetExactConcentrationAt returns the concentration at the specified point by summing
+// the chemical sources directly, bypassing the interpolated concentration grid
+func (w *World) GetExactConcentrationAt(point types.Point) float64 {
+	w.sourceMutex.RLock()
+	defer w.sourceMutex.RUnlock()
+
+	return w.World.GetConcentrationAt(point)
+}
+
+// GetExactConcentrationGradientVectorAt returns the gradient at a point computed
+// from the chemical sources directly, bypassing the grid's interpolation
+func (w *World) GetExactConcentrationGradientVectorAt(point types.Point) types.Point {
+	w.sourceMutex.RLock()
+	defer w.sourceMutex.RUnlock()
+
+	return w.World.GetConcentrationGradientAt(point)
+}
+
+// GetExactConcentrationsAt is the batch form of GetExactConcentrationAt
+func (w *World) GetExactConcentrationsAt(points []types.Point) []float64 {
+	w.sourceMutex.RLock()
+	defer w.sourceMutex.RUnlock()
+
+	values := make([]float64, len(points))
+	for i, point := range points {
+		values[i] = w.World.GetConcentrationAt(point)
+	}
+	return values
+}
+
+// GetConcentrationsAt returns the concentration at each of the given points,
+// taking the world's locks once for the whole batch instead of once per point
+func (w *World) GetConcentrationsAt(points []types.Point) []float64 {
+	w.gridMutex.RLock()
+	grid := w.concentrationGrid
+	w.gridMutex.RUnlock()
+
+	if grid != nil {
+		return grid.GetConcentrationsAt(points)
+	}
+
+	// Otherwise calculate directly (slower)
+	return w.GetExactConcentrationsAt(points)
+}
+
 // GetConcentrationGradientAt calculates the gradient (direction of concentration change)
-// at the specified point
+// at the specified point, normalized to unit length
 func (w *World) GetConcentrationGradientAt(point types.Point) types.Point {
+	gradient := w.GetConcentrationGradientVectorAt(point)
+
+	// Normalize if not zero
+	length := math.Sqrt(gradient.X*gradient.X + gradient.Y*gradient.Y)
+	if length > 1e-9 {
+		gradient.X /= length
+		gradient.Y /= length
+	}
+
+	return gradient
+}
+
+// GetConcentrationGradientVectorAt calculates the unnormalized concentration gradient
+// at the specified point. Its length is the local steepness of the field.
+func (w *World) GetConcentrationGradientVectorAt(point types.Point) types.Point {
 	w.gridMutex.RLock()
-	defer w.gridMutex.RUnlock()
+	grid := w.concentrationGrid
+	w.gridMutex.RUnlock()
 
 	// If we have a concentration grid, use it for faster gradient calculation
-	if w.concentrationGrid != nil {
-		return w.concentrationGrid.GetGradientAt(point)
+	if grid != nil {
+		return grid.GetGradientVectorAt(point)
 	}
 
 	// Otherwise, calculate numerically
+	w.sourceMutex.RLock()
+	defer w.sourceMutex.RUnlock()
 	const delta = 0.5 // Small distance for finite difference
 
 	// Calculate concentrations at points slightly offset from the original
@@ -181,32 +322,60 @@ func (w *World) GetConcentrationGradientAt(point types.Point) types.Point {
 	dCdx := (cRight - cCenter) / delta
 	dCdy := (cUp - cCenter) / delta
 
-	// Return the gradient vector
-	gradient := types.Point{X: dCdx, Y: dCdy}
+	return types.Point{X: dCdx, Y: dCdy}
+}
 
-	// Normalize if not zero
-	length := math.Sqrt(gradient.X*gradient.X + gradient.Y*gradient.Y)
-	if length > 1e-9 {
-		gradient.X /= length
-		gradient.Y /= length
-	}
+// DefaultGridResolution is the cell size of the concentration grid in world units
+const DefaultGridResolution = 10.0
 
-	return gradient
+// InitializeConcentrationGrid initializes the concentration grid for faster lookups.
+// The caller must not hold any of the world's locks.
+func (w *World) InitializeConcentrationGrid(resolution float64) {
+	w.rebuildConcentrationGrid(resolution)
 }
 
-// InitializeConcentrationGrid initializes the concentration grid for faster lookups
-func (w *World) InitializeConcentrationGrid(resolution float64) {
-	w.gridMutex.Lock()
-	defer w.gridMutex.Unlock()
+// rebuildConcentrationGrid builds a grid from the current sources, installs it and
+// returns it. The caller must not hold any of the world's locks.
+func (w *World) rebuildConcentrationGrid(resolution float64) *ConcentrationGrid {
+	// Hold the source lock until the grid is installed, so a source added meanwhile
+	// can't be missed by the new grid. Source lock, then grid lock, as in the
+	// source update paths.
+	w.sourceMutex.RLock()
+	defer w.sourceMutex.RUnlock()
 
 	grid := NewConcentrationGrid(w.Width, w.Height, resolution)
+	grid.MaxConcentration = w.MaxConcentration
+	grid.SetFineOverlays(w.chemicalConfig.FineGridFactor, w.chemicalConfig.FineGridRadius)
+	grid.SetSources(w.ChemicalSources)
+	grid.Refresh()
 
-	// Instead of calculating concentrations at each grid point,
-	// just give the grid a reference to our chemical sources
-	sources := w.GetChemicalSources()
-	grid.SetSources(sources)
+	w.gridMutex.Lock()
+	defer w.gridMutex.Unlock()
 
 	w.concentrationGrid = grid
+	return grid
+}
+
+// invalidateConcentrationGrid drops the concentration grid, so lookups use the
+// sources directly until GetConcentrationGrid rebuilds it. Caller must not hold gridMutex.
+func (w *World) invalidateConcentrationGrid() {
+	w.gridMutex.Lock()
+	defer w.gridMutex.Unlock()
+
+	w.concentrationGrid = nil
+}
+
+// syncGridSource pushes the current state of the source at index i to the
+// concentration grid, which invalidates only the region that source influences.
+// Caller must hold sourceMutex.
+func (w *World) syncGridSource(i int) {
+	w.gridMutex.RLock()
+	grid := w.concentrationGrid
+	w.gridMutex.RUnlock()
+
+	if grid != nil {
+		grid.UpdateSource(i, w.ChemicalSources[i])
+	}
 }
 
 // GetBounds returns the world boundaries as a Rect
@@ -219,8 +388,9 @@ func (w *World) UpdateOrganisms(organisms []types.Organism) {
 	w.organismMutex.Lock()
 	defer w.organismMutex.Unlock()
 
-	// Validate that all organisms are within bounds
-	validOrganisms := make([]types.Organism, 0, len(organisms))
+	// Copy the organisms within bounds into the existing backing array, reusing its
+	// capacity. GetOrganisms hands out copies, so nobody else holds this array.
+	validOrganisms := w.Organisms[:0]
 	for _, org := range organisms {
 		if w.Boundaries.Contains(org.Position) {
 			validOrganisms = append(validOrganisms, org)
@@ -228,7 +398,17 @@ func (w *World) UpdateOrganisms(organisms []types.Organism) {
 	}
 
 	// Replace the organisms
-	w.Organisms = validOrganisms
+	w.Organisms = compactOrganisms(w.Organisms, validOrganisms)
+}
+
+// compactOrganisms returns kept, a prefix of the backing array of old, after zeroing
+// the slots of old it no longer uses so they don't keep stale organisms or their
+// histories alive
+func compactOrganisms(old, kept []types.Organism) []types.Organism {
+	if len(old) > len(kept) {
+		clear(old[len(kept):])
+	}
+	return kept
 }
 
 // PopulateWorld fills the world with organisms and chemical sources based on configuration
@@ -236,12 +416,11 @@ func (w *World) PopulateWorld(cfg config.SimulationConfig) {
 	w.organismMutex.Lock()
 	defer w.organismMutex.Unlock()
 
-	// Create a random number generator with the provided seed
-	rng := rand.New(rand.NewSource(cfg.RandomSeed))
-	if cfg.RandomSeed == 0 {
-		// If no seed is provided, use current time
-		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
-	}
+	// Create a random number generator with the provided seed. Sources and organisms,
+	// traits and IDs included, draw only from this generator, so a nonzero seed
+	// reproduces the layout.
+	// Without a seed, the current time is used.
+	rng := types.NewRand(w.sourceFactory, cfg.RandomSeed)
 
 	// Add chemical sources
 	for i := 0; i < cfg.Chemical.Count; i++ {
@@ -284,25 +463,50 @@ func (w *World) PopulateWorld(cfg config.SimulationConfig) {
 		x = math.Max(1.0, math.Min(w.Width-1.0, x))
 		y = math.Max(1.0, math.Min(w.Height-1.0, y))
 
-		// Random heading
-		heading := rng.Float64() * 2 * math.Pi
+		// Heading from the configured distribution
+		heading := w.initialHeading(rng, cfg.Organism, types.Point{X: x, Y: y})
+
+		// Draw chemical preference from the configured distribution
+		preference := samplePreference(rng, cfg.Organism)
 
-		// Normal distribution for chemical preference
-		preference := rng.NormFloat64()*cfg.Organism.PreferenceDistributionStdDev + cfg.Organism.PreferenceDistributionMean
+		// Spread circadian phases around the cycle when the trait is in use
+		circadianPhase := 0.0
+		if cfg.Organism.CircadianStrength > 0 {
+			circadianPhase = rng.Float64() * 2 * math.Pi
+		}
 
 		// Create organism config from simulation config
 		organismConfig := types.OrganismConfig{
-			InitialEnergy:         cfg.Energy.InitialEnergy,
-			MaximumEnergy:         cfg.Energy.MaximumEnergy,
-			BaseMetabolicRate:     cfg.Energy.BaseMetabolicRate,
-			MovementCostFactor:    cfg.Energy.MovementCostFactor,
-			SensingCostBase:       cfg.Energy.SensingCostBase,
-			OptimalEnergyGainRate: cfg.Energy.OptimalEnergyGainRate,
-			EnergyEfficiencyRange: cfg.Energy.EnergyEfficiencyRange,
+			InitialEnergy:          cfg.Energy.InitialEnergy,
+			MaximumEnergy:          cfg.Energy.MaximumEnergy,
+			BaseMetabolicRate:      cfg.Energy.BaseMetabolicRate,
+			MovementCostFactor:     cfg.Energy.MovementCostFactor,
+			SensingCostBase:        cfg.Energy.SensingCostBase,
+			OptimalEnergyGainRate:  cfg.Energy.OptimalEnergyGainRate,
+			EnergyEfficiencyRange:  cfg.Energy.EnergyEfficiencyRange,
+			HungerSpeedBoost:       cfg.Energy.HungerSpeedBoost,
+			BoundaryCollisionCost:  cfg.Energy.BoundaryCollisionCost,
+			ReserveCapacityRatio:   cfg.Energy.ReserveCapacityRatio,
+			ReserveTransferRate:    cfg.Energy.ReserveTransferRate,
+			FeedingMemorySize:      cfg.Organism.FeedingMemorySize,
+			MinSensorSpread:        cfg.Organism.MinSensorSpread,
+			SensorFieldOfView:      cfg.Organism.SensorFieldOfView,
+			CircadianStrength:      cfg.Organism.CircadianStrength,
+			GainEfficiencyBudget:   cfg.Energy.GainEfficiencyBudget,
+			PlasticityRate:         cfg.Organism.PlasticityRate,
+			ImprintStrength:        cfg.Organism.ImprintStrength,
+			ForagingStrategy:       cfg.Organism.ForagingStrategy,
+			ScaleCooldown:          cfg.Reproduction.ScaleCooldown,
+			DormancyThreshold:      cfg.Energy.DormancyThreshold,
+			DormantMetabolicFactor: cfg.Energy.DormantMetabolicFactor,
+			MaxAge:                 cfg.Organism.MaxAge,
+			FlowThroughFeeding:     cfg.Energy.FlowThroughFeeding,
+			InitialSize:            cfg.Organism.InitialSize,
 		}
 
 		// Create and add organism with energy configuration
-		organism := types.NewOrganismWithConfig(
+		organism := types.NewOrganismWithRand(
+			rng,
 			types.Point{X: x, Y: y},
 			heading,
 			preference,
@@ -310,11 +514,38 @@ func (w *World) PopulateWorld(cfg config.SimulationConfig) {
 			types.DefaultSensorAngles(),
 			organismConfig,
 		)
+		organism.CircadianPhase = circadianPhase
 		w.World.AddOrganism(organism)
 	}
 
 	// Reset the concentration grid
-	w.concentrationGrid = nil
+	w.invalidateConcentrationGrid()
+}
+
+// initialHeading returns the starting heading of an organism at position, as set by
+// the configured distribution. Only the random distribution draws from rng.
+func (w *World) initialHeading(rng *rand.Rand, cfg config.OrganismConfig, position types.Point) float64 {
+	switch cfg.InitialHeading {
+	case config.InitialHeadingFixed:
+		return cfg.InitialHeadingAngle
+	case config.InitialHeadingTowardCenter:
+		return math.Atan2(w.Height/2-position.Y, w.Width/2-position.X)
+	default:
+		return rng.Float64() * 2 * math.Pi
+	}
+}
+
+// samplePreference draws an initial chemical preference from the configured distribution.
+// The bimodal distribution picks the secondary peak with probability SecondaryPreferenceWeight;
+// both peaks share the same standard deviation.
+func samplePreference(rng *rand.Rand, cfg config.OrganismConfig) float64 {
+	mean := cfg.PreferenceDistributionMean
+	if cfg.PreferenceDistribution == config.PreferenceDistributionBimodal &&
+		rng.Float64() < cfg.SecondaryPreferenceWeight {
+		mean = cfg.SecondaryPreferenceMean
+	}
+
+	return rng.NormFloat64()*cfg.PreferenceDistributionStdDev + mean
 }
 
 // Reset resets the world to its initial state
@@ -324,10 +555,12 @@ func (w *World) Reset(cfg config.SimulationConfig) {
 
 	// Clear organisms and chemical sources
 	w.Organisms = []types.Organism{}
+	w.deathsByCause = nil
 	w.ChemicalSources = []types.ChemicalSource{}
+	w.seasonTime = 0
 
 	// Reset concentration grid
-	w.concentrationGrid = nil
+	w.invalidateConcentrationGrid()
 
 	// Unlock mutex temporarily to allow nested locks in PopulateWorld
 	w.organismMutex.Unlock()
@@ -336,34 +569,32 @@ func (w *World) Reset(cfg config.SimulationConfig) {
 	w.PopulateWorld(cfg)
 
 	// Re-initialize the concentration grid
-	w.InitializeConcentrationGrid(10.0)
+	w.InitializeConcentrationGrid(DefaultGridResolution)
+
+	// Track the new sources' energy
+	w.initializeSystemEnergy(cfg.Chemical)
 
 	// Re-lock mutex to satisfy defer w.organismMutex.Unlock()
 	w.organismMutex.Lock()
+
+	// Restart the reproduction order, so a reset seeded run repeats a fresh one
+	w.reproductionRng, w.reproductionSource = types.NewCountingRand(w.sourceFactory, cfg.RandomSeed)
 }
 
-// GetConcentrationGrid returns the current concentration grid
+// GetConcentrationGrid returns the current concentration grid, rebuilding it from
+// the current sources if it has been invalidated
 func (w *World) GetConcentrationGrid() *ConcentrationGrid {
 	w.gridMutex.RLock()
-	defer w.gridMutex.RUnlock()
-
-	// Ensure the grid is initialized
-	if w.concentrationGrid == nil {
-		// Release the read lock
-		w.gridMutex.RUnlock()
-
-		// Acquire a write lock to initialize the grid
-		w.gridMutex.Lock()
-		// Check again in case another thread initialized it while we were waiting
-		if w.concentrationGrid == nil {
-			w.InitializeConcentrationGrid(10.0)
-		}
-		// Downgrade to a read lock
-		w.gridMutex.Unlock()
-		w.gridMutex.RLock()
+	grid := w.concentrationGrid
+	w.gridMutex.RUnlock()
+	if grid != nil {
+		return grid
 	}
 
-	return w.concentrationGrid
+	// The rebuild takes the source lock, which must come before the grid lock,
+	// so no grid lock may be held here. Concurrent callers may each rebuild; the
+	// last one installed wins, and every one is built from the current sources.
+	return w.rebuildConcentrationGrid(DefaultGridResolution)
 }
 
 // RemoveOrganism removes an organism at the specified index
@@ -383,24 +614,77 @@ func (w *World) RemoveOrganism(index int) bool {
 
 // RemoveDeadOrganisms removes all organisms with zero or negative energy
 func (w *World) RemoveDeadOrganisms() int {
+	return len(w.RemoveDeadOrganismsDetailed())
+}
+
+// RemoveDeadOrganismsDetailed removes all dead organisms like RemoveDeadOrganisms,
+// returning copies of them as they were when they died
+func (w *World) RemoveDeadOrganismsDetailed() []types.Organism {
 	w.organismMutex.Lock()
 	defer w.organismMutex.Unlock()
 
-	aliveOrganisms := make([]types.Organism, 0, len(w.Organisms))
-	removedCount := 0
-
-	// Keep only organisms with positive energy
+	// Keep only living organisms, filtering in place and tallying why the rest died.
+	// The lock is held until the list is rebuilt so readers never see it half done.
+	if w.deathsByCause == nil {
+		w.deathsByCause = make(map[types.DeathCause]int)
+	}
+	aliveOrganisms := w.Organisms[:0]
+	var removed []types.Organism
 	for _, org := range w.Organisms {
-		if org.Energy > 0 {
+		if cause := org.CauseOfDeath(); cause == "" {
 			aliveOrganisms = append(aliveOrganisms, org)
 		} else {
-			removedCount++
+			w.deathsByCause[cause]++
+			removed = append(removed, org)
 		}
 	}
 
 	// Update the organisms list
-	w.Organisms = aliveOrganisms
-	return removedCount
+	w.Organisms = compactOrganisms(w.Organisms, aliveOrganisms)
+	return removed
+}
+
+// DeathsByCause returns how many organisms have been removed for each cause of
+// death since the world was created or last reset
+func (w *World) DeathsByCause() map[types.DeathCause]int {
+	w.organismMutex.RLock()
+	defer w.organismMutex.RUnlock()
+
+	deaths := make(map[types.DeathCause]int, len(w.deathsByCause))
+	for cause, count := range w.deathsByCause {
+		deaths[cause] = count
+	}
+	return deaths
+}
+
+// ChemicalEnergyInfo summarizes the energy left in the sources of one chemical type
+type ChemicalEnergyInfo struct {
+	Energy          float64 // Energy remaining in the active sources
+	MaxEnergy       float64 // Combined capacity of all the sources, active or not
+	ActiveSources   int
+	DepletedSources int
+}
+
+// ChemicalEnergyBreakdown returns the energy remaining in the world's chemical
+// sources, keyed by chemical type. Depleted sources hold no energy, matching how
+// they're counted in the system total.
+func (w *World) ChemicalEnergyBreakdown() map[int]ChemicalEnergyInfo {
+	w.sourceMutex.RLock()
+	defer w.sourceMutex.RUnlock()
+
+	breakdown := make(map[int]ChemicalEnergyInfo)
+	for _, source := range w.ChemicalSources {
+		info := breakdown[source.Type]
+		info.MaxEnergy += source.MaxEnergy
+		if source.IsActive {
+			info.Energy += source.Energy
+			info.ActiveSources++
+		} else {
+			info.DepletedSources++
+		}
+		breakdown[source.Type] = info
+	}
+	return breakdown
 }
 
 // Reproduction and population constants
@@ -410,7 +694,7 @@ const (
 
 // ProcessReproduction checks all organisms for reproduction eligibility
 // and creates offspring as needed
-// Returns the number of reproductions and their positions
+// Returns the number of reproductions and where the offspring were born
 func (w *World) ProcessReproduction() (int, []types.Point) {
 	return w.ProcessReproductionWithConfig(config.ReproductionConfig{
 		MaxPopulation: DefaultMaxOrganismCount,
@@ -419,8 +703,14 @@ func (w *World) ProcessReproduction() (int, []types.Point) {
 
 // ProcessReproductionWithConfig checks all organisms for reproduction eligibility
 // and creates offspring based on the provided configuration
-// Returns the number of reproductions that occurred and the positions where they happened
+// Returns the number of reproductions that occurred and the offspring's birth positions
 func (w *World) ProcessReproductionWithConfig(cfg config.ReproductionConfig) (int, []types.Point) {
+	// Births that draw on the environment need the sources too; lock them first,
+	// in the same order as Snapshot
+	if cfg.EnvironmentCost > 0 {
+		w.sourceMutex.Lock()
+		defer w.sourceMutex.Unlock()
+	}
 	w.organismMutex.Lock()
 	defer w.organismMutex.Unlock()
 
@@ -434,38 +724,127 @@ func (w *World) ProcessReproductionWithConfig(cfg config.ReproductionConfig) (in
 		return 0, nil
 	}
 
-	// Create a slice to hold new organisms
-	newOrganisms := make([]types.Organism, 0)
+	// Hold new organisms in a reused buffer until they're all created
+	newOrganisms := w.offspringScratch[:0]
 
 	// Track reproduction event positions
-	reproductionPositions := make([]types.Point, 0)
+	var reproductionPositions []types.Point
 
 	// Track how many new organisms were created
 	reproductionCount := 0
 
-	// Check each organism for reproduction
+	// Collect the organisms that are ready to reproduce
+	eligible := w.eligibleScratch[:0]
 	for i := range w.Organisms {
-		if w.Organisms[i].CanReproduce() && len(w.Organisms)+len(newOrganisms) < maxPopulation {
-			// Create a new organism
-			offspring := w.Organisms[i].Reproduce()
+		if w.Organisms[i].CanReproduce() {
+			eligible = append(eligible, i)
+		}
+	}
+
+	// Density-dependent mating: offspring are still clones, but only organisms with
+	// a potential mate close by get to have them
+	if cfg.RequireMate {
+		eligible = w.withNearbyMate(eligible, cfg.MateRadius)
+	}
+
+	// Visit them in a seeded random order so that, near the population cap,
+	// lower-indexed organisms aren't systematically favored
+	w.reproductionRng.Shuffle(len(eligible), func(a, b int) {
+		eligible[a], eligible[b] = eligible[b], eligible[a]
+	})
 
-			// Ensure the offspring is within world bounds
-			if w.Boundaries.Contains(offspring.Position) {
-				newOrganisms = append(newOrganisms, offspring)
-				reproductionCount++
+	// Check each organism for reproduction
+	for _, i := range eligible {
+		// Throttle births so a wave of eligible organisms is spread over several steps
+		if cfg.MaxReproductionsPerStep > 0 && reproductionCount >= cfg.MaxReproductionsPerStep {
+			break
+		}
 
-				// Track the position where reproduction occurred
-				reproductionPositions = append(reproductionPositions, w.Organisms[i].Position)
+		if len(w.Organisms)+len(newOrganisms) < maxPopulation {
+			// Create a new organism, remembering what it cost the parent
+			parent := &w.Organisms[i]
+			energy, sinceReproduction := parent.Energy, parent.TimeSinceReproduction
+			offspring := parent.ReproduceWithRand(w.reproductionRng)
+
+			// Discard offspring placed out of bounds, and births the local environment
+			// can't pay for, refunding the parent so a failed birth costs nothing
+			if !w.Boundaries.Contains(offspring.Position) ||
+				(cfg.EnvironmentCost > 0 && !w.withdrawFromSourcesAt(parent.Position, cfg.EnvironmentCost)) {
+				parent.Energy, parent.TimeSinceReproduction = energy, sinceReproduction
+				continue
 			}
+
+			newOrganisms = append(newOrganisms, offspring)
+			reproductionCount++
+
+			// Track where the offspring was born
+			reproductionPositions = append(reproductionPositions, offspring.Position)
 		}
 	}
 
-	// Add all new organisms to the world
+	// Add all new organisms to the world, then empty the buffers for the next pass
+	// so they don't keep the offspring's histories alive
 	w.Organisms = append(w.Organisms, newOrganisms...)
+	clear(newOrganisms)
+	w.offspringScratch = newOrganisms[:0]
+	w.eligibleScratch = eligible[:0]
 
 	return reproductionCount, reproductionPositions
 }
 
+// withdrawFromSourcesAt takes exactly amount of energy from the active sources
+// reaching position, in proportion to their concentration there. If those sources
+// hold less than amount in total, nothing is taken and false is returned.
+// Caller must hold sourceMutex.
+func (w *World) withdrawFromSourcesAt(position types.Point, amount float64) bool {
+	contributions := make([]float64, len(w.ChemicalSources))
+	totalConcentration := 0.0
+	available := 0.0
+	for i, source := range w.ChemicalSources {
+		if !source.IsActive {
+			continue
+		}
+		if conc := source.GetConcentrationAt(position); conc > 0 {
+			contributions[i] = conc
+			totalConcentration += conc
+			available += source.Energy
+		}
+	}
+
+	if totalConcentration <= 0 || available < amount {
+		return false
+	}
+
+	// Take each source's share, capped at what it holds, then cover any shortfall
+	// from whichever contributing sources still have energy
+	remaining := amount
+	for pass := 0; pass < 2 && remaining > 0; pass++ {
+		for i := range w.ChemicalSources {
+			if contributions[i] <= 0 || remaining <= 0 {
+				continue
+			}
+
+			take := remaining
+			if pass == 0 {
+				take = amount * contributions[i] / totalConcentration
+			}
+			take = math.Min(take, w.ChemicalSources[i].Energy)
+
+			w.ChemicalSources[i].Energy -= take
+			w.totalSystemEnergy -= take
+			remaining -= take
+
+			if w.ChemicalSources[i].Energy <= 0 {
+				w.ChemicalSources[i].Energy = 0
+				w.ChemicalSources[i].IsActive = false
+			}
+			w.syncGridSource(i)
+		}
+	}
+
+	return true
+}
+
 // GetPopulationInfo returns information about the current population
 func (w *World) GetPopulationInfo() (int, float64) {
 	w.organismMutex.RLock()
@@ -488,55 +867,20 @@ func (w *World) GetPopulationInfo() (int, float64) {
 
 // DepleteEnergyFromSourcesAt removes energy from chemical sources based on organism consumption
 func (w *World) DepleteEnergyFromSourcesAt(position types.Point, amount float64) {
+	w.DepleteEnergyFromSourcesWithin(position, 0, amount)
+}
+
+// DepleteEnergyFromSourcesWithin removes energy from chemical sources like
+// DepleteEnergyFromSourcesAt for an organism feeding over a disc of radius around
+// position, such as one with its FeedingRadius
+func (w *World) DepleteEnergyFromSourcesWithin(position types.Point, radius, amount float64) {
 	w.sourceMutex.Lock()
 	defer w.sourceMutex.Unlock()
 
-	// Calculate how much each source contributes to the concentration at this position
-	totalConcentration := 0.0
-	sourceConcentrations := make([]float64, len(w.ChemicalSources))
-
-	for i, source := range w.ChemicalSources {
-		if source.IsActive {
-			conc := source.GetConcentrationAt(position)
-			sourceConcentrations[i] = conc
-			totalConcentration += conc
-		}
-	}
-
-	// No concentration means no sources to deplete
-	if totalConcentration <= 0 {
-		return
-	}
-
-	// Distribute depletion proportionally based on concentration contribution
-	for i := range w.ChemicalSources {
-		if sourceConcentrations[i] > 0 {
-			// Calculate proportion of total concentration from this source
-			proportion := sourceConcentrations[i] / totalConcentration
-
-			// Calculate how much energy to remove from this source
-			depletionAmount := amount * proportion * 50.0 // Increased from 5.0 to 50.0 for faster depletion
-
-			// Don't deplete more than available
-			originalEnergy := w.ChemicalSources[i].Energy
-			if depletionAmount > originalEnergy {
-				depletionAmount = originalEnergy
-			}
-
-			// Deplete the source
-			w.ChemicalSources[i].Energy -= depletionAmount
-
-			// Track total energy removed from the system
-			w.totalSystemEnergy -= depletionAmount
-
-			// Check for depletion
-			if w.ChemicalSources[i].Energy <= 0 {
-				w.ChemicalSources[i].Energy = 0
-				w.ChemicalSources[i].IsActive = false
-
-				// Invalidate the concentration grid when a source becomes inactive
-				w.concentrationGrid = nil
-			}
+	// Distribute depletion proportionally based on each source's concentration contribution
+	for i, depletionAmount := range depletionShares(w.ChemicalSources, position, radius, amount) {
+		if depletionAmount > 0 {
+			w.depleteSource(i, depletionAmount)
 		}
 	}
 }
@@ -546,6 +890,9 @@ func (w *World) UpdateChemicalSources(deltaTime float64, rng *rand.Rand) {
 	w.sourceMutex.Lock()
 	defer w.sourceMutex.Unlock()
 
+	// Advance the seasonal food cycle
+	w.seasonTime += deltaTime
+
 	// Process each source
 	for i := range w.ChemicalSources {
 		// Skip inactive sources
@@ -553,16 +900,16 @@ func (w *World) UpdateChemicalSources(deltaTime float64, rng *rand.Rand) {
 			continue
 		}
 
-		// Remember energy before update
-		energyBefore := w.ChemicalSources[i].Energy
-
 		// Update the source
 		w.ChemicalSources[i].Update(deltaTime, &w.totalSystemEnergy)
 
-		// If energy changed significantly, invalidate the concentration grid
-		if math.Abs(energyBefore-w.ChemicalSources[i].Energy) > energyBefore*0.05 {
-			w.concentrationGrid = nil
-		}
+		// If energy changed significantly, invalidate the grid region around the source
+		w.syncGridSource(i)
+	}
+
+	// A finite environment keeps depleting but never renews
+	if !w.chemicalConfig.RegenerationEnabled {
+		return
 	}
 
 	// Check if we need to regenerate depleted sources
@@ -597,12 +944,12 @@ func (w *World) UpdateChemicalSources(deltaTime float64, rng *rand.Rand) {
 				// Update system energy
 				w.totalSystemEnergy += w.ChemicalSources[randomIndex].Energy
 
-				// Invalidate the concentration grid
-				w.concentrationGrid = nil
+				// Invalidate the grid region around the regenerated source
+				w.syncGridSource(randomIndex)
 			}
 		} else if len(w.ChemicalSources) < w.chemicalConfig.Count {
 			// Create a new source if we're below the target count
-			w.CreateChemicalSource(rng)
+			w.createChemicalSource(rng)
 		}
 	}
 }
@@ -610,6 +957,16 @@ func (w *World) UpdateChemicalSources(deltaTime float64, rng *rand.Rand) {
 // CreateChemicalSource creates a new chemical source at a random position
 // to maintain energy balance in the system
 func (w *World) CreateChemicalSource(rng *rand.Rand) {
+	w.sourceMutex.Lock()
+	defer w.sourceMutex.Unlock()
+
+	w.createChemicalSource(rng)
+}
+
+// createChemicalSource creates a new source like CreateChemicalSource, drawing all
+// of its randomness from rng so that seeded runs regenerate identically.
+// Caller must hold sourceMutex.
+func (w *World) createChemicalSource(rng *rand.Rand) {
 	// Calculate energy deficit in the system
 	energyDeficit := w.targetSystemEnergy - w.totalSystemEnergy
 
@@ -649,11 +1006,17 @@ func (w *World) CreateChemicalSource(rng *rand.Rand) {
 	)
 
 	// Add to the world
-	added := w.AddChemicalSource(source)
+	if !w.World.AddChemicalSource(source) {
+		return
+	}
+	w.totalSystemEnergy += source.Energy
 
-	// Update system energy if source was added successfully
-	if added {
-		w.totalSystemEnergy += source.Energy
+	// The grid tracks sources by index, so hand it the full set including the new one
+	w.gridMutex.RLock()
+	grid := w.concentrationGrid
+	w.gridMutex.RUnlock()
+	if grid != nil {
+		grid.SetSources(w.ChemicalSources)
 	}
 }
 
@@ -664,3 +1027,41 @@ func (w *World) GetSystemEnergyInfo() (float64, float64) {
 
 	return w.totalSystemEnergy, w.targetSystemEnergy
 }
+
+// Season returns the world's seasonal food cycle and the time elapsed in it
+func (w *World) Season() (types.SeasonalCycle, float64) {
+	w.sourceMutex.RLock()
+	defer w.sourceMutex.RUnlock()
+
+	return w.season, w.seasonTime
+}
+
+// HeatDeathEnergyFraction is the fraction of the target system energy below which
+// a world that can't regenerate its sources is considered dead
+const HeatDeathEnergyFraction = 0.01
+
+// HeatDeath reports whether the system energy has collapsed below
+// HeatDeathEnergyFraction of the target with no way to recover, either because
+// regeneration is disabled or because there are no sources left to regenerate
+func (w *World) HeatDeath() bool {
+	totalEnergy, targetEnergy := w.GetSystemEnergyInfo()
+	if totalEnergy > targetEnergy*HeatDeathEnergyFraction {
+		return false
+	}
+
+	return !w.canRegenerate()
+}
+
+// canRegenerate reports whether depleted sources can come back, either by
+// reactivating existing sources or by creating new ones
+func (w *World) canRegenerate() bool {
+	if !w.chemicalConfig.RegenerationEnabled || w.chemicalConfig.RegenerationProbability <= 0 {
+		return false
+	}
+
+	w.sourceMutex.RLock()
+	sourceCount := len(w.ChemicalSources)
+	w.sourceMutex.RUnlock()
+
+	return sourceCount > 0 || w.chemicalConfig.Count > 0
+}
@@ -1,8 +1,11 @@
 package world
 
 import (
+	"math"
 	"math/rand"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/zachbeta/evolve_sim/pkg/config"
 	"github.com/zachbeta/evolve_sim/pkg/types"
@@ -481,6 +484,7 @@ func TestUpdateChemicalSources(t *testing.T) {
 			MinDecayFactor:          0.001,
 			MaxDecayFactor:          0.01,
 			DepletionRate:           0.2,
+			RegenerationEnabled:     true,
 			RegenerationProbability: 1.0, // High probability for testing
 			TargetSystemEnergy:      100000,
 		},
@@ -551,3 +555,853 @@ func setupTestWorld() *World {
 
 	return NewWorld(cfg)
 }
+
+func TestBimodalPreferenceDistribution(t *testing.T) {
+	cfg := config.SimulationConfig{
+		World: config.WorldConfig{Width: 1000, Height: 1000},
+		Organism: config.OrganismConfig{
+			Count:                        400,
+			Speed:                        1.0,
+			PreferenceDistributionMean:   20.0,
+			PreferenceDistributionStdDev: 2.0,
+			PreferenceDistribution:       config.PreferenceDistributionBimodal,
+			SecondaryPreferenceMean:      80.0,
+			SecondaryPreferenceWeight:    0.5,
+		},
+		RandomSeed: 99,
+	}
+
+	world := NewWorld(cfg)
+
+	nearPrimary, nearSecondary, between := 0, 0, 0
+	for _, org := range world.GetOrganisms() {
+		switch {
+		case org.ChemPreference > 10 && org.ChemPreference < 30:
+			nearPrimary++
+		case org.ChemPreference > 70 && org.ChemPreference < 90:
+			nearSecondary++
+		default:
+			between++
+		}
+	}
+
+	// Both peaks should be well populated, with roughly half the organisms each
+	if nearPrimary < 150 || nearSecondary < 150 {
+		t.Errorf("Expected organisms clustered around both means, got %d near 20 and %d near 80",
+			nearPrimary, nearSecondary)
+	}
+
+	if between > 4 {
+		t.Errorf("Expected almost no organisms between the peaks, got %d", between)
+	}
+}
+
+func TestInitialHeadingDistribution(t *testing.T) {
+	newWorld := func(distribution string, angle float64) *World {
+		return NewWorld(config.SimulationConfig{
+			World: config.WorldConfig{Width: 400, Height: 400},
+			Organism: config.OrganismConfig{
+				Count:               25,
+				Speed:               1.0,
+				InitialHeading:      distribution,
+				InitialHeadingAngle: angle,
+			},
+			RandomSeed: 7,
+		})
+	}
+
+	t.Run("Fixed", func(t *testing.T) {
+		for _, org := range newWorld(config.InitialHeadingFixed, 1.25).GetOrganisms() {
+			if org.Heading != 1.25 {
+				t.Errorf("Organism at %v has heading %v; want 1.25", org.Position, org.Heading)
+			}
+		}
+	})
+
+	t.Run("Toward center", func(t *testing.T) {
+		center := types.Point{X: 200, Y: 200}
+		for _, org := range newWorld(config.InitialHeadingTowardCenter, 0).GetOrganisms() {
+			// Stepping along the heading must bring the organism closer to the center
+			ahead := types.Point{X: org.Position.X + math.Cos(org.Heading), Y: org.Position.Y + math.Sin(org.Heading)}
+			if ahead.DistanceTo(center) >= org.Position.DistanceTo(center) {
+				t.Errorf("Organism at %v heading %v does not face the center", org.Position, org.Heading)
+			}
+		}
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		headings := make(map[float64]bool)
+		for _, org := range newWorld(config.InitialHeadingRandom, 0).GetOrganisms() {
+			headings[org.Heading] = true
+		}
+		if len(headings) < 20 {
+			t.Errorf("Got %d distinct headings for 25 organisms; want them spread out", len(headings))
+		}
+	})
+}
+
+func TestGradientVectorMagnitude(t *testing.T) {
+	world := NewWorld(config.SimulationConfig{
+		World: config.WorldConfig{Width: 100.0, Height: 100.0},
+	})
+	world.AddChemicalSource(types.NewChemicalSource(types.NewPoint(50, 50), 100.0, 0.01))
+	world.InitializeConcentrationGrid(2.0)
+
+	length := func(p types.Point) float64 { return math.Sqrt(p.X*p.X + p.Y*p.Y) }
+
+	// The field is steeper on the flank of the peak than far out in the tail
+	flank := length(world.GetConcentrationGradientVectorAt(types.Point{X: 60, Y: 50}))
+	tail := length(world.GetConcentrationGradientVectorAt(types.Point{X: 95, Y: 50}))
+	if flank <= tail {
+		t.Errorf("Expected steeper gradient near the source (%v) than far away (%v)", flank, tail)
+	}
+
+	// The normalized gradient should still have unit length
+	if normalized := length(world.GetConcentrationGradientAt(types.Point{X: 60, Y: 50})); !approximatelyEqual(normalized, 1.0, 1e-9) {
+		t.Errorf("Normalized gradient length = %v; want 1", normalized)
+	}
+}
+
+func TestReproductionOrderNearCap(t *testing.T) {
+	// Offspring are born 5-10 units from their parent and parents are 20 apart,
+	// so each birth position identifies its parent by the nearest column
+	parentsFor := func(seed int64) []int {
+		world := NewWorld(config.SimulationConfig{
+			World:      config.WorldConfig{Width: 500, Height: 500},
+			RandomSeed: seed,
+		})
+
+		// Twenty organisms, all ready to reproduce
+		for i := 0; i < 20; i++ {
+			org := types.NewOrganism(types.Point{X: float64(10 + i*20), Y: 250}, 0, 50, 1.0, types.DefaultSensorAngles())
+			org.Energy = org.EnergyCapacity
+			org.TimeSinceReproduction = types.ReproductionCooldown
+			world.AddOrganism(org)
+		}
+
+		// Only room for five offspring
+		count, positions := world.ProcessReproductionWithConfig(config.ReproductionConfig{MaxPopulation: 25})
+		if count != 5 {
+			t.Fatalf("Expected 5 reproductions at the cap, got %d", count)
+		}
+		parents := make([]int, len(positions))
+		for i, pos := range positions {
+			parents[i] = int(math.Round((pos.X - 10) / 20))
+		}
+		return parents
+	}
+
+	first := parentsFor(42)
+
+	indexOrdered := true
+	for i, parent := range first {
+		if parent != i {
+			indexOrdered = false
+		}
+	}
+	if indexOrdered {
+		t.Errorf("Expected reproduction near the cap not to follow index order, got parents at %v", first)
+	}
+
+	// The same seed should pick the same parents
+	second := parentsFor(42)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("Parent %d differs between runs with the same seed: %v vs %v", i, first[i], second[i])
+		}
+	}
+}
+
+func TestReproductionReturnsBirthPositions(t *testing.T) {
+	world := NewWorld(config.SimulationConfig{
+		World:      config.WorldConfig{Width: 200, Height: 200},
+		RandomSeed: 3,
+	})
+
+	// Three organisms, all ready to reproduce
+	parents := []types.Point{{X: 50, Y: 100}, {X: 100, Y: 100}, {X: 150, Y: 100}}
+	for _, pos := range parents {
+		org := types.NewOrganism(pos, 0, 50, 1.0, types.DefaultSensorAngles())
+		org.Energy = org.EnergyCapacity
+		org.TimeSinceReproduction = types.ReproductionCooldown
+		world.AddOrganism(org)
+	}
+
+	count, positions := world.ProcessReproductionWithConfig(config.ReproductionConfig{MaxPopulation: 10})
+	if count != 3 {
+		t.Fatalf("Expected 3 reproductions, got %d", count)
+	}
+	if len(positions) != count {
+		t.Fatalf("Got %d birth positions for %d reproductions", len(positions), count)
+	}
+
+	// Each position is where an offspring actually is, not where its parent stands
+	organisms := world.GetOrganisms()
+	for _, pos := range positions {
+		if !world.Boundaries.Contains(pos) {
+			t.Errorf("Birth position %v lies outside the world", pos)
+		}
+		for _, parent := range parents {
+			if pos == parent {
+				t.Errorf("Birth position %v is the parent's position", pos)
+			}
+		}
+		found := false
+		for _, org := range organisms[len(parents):] {
+			if org.Position == pos {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("No offspring at birth position %v", pos)
+		}
+	}
+}
+
+func TestMaxReproductionsPerStep(t *testing.T) {
+	world := NewWorld(config.SimulationConfig{
+		World:      config.WorldConfig{Width: 500, Height: 500},
+		RandomSeed: 1,
+	})
+
+	// Ten organisms, all ready to reproduce
+	for i := 0; i < 10; i++ {
+		org := types.NewOrganism(types.Point{X: float64(25 + i*40), Y: 250}, 0, 50, 1.0, types.DefaultSensorAngles())
+		org.Energy = org.EnergyCapacity
+		org.TimeSinceReproduction = types.ReproductionCooldown
+		world.AddOrganism(org)
+	}
+
+	cfg := config.ReproductionConfig{MaxPopulation: 100, MaxReproductionsPerStep: 1}
+	for step := 0; step < 3; step++ {
+		before := len(world.GetOrganisms())
+		count, positions := world.ProcessReproductionWithConfig(cfg)
+		if count != 1 || len(positions) != 1 {
+			t.Errorf("Step %d: %d reproductions; want exactly 1", step, count)
+		}
+		if after := len(world.GetOrganisms()); after != before+1 {
+			t.Errorf("Step %d: population went from %d to %d; want one birth", step, before, after)
+		}
+	}
+}
+
+func TestInitialEnergyFraction(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.World = config.WorldConfig{Width: 200, Height: 200}
+	cfg.Organism.Count = 10
+	cfg.RandomSeed = 5
+
+	world := NewWorld(cfg)
+	for _, org := range world.GetOrganisms() {
+		want := org.EnergyCapacity * cfg.Energy.InitialEnergy
+		if !approximatelyEqual(org.Energy, want, 1e-9) {
+			t.Errorf("Organism energy = %v; want %v (%.0f%% of capacity %v)",
+				org.Energy, want, cfg.Energy.InitialEnergy*100, org.EnergyCapacity)
+		}
+		if org.Energy > org.EnergyCapacity {
+			t.Errorf("Organism starts with %v energy, above its capacity %v", org.Energy, org.EnergyCapacity)
+		}
+	}
+}
+
+func TestHeatDeath(t *testing.T) {
+	newDrainingWorld := func(regeneration float64) *World {
+		return NewWorld(config.SimulationConfig{
+			World: config.WorldConfig{Width: 500, Height: 500},
+			Chemical: config.ChemicalConfig{
+				Count:                   3,
+				MinStrength:             100,
+				MaxStrength:             200,
+				MinDecayFactor:          0.001,
+				MaxDecayFactor:          0.01,
+				RegenerationEnabled:     true,
+				RegenerationProbability: regeneration,
+			},
+		})
+	}
+
+	// drain removes all energy from the world's sources
+	drain := func(world *World) {
+		for i, source := range world.GetChemicalSources() {
+			for n := 0; n < 100 && world.GetChemicalSources()[i].IsActive; n++ {
+				world.DepleteEnergyFromSourcesAt(source.Position, source.MaxEnergy)
+			}
+		}
+	}
+
+	t.Run("Regeneration off", func(t *testing.T) {
+		world := newDrainingWorld(0)
+		if world.HeatDeath() {
+			t.Fatal("Expected a freshly populated world to be alive")
+		}
+
+		drain(world)
+		totalEnergy, targetEnergy := world.GetSystemEnergyInfo()
+		if totalEnergy > targetEnergy*HeatDeathEnergyFraction {
+			t.Fatalf("Test setup failed: energy %v of %v after draining", totalEnergy, targetEnergy)
+		}
+		if !world.HeatDeath() {
+			t.Error("Expected heat death once energy collapsed with regeneration off")
+		}
+	})
+
+	t.Run("Regeneration on", func(t *testing.T) {
+		world := newDrainingWorld(0.5)
+		drain(world)
+		if world.HeatDeath() {
+			t.Error("Expected no heat death while depleted sources can regenerate")
+		}
+	})
+
+	t.Run("Default config while sources hold energy", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		cfg.RandomSeed = 7
+		cfg.Chemical.RegenerationEnabled = false
+		world := NewWorld(cfg)
+
+		// Decay plus steady grazing at every source, a small fraction of what they hold
+		rng := rand.New(rand.NewSource(cfg.RandomSeed))
+		for step := 0; step < 1000; step++ {
+			world.UpdateChemicalSources(1.0/60.0, rng)
+			for _, source := range world.GetChemicalSources() {
+				world.DepleteEnergyFromSourcesAt(source.Position, 0.1)
+			}
+			if world.HeatDeath() {
+				live := 0.0
+				for _, info := range world.ChemicalEnergyBreakdown() {
+					live += info.Energy
+				}
+				t.Fatalf("Heat death at step %d with %v energy still in the sources", step, live)
+			}
+		}
+	})
+
+	t.Run("Sources removed", func(t *testing.T) {
+		world := newDrainingWorld(0.5)
+		world.chemicalConfig.Count = 0
+		world.ApplyScenario(Scenario{Organisms: world.GetOrganisms()})
+		if !world.HeatDeath() {
+			t.Error("Expected heat death with no sources left to regenerate")
+		}
+	})
+}
+
+func TestPlacementDeterminism(t *testing.T) {
+	cfg := config.SimulationConfig{
+		World: config.WorldConfig{Width: 800, Height: 600},
+		Organism: config.OrganismConfig{
+			Count:                        40,
+			Speed:                        1.0,
+			PreferenceDistributionMean:   50.0,
+			PreferenceDistributionStdDev: 10.0,
+		},
+		Chemical: config.ChemicalConfig{
+			Count:          5,
+			MinStrength:    100,
+			MaxStrength:    200,
+			MinDecayFactor: 0.001,
+			MaxDecayFactor: 0.01,
+		},
+		RandomSeed: 1234,
+	}
+
+	first := NewWorld(cfg)
+	second := NewWorld(cfg)
+
+	firstOrgs, secondOrgs := first.GetOrganisms(), second.GetOrganisms()
+	if len(firstOrgs) != cfg.Organism.Count || len(secondOrgs) != cfg.Organism.Count {
+		t.Fatalf("Populated %d and %d organisms; want %d", len(firstOrgs), len(secondOrgs), cfg.Organism.Count)
+	}
+	for i := range firstOrgs {
+		if firstOrgs[i].Position != secondOrgs[i].Position {
+			t.Errorf("Organism %d placed at %v and %v with the same seed", i, firstOrgs[i].Position, secondOrgs[i].Position)
+		}
+	}
+
+	firstSources, secondSources := first.GetChemicalSources(), second.GetChemicalSources()
+	for i := range firstSources {
+		if firstSources[i].Position != secondSources[i].Position {
+			t.Errorf("Source %d placed at %v and %v with the same seed", i, firstSources[i].Position, secondSources[i].Position)
+		}
+	}
+
+	// Resetting with the same seed reproduces the layout too
+	first.Reset(cfg)
+	for i, org := range first.GetOrganisms() {
+		if org.Position != secondOrgs[i].Position {
+			t.Errorf("Organism %d placed at %v after reset; want %v", i, org.Position, secondOrgs[i].Position)
+		}
+	}
+
+	// A different seed jitters organisms differently
+	cfg.RandomSeed = 4321
+	other := NewWorld(cfg).GetOrganisms()
+	moved := 0
+	for i := range other {
+		if other[i].Position != secondOrgs[i].Position {
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Error("Expected a different seed to change organism placement")
+	}
+}
+
+func TestRegenerationDisabled(t *testing.T) {
+	cfg := config.SimulationConfig{
+		World: config.WorldConfig{Width: 500, Height: 500},
+		Chemical: config.ChemicalConfig{
+			Count:                   3,
+			MinStrength:             100,
+			MaxStrength:             200,
+			MinDecayFactor:          0.001,
+			MaxDecayFactor:          0.01,
+			RegenerationEnabled:     false,
+			RegenerationProbability: 1.0, // Would regenerate every step if enabled
+		},
+		RandomSeed: 3,
+	}
+
+	world := NewWorld(cfg)
+	rng := rand.New(rand.NewSource(42))
+	sourceCount := len(world.GetChemicalSources())
+
+	allInactive := func() bool {
+		for _, source := range world.GetChemicalSources() {
+			if source.IsActive {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Depletion still runs until every source is exhausted
+	for i := 0; i < 10000 && !allInactive(); i++ {
+		world.UpdateChemicalSources(10.0, rng)
+	}
+	if !allInactive() {
+		t.Fatal("Expected sources to deplete with regeneration disabled")
+	}
+
+	// Nothing comes back, no matter how long the world runs
+	for i := 0; i < 100; i++ {
+		world.UpdateChemicalSources(10.0, rng)
+		if !allInactive() {
+			t.Fatalf("A depleted source reactivated after %d updates", i+1)
+		}
+		if got := len(world.GetChemicalSources()); got != sourceCount {
+			t.Fatalf("Source count changed from %d to %d after %d updates", sourceCount, got, i+1)
+		}
+	}
+
+	if !world.HeatDeath() {
+		t.Error("Expected heat death once a non-renewing world is exhausted")
+	}
+}
+
+func TestReproductionEnvironmentCost(t *testing.T) {
+	const cost = 500.0
+	cfg := config.ReproductionConfig{MaxPopulation: 100, EnvironmentCost: cost}
+
+	// newReadyOrganism returns an organism at position that is ready to reproduce
+	newReadyOrganism := func(position types.Point) types.Organism {
+		org := types.NewOrganism(position, 0, 50.0, 1.0, types.DefaultSensorAngles())
+		org.Energy = org.EnergyCapacity
+		org.TimeSinceReproduction = types.ReproductionCooldown
+		return org
+	}
+
+	// newWorldWithSource returns a world with one short-range source near the origin
+	newWorldWithSource := func() *World {
+		w := setupTestWorld()
+		w.AddChemicalSource(types.NewChemicalSource(types.Point{X: 100, Y: 100}, 100, 1.0))
+		w.InitializeConcentrationGrid(10.0)
+		w.totalSystemEnergy = w.GetChemicalSources()[0].Energy
+		return w
+	}
+
+	t.Run("Rich area", func(t *testing.T) {
+		w := newWorldWithSource()
+		w.AddOrganism(newReadyOrganism(types.Point{X: 105, Y: 100}))
+		sourceBefore := w.GetChemicalSources()[0].Energy
+		systemBefore, _ := w.GetSystemEnergyInfo()
+
+		count, _ := w.ProcessReproductionWithConfig(cfg)
+		if count != 1 {
+			t.Fatalf("Reproductions in a rich area = %d; want 1", count)
+		}
+
+		sourceAfter := w.GetChemicalSources()[0].Energy
+		if !approximatelyEqual(sourceBefore-sourceAfter, cost, 1e-6) {
+			t.Errorf("Source lost %v energy; want %v", sourceBefore-sourceAfter, cost)
+		}
+		systemAfter, _ := w.GetSystemEnergyInfo()
+		if !approximatelyEqual(systemBefore-systemAfter, cost, 1e-6) {
+			t.Errorf("System energy dropped by %v; want %v", systemBefore-systemAfter, cost)
+		}
+	})
+
+	t.Run("Barren area", func(t *testing.T) {
+		w := newWorldWithSource()
+		parent := newReadyOrganism(types.Point{X: 900, Y: 900}) // Out of the source's reach
+		w.AddOrganism(parent)
+		sourceBefore := w.GetChemicalSources()[0].Energy
+
+		count, _ := w.ProcessReproductionWithConfig(cfg)
+		if count != 0 {
+			t.Fatalf("Reproductions in a barren area = %d; want 0", count)
+		}
+		if got := w.GetOrganisms()[0].Energy; got != parent.Energy {
+			t.Errorf("Parent energy changed from %v to %v without a birth", parent.Energy, got)
+		}
+		if got := w.GetChemicalSources()[0].Energy; got != sourceBefore {
+			t.Errorf("Source energy changed from %v to %v without a birth", sourceBefore, got)
+		}
+	})
+
+	t.Run("Sources can't cover the cost", func(t *testing.T) {
+		w := newWorldWithSource()
+		w.ChemicalSources[0].Energy = cost / 2
+		w.AddOrganism(newReadyOrganism(types.Point{X: 105, Y: 100}))
+
+		if count, _ := w.ProcessReproductionWithConfig(cfg); count != 0 {
+			t.Errorf("Reproductions with a depleted source = %d; want 0", count)
+		}
+	})
+}
+
+func TestMaxConcentration(t *testing.T) {
+	cfg := config.SimulationConfig{
+		World:    config.WorldConfig{Width: 100, Height: 100},
+		Chemical: config.ChemicalConfig{MaxConcentration: 150},
+	}
+	world := NewWorld(cfg)
+
+	// Two overlapping sources sum to 200 at their shared center
+	center := types.Point{X: 50, Y: 50}
+	for i := 0; i < 2; i++ {
+		world.AddChemicalSource(types.NewChemicalSource(center, 100, 0.01))
+	}
+
+	check := func(name string) {
+		if got := world.GetConcentrationAt(center); got != 150 {
+			t.Errorf("%s: concentration at overlapping sources = %v; want the cap 150", name, got)
+		}
+		if got := world.Snapshot().GetConcentrationAt(center); got != 150 {
+			t.Errorf("%s: snapshot concentration = %v; want the cap 150", name, got)
+		}
+
+		// Away from the peak the field is below the cap and unaffected
+		far := types.Point{X: 0, Y: 0}
+		if got, want := world.GetConcentrationAt(far), 2*types.NewChemicalSource(center, 100, 0.01).GetConcentrationAt(far); math.Abs(got-want) > 1e-6 {
+			t.Errorf("%s: concentration below the cap = %v; want %v", name, got, want)
+		}
+	}
+
+	check("direct")
+	world.InitializeConcentrationGrid(10.0)
+	check("grid")
+	if got := world.GetExactConcentrationAt(center); got != 150 {
+		t.Errorf("Exact concentration = %v; want the cap 150", got)
+	}
+}
+
+func TestRemoveDeadOrganismsDetailed(t *testing.T) {
+	w := NewWorld(config.SimulationConfig{
+		World: config.WorldConfig{Width: 200, Height: 200},
+	})
+
+	alive := types.NewOrganism(types.Point{X: 20, Y: 20}, 0, 30, 1.0, types.DefaultSensorAngles())
+	alive.Energy = alive.EnergyCapacity
+	w.AddOrganism(alive)
+
+	// Two dead organisms on either side of a living one, so filtering in place
+	// overwrites a slot before the scan is finished
+	first := types.NewOrganism(types.Point{X: 40, Y: 60}, 0, 30, 1.0, types.DefaultSensorAngles())
+	first.Energy = 0
+	first.Generation = 3
+	second := types.NewOrganism(types.Point{X: 150, Y: 90}, 0, 30, 1.0, types.DefaultSensorAngles())
+	second.Energy = 0
+	second.Generation = 7
+	w.Organisms = []types.Organism{first, alive, second}
+
+	removed := w.RemoveDeadOrganismsDetailed()
+	if len(removed) != 2 {
+		t.Fatalf("Removed %d organisms; want 2", len(removed))
+	}
+	for i, want := range []types.Organism{first, second} {
+		got := removed[i]
+		if got.ID != want.ID || got.Position != want.Position || got.Generation != want.Generation {
+			t.Errorf("Removed[%d] = ID %d at %v, generation %d; want ID %d at %v, generation %d",
+				i, got.ID, got.Position, got.Generation, want.ID, want.Position, want.Generation)
+		}
+	}
+
+	orgs := w.GetOrganisms()
+	if len(orgs) != 1 || orgs[0].ID != alive.ID {
+		t.Errorf("Expected only the living organism to remain, got %d organisms", len(orgs))
+	}
+	if removed := w.RemoveDeadOrganismsDetailed(); len(removed) != 0 {
+		t.Errorf("Removed %d organisms from a living population; want 0", len(removed))
+	}
+}
+
+func TestReusedOrganismStorageHasNoStaleData(t *testing.T) {
+	w := NewWorld(config.SimulationConfig{
+		World: config.WorldConfig{Width: 200, Height: 200},
+	})
+
+	// A dying organism with plenty of state, followed by a parent ready to reproduce
+	dying := types.NewOrganism(types.Point{X: 50, Y: 50}, 0, 80, 1.0, types.DefaultSensorAngles())
+	dying.Energy = 0
+	dying.FeedingMemorySize = 3
+	dying.FeedingMemory = []types.Point{{X: 1, Y: 1}, {X: 2, Y: 2}}
+	dying.DispersalTime = 5
+	dying.Dormant = true
+	w.AddOrganism(dying)
+
+	parent := types.NewOrganism(types.Point{X: 100, Y: 100}, 0, 30, 1.0, types.DefaultSensorAngles())
+	parent.Energy = parent.EnergyCapacity
+	parent.TimeSinceReproduction = 100
+	w.AddOrganism(parent)
+
+	// Copies handed out earlier must not change when the world reuses its storage
+	before := w.GetOrganisms()
+
+	if removed := w.RemoveDeadOrganisms(); removed != 1 {
+		t.Fatalf("RemoveDeadOrganisms() = %d; want 1", removed)
+	}
+
+	// The slot the dead organism vacated must be cleared, not left holding it
+	w.organismMutex.RLock()
+	for _, org := range w.Organisms[len(w.Organisms):cap(w.Organisms)] {
+		if org.ID != 0 || org.FeedingMemory != nil || org.PositionHistory != nil {
+			t.Errorf("Vacated slot still holds organism %d", org.ID)
+		}
+	}
+	w.organismMutex.RUnlock()
+
+	if count, _ := w.ProcessReproductionWithConfig(config.ReproductionConfig{MaxPopulation: 10}); count != 1 {
+		t.Fatalf("ProcessReproductionWithConfig() = %d; want 1", count)
+	}
+
+	orgs := w.GetOrganisms()
+	if len(orgs) != 2 {
+		t.Fatalf("Got %d organisms; want parent and offspring", len(orgs))
+	}
+	offspring := orgs[1]
+	if offspring.ParentID != parent.ID || offspring.ChemPreference == dying.ChemPreference {
+		t.Errorf("Offspring = %+v; want a child of the parent", offspring)
+	}
+	if len(offspring.FeedingMemory) != 0 || offspring.DispersalTime != 0 || offspring.Dormant {
+		t.Errorf("Offspring inherited stale state: memory %v, dispersal %v, dormant %v",
+			offspring.FeedingMemory, offspring.DispersalTime, offspring.Dormant)
+	}
+
+	if before[0].ID != dying.ID || before[1].ID != parent.ID {
+		t.Errorf("Earlier copy changed to IDs %d, %d; want %d, %d", before[0].ID, before[1].ID, dying.ID, parent.ID)
+	}
+}
+
+// BenchmarkPopulationTurnover measures allocations for a reproduction-heavy step:
+// organisms are updated, a tenth of them die and the survivors reproduce
+func BenchmarkPopulationTurnover(b *testing.B) {
+	w := NewWorld(config.SimulationConfig{
+		World: config.WorldConfig{Width: 500, Height: 500},
+	})
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		w.AddOrganism(types.NewOrganism(
+			types.Point{X: 50 + rng.Float64()*400, Y: 50 + rng.Float64()*400},
+			0, 50, 1.0, types.DefaultSensorAngles(),
+		))
+	}
+	cfg := config.ReproductionConfig{MaxPopulation: 220}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		organisms := w.GetOrganisms()
+		for i := range organisms {
+			organisms[i].Energy = organisms[i].EnergyCapacity
+			organisms[i].TimeSinceReproduction = 100
+			if i%10 == n%10 {
+				organisms[i].Energy = 0
+			}
+		}
+		w.UpdateOrganisms(organisms)
+		w.RemoveDeadOrganisms()
+		w.ProcessReproductionWithConfig(cfg)
+	}
+}
+
+// TestConcurrentSourceEditsAndLookups adds sources while other goroutines query
+// concentrations and fetch the grid. Run with -race to check the grid's
+// invalidate-and-rebuild path for data races; a lock-order mistake shows up as a
+// timeout.
+func TestConcurrentSourceEditsAndLookups(t *testing.T) {
+	world := NewWorld(config.SimulationConfig{
+		World: config.WorldConfig{Width: 200, Height: 200},
+		Chemical: config.ChemicalConfig{
+			Count:          2,
+			MinStrength:    100,
+			MaxStrength:    200,
+			MinDecayFactor: 0.001,
+			MaxDecayFactor: 0.01,
+		},
+		RandomSeed: 5,
+	})
+
+	const adds, readers = 50, 4
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var wg sync.WaitGroup
+		wg.Add(1 + readers)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < adds; i++ {
+				world.AddChemicalSource(types.NewChemicalSource(types.Point{X: float64(i * 4), Y: 100}, 50, 0.01))
+			}
+		}()
+		for r := 0; r < readers; r++ {
+			go func(r int) {
+				defer wg.Done()
+				for i := 0; i < adds*4; i++ {
+					p := types.Point{X: float64((i*7 + r*13) % 200), Y: float64((i*11 + r*3) % 200)}
+					world.GetConcentrationAt(p)
+					world.GetConcentrationGradientVectorAt(p)
+					if r%2 == 0 && world.GetConcentrationGrid() == nil {
+						t.Error("GetConcentrationGrid() = nil; want a rebuilt grid")
+					}
+				}
+			}(r)
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Concurrent source edits and lookups deadlocked")
+	}
+
+	// Once the edits stop, the rebuilt grid must know every source
+	grid := world.GetConcentrationGrid()
+	if got, want := len(grid.Sources), len(world.GetChemicalSources()); got != want {
+		t.Errorf("Rebuilt grid has %d sources; want %d", got, want)
+	}
+	p := types.Point{X: 100, Y: 100}
+	if got, want := world.GetConcentrationAt(p), world.GetExactConcentrationAt(p); math.Abs(got-want) > want*0.05+1e-9 {
+		t.Errorf("Concentration after edits = %v; want about %v from the sources", got, want)
+	}
+}
+
+func TestChemicalEnergyBreakdown(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Organism.Count = 0
+	cfg.Chemical.Count = 4
+	w := NewWorld(cfg)
+
+	// Give one source a second chemical type, drain another and partly drain a third
+	w.sourceMutex.Lock()
+	w.ChemicalSources[3].Type = 1
+	w.depleteSource(0, w.ChemicalSources[0].Energy)
+	w.depleteSource(1, w.ChemicalSources[1].Energy/2)
+	w.sourceMutex.Unlock()
+
+	breakdown := w.ChemicalEnergyBreakdown()
+	if len(breakdown) != 2 {
+		t.Fatalf("Breakdown has %d chemical types; want 2", len(breakdown))
+	}
+
+	want := map[int][2]int{types.DefaultChemicalType: {2, 1}, 1: {1, 0}}
+	total := 0.0
+	for chemicalType, info := range breakdown {
+		if counts := [2]int{info.ActiveSources, info.DepletedSources}; counts != want[chemicalType] {
+			t.Errorf("Type %d active/depleted sources = %v; want %v", chemicalType, counts, want[chemicalType])
+		}
+		total += info.Energy
+	}
+
+	systemEnergy, _ := w.GetSystemEnergyInfo()
+	if !approximatelyEqual(total, systemEnergy, 1e-6) {
+		t.Errorf("Breakdown energy sums to %.4f; want the system total %.4f", total, systemEnergy)
+	}
+}
+
+func TestReproductionRequiresMate(t *testing.T) {
+	cfg := config.ReproductionConfig{MaxPopulation: 100, RequireMate: true, MateRadius: 20}
+
+	// newReadyOrganism returns an organism at position that is ready to reproduce
+	newReadyOrganism := func(x, y float64) types.Organism {
+		org := types.NewOrganism(types.Point{X: x, Y: y}, 0, 50.0, 1.0, types.DefaultSensorAngles())
+		org.Energy = org.EnergyCapacity
+		org.TimeSinceReproduction = types.ReproductionCooldown
+		return org
+	}
+
+	tests := []struct {
+		name      string
+		organisms func() []types.Organism
+		want      int
+	}{
+		{"Isolated", func() []types.Organism {
+			return []types.Organism{newReadyOrganism(100, 100), newReadyOrganism(500, 500)}
+		}, 0},
+		{"Neighbor not ready", func() []types.Organism {
+			neighbor := newReadyOrganism(110, 100)
+			neighbor.Energy = 0
+			return []types.Organism{newReadyOrganism(100, 100), neighbor}
+		}, 0},
+		{"Ready neighbor in range", func() []types.Organism {
+			return []types.Organism{newReadyOrganism(100, 100), newReadyOrganism(110, 100), newReadyOrganism(500, 500)}
+		}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := setupTestWorld()
+			for _, org := range tt.organisms() {
+				w.AddOrganism(org)
+			}
+
+			if count, _ := w.ProcessReproductionWithConfig(cfg); count != tt.want {
+				t.Errorf("Births = %d; want %d", count, tt.want)
+			}
+		})
+	}
+
+	t.Run("Isolated organism reproduces without the flag", func(t *testing.T) {
+		w := setupTestWorld()
+		w.AddOrganism(newReadyOrganism(100, 100))
+
+		if count, _ := w.ProcessReproductionWithConfig(config.ReproductionConfig{MaxPopulation: 100}); count != 1 {
+			t.Errorf("Births = %d; want 1", count)
+		}
+	})
+}
+
+func TestFailedReproductionCostsParentNothing(t *testing.T) {
+	// Offspring land 5-10 units from the parent, always outside a world this small
+	w := NewWorld(config.SimulationConfig{World: config.WorldConfig{Width: 4, Height: 4}})
+	parent := types.NewOrganism(types.Point{X: 2, Y: 2}, 0, 50.0, 1.0, types.DefaultSensorAngles())
+	parent.Energy = parent.EnergyCapacity
+	parent.TimeSinceReproduction = types.ReproductionCooldown + 1
+	w.AddOrganism(parent)
+
+	for step := 0; step < 3; step++ {
+		if count, _ := w.ProcessReproductionWithConfig(config.ReproductionConfig{MaxPopulation: 10}); count != 0 {
+			t.Fatalf("Births = %d; want none to fit in the world", count)
+		}
+	}
+
+	after := w.GetOrganisms()[0]
+	if after.Energy != parent.Energy {
+		t.Errorf("Parent energy = %v after failed births; want %v", after.Energy, parent.Energy)
+	}
+	if after.TimeSinceReproduction != parent.TimeSinceReproduction {
+		t.Errorf("Parent time since reproduction = %v after failed births; want %v", after.TimeSinceReproduction, parent.TimeSinceReproduction)
+	}
+}